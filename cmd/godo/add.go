@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/wraient/godo/internal"
+)
+
+// runAdd creates a task from its arguments, or with "-" one task per line
+// of stdin, so godo can sit at the end of a pipeline. With --template it
+// creates the template's task tree instead.
+func runAdd(args []string) error {
+	fs := flag.NewFlagSet("add", flag.ExitOnError)
+	parent := fs.String("parent", "", "ID of the list or task to add to")
+	template := fs.String("template", "", "Name of a template to create the task from")
+	fs.Parse(args)
+
+	if *template != "" {
+		return addFromTemplate(*template, *parent)
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: godo add [--parent=<id>] <title>|- or godo add --template=<name>")
+	}
+
+	var lines []string
+	if fs.NArg() == 1 && fs.Arg(0) == "-" {
+		var err error
+		if lines, err = readLines(os.Stdin); err != nil {
+			return fmt.Errorf("error reading stdin: %v", err)
+		}
+	} else {
+		lines = []string{strings.Join(fs.Args(), " ")}
+	}
+
+	store := internal.DefaultStore()
+	added := 0
+	for _, line := range lines {
+		task := internal.ParseQuickAdd(line)
+		task.Parent = *parent
+		if _, err := store.Add(task); err != nil {
+			return fmt.Errorf("added %d task(s), then failed on %q: %v", added, line, err)
+		}
+		added++
+	}
+	fmt.Printf("Added %d task(s)\n", added)
+	return nil
+}
+
+// addFromTemplate creates the task tree of the named template
+func addFromTemplate(name, parent string) error {
+	task, err := internal.NewTaskFromTemplate(name, time.Now())
+	if err != nil {
+		return err
+	}
+	task.Parent = parent
+	created, err := internal.DefaultStore().Add(task)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Added %s from template %s\n", created.Title, name)
+	return nil
+}
+
+// readLines returns the non-empty lines of r with surrounding space trimmed
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
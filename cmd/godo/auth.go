@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/wraient/godo/internal"
+)
+
+// runAuth repeats the Google sign-in, for tokens missing the Tasks scope.
+// auth status reports on the stored token and auth reset deletes it first.
+func runAuth(args []string) error {
+	if len(args) == 0 {
+		if err := internal.Reauthenticate(); err != nil {
+			return err
+		}
+		fmt.Println("Successfully authenticated with Google!")
+		return nil
+	}
+
+	switch args[0] {
+	case "status":
+		return authStatus()
+	case "reset":
+		return authReset(args[1:])
+	}
+	return fmt.Errorf("usage: godo auth [status | reset [--yes]]")
+}
+
+// authStatus prints whether a usable token is stored and when it expires
+func authStatus() error {
+	status, err := internal.GoogleTokenInfo()
+	if err != nil {
+		return fmt.Errorf("%v\nRun godo auth reset to sign in again", err)
+	}
+	fmt.Printf("Token file: %s\n", status.Path)
+	if !status.Exists {
+		fmt.Println("Not signed in; run godo auth to sign in")
+		return nil
+	}
+
+	switch {
+	case status.Valid && status.Expiry.IsZero():
+		fmt.Println("Signed in, the token doesn't expire")
+	case status.Valid:
+		fmt.Printf("Signed in, the access token expires %s\n", status.Expiry.Local().Format(time.RFC1123))
+	case status.RefreshErr != nil:
+		fmt.Printf("The token expired and can't be refreshed: %v\n", status.RefreshErr)
+		fmt.Println("Run godo auth reset to sign in again")
+	default:
+		fmt.Println("The token expired and has no refresh token")
+		fmt.Println("Run godo auth reset to sign in again")
+	}
+	if status.Valid && status.Refresh {
+		fmt.Println("A refresh token is stored, so the access token is renewed as needed")
+	}
+	return nil
+}
+
+// authReset deletes the stored token, after confirming, and signs in again
+func authReset(args []string) error {
+	fs := flag.NewFlagSet("auth reset", flag.ExitOnError)
+	yes := fs.Bool("yes", false, "Don't ask for confirmation")
+	fs.Parse(args)
+
+	if !*yes {
+		fmt.Printf("Delete the Google token at %s and sign in again? [y/N] ", internal.GoogleTokenPath())
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+			fmt.Println("Nothing deleted")
+			return nil
+		}
+	}
+
+	if err := internal.ResetGoogleToken(); err != nil {
+		return err
+	}
+	fmt.Println("Successfully authenticated with Google!")
+	return nil
+}
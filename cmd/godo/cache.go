@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/wraient/godo/internal"
+)
+
+// runCache manages the Google Tasks cache; clear deletes it and, when
+// Google is in use, fetches the tasks again right away
+func runCache(args []string) error {
+	if len(args) != 1 || args[0] != "clear" {
+		return fmt.Errorf("usage: godo cache clear")
+	}
+
+	if err := internal.ClearGoogleCache(); err != nil {
+		return err
+	}
+	fmt.Println("Cleared the Google Tasks cache")
+
+	if !internal.UseGoogleTasks.Load() {
+		return nil
+	}
+	lists, err := internal.RefreshGoogleCache()
+	if err != nil {
+		return fmt.Errorf("error fetching from Google: %v", err)
+	}
+	fmt.Printf("Fetched %d task list(s) from Google Tasks\n", lists)
+	return nil
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wraient/godo/internal"
+)
+
+// runCapture adds its arguments as one task to the Inbox list
+func runCapture(args []string) error {
+	text := strings.TrimSpace(strings.Join(args, " "))
+	if text == "" {
+		return fmt.Errorf("usage: godo capture <text>")
+	}
+	task, err := internal.Capture(text)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Captured %q in %s\n", task.Title, internal.InboxTitle)
+	return nil
+}
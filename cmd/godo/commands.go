@@ -0,0 +1,71 @@
+package main
+
+// commandFlag describes a subcommand flag for help and shell completion
+type commandFlag struct {
+	Name string
+	Desc string
+}
+
+// command is a godo subcommand. Subcommands parse their own flags; Flags
+// only lists them so shell completion can offer them.
+type command struct {
+	Name     string
+	Desc     string
+	Flags    []commandFlag
+	Run      func(args []string) error
+	ErrMsg   string // Prefix for errors returned by Run
+	NoSetup  bool   // Runs before the config is loaded and Google is connected
+	NoGoogle bool   // Loads the config but doesn't connect to Google, e.g. to fix a broken token
+}
+
+// globalFlags are the flags accepted before the subcommand
+var globalFlags = []commandFlag{
+	{Name: "google", Desc: "Use Google Tasks for storage"},
+	{Name: "caldav", Desc: "Use the CalDAV server set in the config for storage"},
+	{Name: "debug", Desc: "Write debug messages to godo.log"},
+	{Name: "profile", Desc: "Use the named local task file, tasks-<name>.json"},
+	{Name: "account", Desc: "Use the named Google account, each signed in with its own token"},
+}
+
+// commands lists every subcommand; without one godo opens the TUI. It is
+// filled in by init because the completion command reads it.
+var commands []command
+
+func init() {
+	commands = []command{
+		{Name: "serve", Desc: "Serve tasks over a local HTTP/JSON API", Run: runServe, ErrMsg: "Error running server",
+			Flags: []commandFlag{{Name: "addr", Desc: "Address to bind the API server to"}}},
+		{Name: "summary", Desc: "Print counts of due, overdue and active tasks", Run: runSummary, ErrMsg: "Error summarizing tasks",
+			Flags: []commandFlag{{Name: "format", Desc: "Output format (plain or json)"}}},
+		{Name: "add", Desc: "Add tasks; - reads one task per line from stdin", Run: runAdd, ErrMsg: "Error adding tasks",
+			Flags: []commandFlag{{Name: "parent", Desc: "ID of the list or task to add to"}, {Name: "template", Desc: "Name of a template to create the task from"}}},
+		{Name: "capture", Desc: "Add a task to the Inbox list, creating it if needed", Run: runCapture, ErrMsg: "Error capturing task"},
+		{Name: "done", Desc: "Complete a task by ID or title", Run: runDone, ErrMsg: "Error completing task"},
+		{Name: "export", Desc: "Export all tasks", Run: runExport, ErrMsg: "Error exporting tasks",
+			Flags: []commandFlag{{Name: "format", Desc: "Format to export (csv)"}, {Name: "output", Desc: "File to write to instead of stdout"}, {Name: "list", Desc: "ID or name of the list, or ID of the task, to export"}}},
+		{Name: "import", Desc: "Import tasks from another app", Run: runImport, ErrMsg: "Error importing tasks",
+			Flags: []commandFlag{{Name: "format", Desc: "Format of the file to import (todoist)"}}},
+		{Name: "migrate", Desc: "Push all local tasks to Google Tasks", Run: runMigrate, ErrMsg: "Error migrating tasks",
+			Flags: []commandFlag{{Name: "to-google", Desc: "Push the local tasks to Google Tasks"}, {Name: "list", Desc: "Google list to put every task in"}, {Name: "switch", Desc: "Use Google Tasks by default afterwards"}}},
+		{Name: "dedupe", Desc: "Remove duplicate tasks, keeping the oldest", Run: runDedupe, ErrMsg: "Error removing duplicates",
+			Flags: []commandFlag{{Name: "yes", Desc: "Don't ask for confirmation"}}},
+		{Name: "init", Desc: "Set up the storage location, date format and theme", Run: runInit, ErrMsg: "Error setting up godo"},
+		{Name: "cache", Desc: "Clear the Google Tasks cache with cache clear", Run: runCache, ErrMsg: "Error clearing cache"},
+		{Name: "config", Desc: "Open the config file in $EDITOR with config edit", Run: runConfig, ErrMsg: "Error editing config", NoSetup: true},
+		{Name: "profiles", Desc: "List the local task profiles", Run: runProfiles, ErrMsg: "Error listing profiles"},
+		{Name: "accounts", Desc: "List the signed-in Google accounts", Run: runAccounts, ErrMsg: "Error listing accounts", NoGoogle: true},
+		{Name: "auth", Desc: "Sign in to Google again; auth status checks the token, auth reset deletes it first", Run: runAuth, ErrMsg: "Error authenticating", NoGoogle: true,
+			Flags: []commandFlag{{Name: "yes", Desc: "Don't ask before deleting the token with auth reset"}}},
+		{Name: "completion", Desc: "Print a shell completion script (bash, zsh or fish)", Run: runCompletion, ErrMsg: "Error generating completion", NoSetup: true},
+	}
+}
+
+// findCommand looks up a subcommand by name
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.Name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
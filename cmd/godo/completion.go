@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// runCompletion prints a completion script for the given shell, built from
+// the command registry so new subcommands complete without extra work
+func runCompletion(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: godo completion bash|zsh|fish")
+	}
+
+	switch args[0] {
+	case "bash":
+		fmt.Print(bashCompletion())
+	case "zsh":
+		fmt.Print(zshCompletion())
+	case "fish":
+		fmt.Print(fishCompletion())
+	default:
+		return fmt.Errorf("unsupported shell %q", args[0])
+	}
+	return nil
+}
+
+// flagWords joins flags as --name words
+func flagWords(flags []commandFlag) string {
+	words := make([]string, len(flags))
+	for i, f := range flags {
+		words[i] = "--" + f.Name
+	}
+	return strings.Join(words, " ")
+}
+
+func bashCompletion() string {
+	var names []string
+	var cases strings.Builder
+	for _, cmd := range commands {
+		names = append(names, cmd.Name)
+		if len(cmd.Flags) > 0 {
+			fmt.Fprintf(&cases, "\t%s)\n\t\tCOMPREPLY=($(compgen -W %q -- \"$cur\") $(compgen -f -- \"$cur\"))\n\t\t;;\n", cmd.Name, flagWords(cmd.Flags))
+		}
+	}
+	fmt.Fprintf(&cases, "\tcompletion)\n\t\tCOMPREPLY=($(compgen -W \"bash zsh fish\" -- \"$cur\"))\n\t\t;;\n")
+
+	return fmt.Sprintf(`# bash completion for godo
+_godo() {
+	local cur="${COMP_WORDS[COMP_CWORD]}"
+	local cmd="" word
+	for word in "${COMP_WORDS[@]:1:COMP_CWORD-1}"; do
+		case "$word" in
+		-*) ;;
+		*) cmd="$word"; break ;;
+		esac
+	done
+
+	case "$cmd" in
+	"")
+		COMPREPLY=($(compgen -W %q -- "$cur"))
+		;;
+%s	*)
+		COMPREPLY=($(compgen -f -- "$cur"))
+		;;
+	esac
+}
+complete -F _godo godo
+`, strings.Join(names, " ")+" "+flagWords(globalFlags), cases.String())
+}
+
+func zshCompletion() string {
+	var described, globals strings.Builder
+	var cases strings.Builder
+	for _, cmd := range commands {
+		fmt.Fprintf(&described, "\t\t%s\n", zshQuote(cmd.Name+":"+cmd.Desc))
+		if len(cmd.Flags) > 0 {
+			fmt.Fprintf(&cases, "\t\t%s)\n\t\t\t_arguments", cmd.Name)
+			for _, f := range cmd.Flags {
+				fmt.Fprintf(&cases, " %s", zshQuote("--"+f.Name+"=["+f.Desc+"]:value:"))
+			}
+			cases.WriteString(" '*:file:_files'\n\t\t\t;;\n")
+		}
+	}
+	for _, f := range globalFlags {
+		fmt.Fprintf(&globals, " %s", zshQuote("--"+f.Name+"["+f.Desc+"]"))
+	}
+
+	return fmt.Sprintf(`#compdef godo
+
+_godo() {
+	local -a commands
+	commands=(
+%s	)
+
+	local state
+	_arguments%s '1: :->command' '*:: :->args'
+	case $state in
+	command)
+		_describe 'command' commands
+		;;
+	args)
+		case $words[1] in
+%s		completion)
+			_values 'shell' bash zsh fish
+			;;
+		*)
+			_files
+			;;
+		esac
+		;;
+	esac
+}
+
+compdef _godo godo
+`, described.String(), globals.String(), cases.String())
+}
+
+func fishCompletion() string {
+	var s strings.Builder
+	s.WriteString("# fish completion for godo\n")
+	for _, f := range globalFlags {
+		fmt.Fprintf(&s, "complete -c godo -l %s -d %s\n", f.Name, fishQuote(f.Desc))
+	}
+	for _, cmd := range commands {
+		fmt.Fprintf(&s, "complete -c godo -f -n __fish_use_subcommand -a %s -d %s\n", cmd.Name, fishQuote(cmd.Desc))
+		for _, f := range cmd.Flags {
+			fmt.Fprintf(&s, "complete -c godo -n '__fish_seen_subcommand_from %s' -l %s -r -d %s\n", cmd.Name, f.Name, fishQuote(f.Desc))
+		}
+	}
+	s.WriteString("complete -c godo -f -n '__fish_seen_subcommand_from completion' -a 'bash zsh fish'\n")
+	return s.String()
+}
+
+// zshQuote single-quotes s for zsh
+func zshQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote single-quotes s for fish
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/wraient/godo/internal"
+)
+
+// runConfig opens the config file in the editor. It runs before the config
+// is loaded, so a file too broken to load can still be fixed this way.
+func runConfig(args []string) error {
+	if len(args) != 1 || args[0] != "edit" {
+		return fmt.Errorf("usage: godo config edit")
+	}
+	path := configPath()
+	if err := internal.EditConfig(path); err != nil {
+		return err
+	}
+	fmt.Printf("Saved %s\n", path)
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/wraient/godo/internal"
+)
+
+// runDedupe removes tasks that repeat an older task's title under the same
+// parent, after listing them and asking for confirmation
+func runDedupe(args []string) error {
+	fs := flag.NewFlagSet("dedupe", flag.ExitOnError)
+	yes := fs.Bool("yes", false, "Don't ask for confirmation")
+	fs.Parse(args)
+
+	var tasks []internal.Task
+	var err error
+	if internal.UseGoogleTasks.Load() {
+		tasks, err = internal.GoogleTasksClientVar.LoadTasks()
+	} else {
+		tasks, err = internal.DefaultStore().List()
+	}
+	if err != nil {
+		return err
+	}
+
+	dups := internal.FindDuplicates(tasks)
+	if len(dups) == 0 {
+		fmt.Println("No duplicate tasks found")
+		return nil
+	}
+	for _, dup := range dups {
+		fmt.Printf("%s (removing %s, keeping %s)\n", dup.Remove.Title, dup.Remove.Id, dup.Keep.Id)
+	}
+
+	if !*yes {
+		fmt.Printf("Remove %d duplicate(s)? [y/N] ", len(dups))
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+			fmt.Println("Nothing removed")
+			return nil
+		}
+	}
+
+	tasks, removed, err := internal.RemoveDuplicates(tasks, dups, internal.GoogleTasksClientVar)
+	if !internal.UseGoogleTasks.Load() {
+		if saveErr := internal.SaveTasksNow(tasks); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}
+	fmt.Printf("Removed %d duplicate(s)\n", removed)
+	return err
+}
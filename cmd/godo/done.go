@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/wraient/godo/internal"
+)
+
+// runDone completes a task by ID or, failing that, by title without opening
+// the TUI
+func runDone(args []string) error {
+	query := strings.TrimSpace(strings.Join(args, " "))
+	if query == "" {
+		return fmt.Errorf("usage: godo done <id or title>")
+	}
+
+	store := internal.DefaultStore()
+	changed, err := store.Complete(query)
+	if errors.Is(err, internal.ErrTaskNotFound) {
+		tasks, listErr := store.List()
+		if listErr != nil {
+			return listErr
+		}
+		task, matchErr := internal.FindTaskByTitle(tasks, query)
+		if matchErr != nil {
+			return matchErr
+		}
+		changed, err = store.Complete(task.Id)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Completed %s\n", changed[0].Title)
+	return nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/wraient/godo/internal"
+)
+
+// runExport writes all tasks, or with --list one list or task and
+// everything below it, in a format other tools can read
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "csv", "Format to export (csv)")
+	output := fs.String("output", "", "File to write to instead of stdout")
+	list := fs.String("list", "", "ID or name of the list, or ID of the task, to export")
+	fs.Parse(args)
+
+	tasks, err := internal.ImportTasks()
+	if err != nil {
+		return err
+	}
+	if *list != "" {
+		if tasks, err = internal.SelectSubtree(tasks, *list); err != nil {
+			return err
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("error creating %s: %v", *output, err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	switch *format {
+	case "csv":
+		return internal.ExportCSV(w, tasks)
+	default:
+		return fmt.Errorf("unsupported export format %q", *format)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/wraient/godo/internal"
+)
+
+// runImport merges tasks exported from another app into godo
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	format := fs.String("format", "todoist", "Format of the file to import (todoist)")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: godo import --format=todoist <file>")
+	}
+
+	var lists []internal.Task
+	var orphans int
+	var err error
+	switch *format {
+	case "todoist":
+		lists, orphans, err = internal.ImportTodoist(fs.Arg(0))
+	default:
+		return fmt.Errorf("unsupported import format %q", *format)
+	}
+	if err != nil {
+		return err
+	}
+
+	if internal.UseGoogleTasks.Load() {
+		for _, list := range lists {
+			// Importing again adds to the lists made the first time
+			listID, err := internal.GoogleTasksClientVar.FindOrCreateList(list.Title)
+			if err != nil {
+				return err
+			}
+			if err := internal.GoogleTasksClientVar.PushTasks(listID, "", list.Tasks); err != nil {
+				return fmt.Errorf("error pushing %s to Google: %v", list.Title, err)
+			}
+		}
+	} else {
+		tasks, err := internal.DefaultStore().List()
+		if err != nil {
+			return err
+		}
+		if err := internal.SaveTasksNow(append(tasks, lists...)); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Imported %d list(s)\n", len(lists))
+	if orphans > 0 {
+		fmt.Printf("%d task(s) referred to a parent or project missing from the export and were put at the top of their project or in %s\n", orphans, internal.TodoistOrphanList)
+	}
+	return nil
+}
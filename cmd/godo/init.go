@@ -0,0 +1,12 @@
+package main
+
+import (
+	"os"
+
+	"github.com/wraient/godo/internal"
+)
+
+// runInit walks through the basic settings and saves them to the config file
+func runInit(args []string) error {
+	return internal.RunConfigWizard(internal.GetGlobalConfig(), os.Stdin, os.Stdout)
+}
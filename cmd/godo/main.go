@@ -14,60 +14,105 @@ import (
 func main() {
 	// Parse command line flags
 	useGoogle := flag.Bool("google", false, "Use Google Tasks for storage")
+	useCalDAV := flag.Bool("caldav", false, "Use the CalDAV server set in the config for storage")
+	debug := flag.Bool("debug", false, "Write debug messages to godo.log")
+	profile := flag.String("profile", "", "Use the named local task file, tasks-<name>.json")
+	account := flag.String("account", "", "Use the named Google account, each signed in with its own token")
 	flag.Parse()
+	internal.DebugLogging = *debug
 
 	// Set the global flag for Google Tasks mode
-	internal.UseGoogleTasks = *useGoogle
+	internal.UseGoogleTasks.Store(*useGoogle)
 
 	var tasks []internal.Task
 	var err error
 
+	// Some subcommands, like completion, need no config or Google connection
+	cmd, ok := findCommand(flag.Arg(0))
+	if ok && cmd.NoSetup {
+		runCommand(cmd)
+		return
+	}
+
 	// Load configuration first, regardless of mode
-	config, err := internal.LoadConfig(filepath.Join(os.Getenv("HOME"), ".config", "godo", "config"))
+	config, err := internal.LoadConfig(configPath())
 	if err != nil {
 		fmt.Printf("Error loading config: %v\n", err)
 		os.Exit(1)
 	}
 	internal.SetGlobalConfig(&config)
 
-	if internal.UseGoogleTasks {
+	// Google mode can also be the default, as set by godo migrate --switch
+	internal.UseCalDAV = *useCalDAV
+	internal.UseGoogleTasks.Store(*useGoogle || config.UseGoogleTasks && !*useCalDAV)
+	if internal.UseGoogleTasks.Load() && internal.UseCalDAV {
+		fmt.Println("Use either --google or --caldav, not both")
+		os.Exit(1)
+	}
+
+	if err := internal.SetProfile(*profile); err != nil {
+		fmt.Printf("Error selecting profile: %v\n", err)
+		os.Exit(1)
+	}
+	if err := internal.SetAccount(*account); err != nil {
+		fmt.Printf("Error selecting account: %v\n", err)
+		os.Exit(1)
+	}
+
+	if internal.UseGoogleTasks.Load() && !(ok && cmd.NoGoogle) {
 		err = internal.InitializeGoogleTasks()
 		if err != nil {
 			fmt.Printf("Error initializing Google Tasks: %v\n", err)
 			os.Exit(1)
 		}
-
-		tasks, err = internal.GoogleTasksClientVar.LoadTasks()
-		if err != nil {
-			fmt.Printf("Error loading tasks from Google: %v\n", err)
+	}
+	if internal.UseCalDAV {
+		if err := internal.InitializeCalDAV(); err != nil {
+			fmt.Printf("Error initializing CalDAV: %v\n", err)
 			os.Exit(1)
 		}
+	}
+
+	// Run a subcommand instead of the TUI if one was given
+	if ok {
+		runCommand(cmd)
+		return
+	}
+
+	if internal.UseGoogleTasks.Load() {
+		// Start from the cache; the UI fetches fresh tasks in the background
+		tasks = internal.CachedTasks()
 	} else {
 		// Load tasks based on storage mode
-		tasks, err = internal.ImportTasks()
+		tasks, err = internal.DefaultStore().List()
 		if err != nil {
 			fmt.Printf("Error loading tasks: %v\n", err)
 			os.Exit(1)
 		}
 	}
 
-	// If no tasks exist, create an intro task
-	if len(tasks) == 0 {
-		now := time.Now()
-		tasks = []internal.Task{
-			{
-				Id:      "1",
-				Title:   "Welcome to Godo!",
-				Notes:   "This is your first task. Press 'n' to create a new task, 'e' to edit this task, or 'd' to delete it.",
-				Created: now,
-				Updated: now,
-			},
-		}
-		// Save the intro task
-		if err := internal.SaveTasks(tasks); err != nil {
-			fmt.Printf("Error saving intro task: %v\n", err)
+	// Greet new users with an intro task, but only on the very first run
+	if len(tasks) == 0 && !internal.UseGoogleTasks.Load() && !internal.UseCalDAV && internal.FirstRun() {
+		tasks = internal.WelcomeTasks(time.Now())
+		if len(tasks) > 0 {
+			if err := internal.DefaultStore().Save(tasks); err != nil {
+				fmt.Printf("Error saving intro task: %v\n", err)
+			}
 		}
 	}
 
 	internal.RunTaskUI(tasks, internal.GoogleTasksClientVar)
 }
+
+// configPath is where godo reads its config from
+func configPath() string {
+	return filepath.Join(os.Getenv("HOME"), ".config", "godo", "config")
+}
+
+// runCommand runs a subcommand with the remaining arguments, exiting on error
+func runCommand(cmd command) {
+	if err := cmd.Run(flag.Args()[1:]); err != nil {
+		fmt.Printf("%s: %v\n", cmd.ErrMsg, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/wraient/godo/internal"
+)
+
+// runMigrate pushes every local task to Google Tasks in one go
+func runMigrate(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	toGoogle := fs.Bool("to-google", false, "Push the local tasks to Google Tasks")
+	list := fs.String("list", "", "Google list to put every task in, instead of one per local list")
+	switchMode := fs.Bool("switch", false, "Use Google Tasks by default afterwards")
+	fs.Parse(args)
+
+	if !*toGoogle {
+		return fmt.Errorf("usage: godo migrate --to-google [--list \"Name\"] [--switch]")
+	}
+
+	// The local tasks are read regardless of mode, but Google must be connected
+	if internal.GoogleTasksClientVar == nil {
+		if err := internal.InitializeGoogleTasks(); err != nil {
+			return fmt.Errorf("error initializing Google Tasks: %v", err)
+		}
+	}
+
+	count, err := internal.MigrateToGoogle(internal.GoogleTasksClientVar, *list, func(done, total int, task internal.Task) {
+		fmt.Printf("Pushed %d/%d: %s\n", done, total, task.Title)
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Migrated %d task(s) to Google Tasks\n", count)
+
+	if *switchMode {
+		if err := internal.UseGoogleByDefault(); err != nil {
+			return fmt.Errorf("error switching to Google Tasks: %v", err)
+		}
+		fmt.Println("godo now uses Google Tasks by default")
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/wraient/godo/internal"
+)
+
+// runProfiles lists the local task profiles, marking the active one
+func runProfiles(args []string) error {
+	profiles, err := internal.ListProfiles()
+	if err != nil {
+		return err
+	}
+	if len(profiles) == 0 {
+		fmt.Println("No profiles yet")
+		return nil
+	}
+	for _, name := range profiles {
+		marker := " "
+		if name == internal.ActiveProfile() {
+			marker = "*"
+		}
+		fmt.Printf("%s %s\n", marker, name)
+	}
+	return nil
+}
+
+// runAccounts lists the Google accounts with a token, marking the active one
+func runAccounts(args []string) error {
+	accounts, err := internal.ListAccounts()
+	if err != nil {
+		return err
+	}
+	if len(accounts) == 0 {
+		fmt.Println("No accounts signed in yet; run godo --account <name> auth to add one")
+		return nil
+	}
+	active := internal.ActiveAccount()
+	if active == "" {
+		active = internal.DefaultAccount
+	}
+	for _, name := range accounts {
+		marker := " "
+		if name == active {
+			marker = "*"
+		}
+		fmt.Printf("%s %s\n", marker, name)
+	}
+	return nil
+}
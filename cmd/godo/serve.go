@@ -0,0 +1,23 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/wraient/godo/internal"
+)
+
+// runServe starts the local HTTP/JSON API until interrupted
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", internal.GetGlobalConfig().ServerAddr, "Address to bind the API server to")
+	fs.Parse(args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return internal.Serve(ctx, *addr)
+}
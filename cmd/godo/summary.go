@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/wraient/godo/internal"
+)
+
+// runSummary prints a one-line overview of today's tasks for status bars
+func runSummary(args []string) error {
+	fs := flag.NewFlagSet("summary", flag.ExitOnError)
+	format := fs.String("format", "plain", "Output format (plain or json)")
+	fs.Parse(args)
+
+	var tasks []internal.Task
+	var err error
+	if internal.UseGoogleTasks.Load() {
+		// The cache is good enough for a status bar; only fetch without one
+		tasks = internal.CachedTasks()
+		if len(tasks) == 0 {
+			tasks, err = internal.GoogleTasksClientVar.LoadTasks()
+		}
+	} else {
+		tasks, err = internal.ImportTasks()
+	}
+	if err != nil {
+		return err
+	}
+
+	summary := internal.Summarize(tasks, time.Now())
+	switch *format {
+	case "plain":
+		fmt.Println(summary)
+	case "json":
+		return json.NewEncoder(os.Stdout).Encode(summary)
+	default:
+		return fmt.Errorf("unsupported summary format %q", *format)
+	}
+	return nil
+}
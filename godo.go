@@ -0,0 +1,53 @@
+// Package godo lets other Go programs read and change godo's tasks without
+// the terminal UI. It uses the same config, storage and Google Tasks sync as
+// the godo command:
+//
+//	store, err := godo.Open("", false)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	task, err := store.Add(godo.Task{Title: "Buy milk"})
+package godo
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/wraient/godo/internal"
+)
+
+// Task is a task or, with Kind "tasks#taskList", a task list
+type Task = internal.Task
+
+// Store lists, adds, updates, deletes and completes tasks
+type Store = internal.Store
+
+// ErrTaskNotFound is returned by Store methods given an unknown task ID
+var ErrTaskNotFound = internal.ErrTaskNotFound
+
+// DefaultConfigPath is where the godo command keeps its config
+func DefaultConfigPath() string {
+	return filepath.Join(os.Getenv("HOME"), ".config", "godo", "config")
+}
+
+// Open loads the config at configPath, or DefaultConfigPath when empty, and
+// returns the local store, or the Google Tasks store when useGoogle is set.
+// Opening Google Tasks runs the OAuth flow if no token is saved yet.
+func Open(configPath string, useGoogle bool) (Store, error) {
+	if configPath == "" {
+		configPath = DefaultConfigPath()
+	}
+	config, err := internal.LoadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	internal.SetGlobalConfig(&config)
+
+	internal.UseGoogleTasks.Store(useGoogle)
+	if useGoogle {
+		if err := internal.InitializeGoogleTasks(); err != nil {
+			return nil, err
+		}
+	}
+	return internal.DefaultStore(), nil
+}
@@ -0,0 +1,166 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DefaultAccount names the account signed in with the plain GoogleTokenPath
+const DefaultAccount = "default"
+
+var (
+	accountMu sync.RWMutex
+	// activeAccount selects the Google account; empty is the default account.
+	// Switching accounts sets it from a background command, so it is only
+	// read and written under accountMu.
+	activeAccount string
+)
+
+// SetAccount selects the Google account, falling back to the GoogleAccount
+// config key when name is empty
+func SetAccount(name string) error {
+	name, err := accountName(name)
+	if err != nil {
+		return err
+	}
+	setActiveAccount(name)
+	return nil
+}
+
+// accountName checks an account name, falling back to the GoogleAccount
+// config key when it is empty. Names are limited to letters, digits, - and
+// _ as they end up in file names. The default account is returned as empty.
+func accountName(name string) (string, error) {
+	if name == "" {
+		if config := GetGlobalConfig(); config != nil {
+			name = config.GoogleAccount
+		}
+	}
+	if name == DefaultAccount {
+		name = ""
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return "", fmt.Errorf("invalid account name %q, use letters, digits, - and _", name)
+		}
+	}
+	return name, nil
+}
+
+func setActiveAccount(name string) {
+	accountMu.Lock()
+	defer accountMu.Unlock()
+	activeAccount = name
+}
+
+// ActiveAccount returns the selected Google account, empty for the default
+func ActiveAccount() string {
+	accountMu.RLock()
+	defer accountMu.RUnlock()
+	return activeAccount
+}
+
+// accountFile returns the active account's copy of a file, adding the
+// account name before the extension, e.g. google_token-work.json
+func accountFile(path string) string {
+	account := ActiveAccount()
+	if account == "" {
+		return path
+	}
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + account + ext
+}
+
+// ListAccounts returns the Google accounts with a token, sorted, with the
+// default account first
+func ListAccounts() ([]string, error) {
+	base := os.ExpandEnv(GetGlobalConfig().GoogleTokenPath)
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(filepath.Base(base), ext) + "-"
+
+	entries, err := os.ReadDir(filepath.Dir(base))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading token directory: %v", err)
+	}
+
+	var accounts []string
+	hasDefault := false
+	for _, entry := range entries {
+		name := entry.Name()
+		switch {
+		case name == filepath.Base(base):
+			hasDefault = true
+		case strings.HasPrefix(name, prefix) && filepath.Ext(name) == ext:
+			accounts = append(accounts, strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext))
+		}
+	}
+	sort.Strings(accounts)
+	if hasDefault {
+		accounts = append([]string{DefaultAccount}, accounts...)
+	}
+	return accounts, nil
+}
+
+// accountLabel names an account for status messages, or is empty for the
+// default account
+func accountLabel(account string) string {
+	if account == "" {
+		return ""
+	}
+	return " (" + account + ")"
+}
+
+func init() {
+	registerCommand(paletteCommand{Name: "account", Desc: "Switch to another Google account, or list them", Run: accountCommand})
+}
+
+// accountCommand reconnects to Google as another account. Each account has
+// its own token and cache, so their lists never mix.
+func accountCommand(m *model, args string) tea.Cmd {
+	name := strings.TrimSpace(args)
+	if name == "" {
+		accounts, _ := ListAccounts()
+		current := ActiveAccount()
+		if current == "" {
+			current = DefaultAccount
+		}
+		m.statusMsg = fmt.Sprintf("Using %s; accounts: %s", current, strings.Join(accounts, ", "))
+		return nil
+	}
+
+	if UseCalDAV {
+		m.statusMsg = "Can't switch Google accounts while using CalDAV, restart without --caldav"
+		return nil
+	}
+	account, err := accountName(name)
+	if err != nil {
+		m.statusMsg = err.Error()
+		return nil
+	}
+	previous := ActiveAccount()
+	// Saved while the old account is active, so the edits go to its cache
+	m.autosave()
+	stopped := stopBackgroundSync()
+	m.statusMsg = "Connecting to Google Tasks" + accountLabel(account) + "..."
+	return func() tea.Msg {
+		// A refresh still running would write the old account's tasks into
+		// the new account's cache, so switch once it has finished
+		<-stopped
+		syncMu.Lock()
+		setActiveAccount(account)
+		syncMu.Unlock()
+
+		msg := connectGoogle().(modeSwitchedMsg)
+		msg.accountSwitch, msg.previousAccount = true, previous
+		return msg
+	}
+}
@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// attachmentType marks links that point at local files
+const attachmentType = "file"
+
+// isURL reports whether target looks like a URL rather than a file path
+func isURL(target string) bool {
+	return strings.Contains(target, "://") || strings.HasPrefix(target, "mailto:") || strings.HasPrefix(target, "www.")
+}
+
+// parseAttachment reads "<path or url> [description]" from the input box.
+// Paths are expanded to absolute ones and must exist; quote paths that
+// contain spaces.
+func parseAttachment(input string) (TaskLink, error) {
+	input = strings.TrimSpace(input)
+	var target, desc string
+	if strings.HasPrefix(input, `"`) {
+		end := strings.Index(input[1:], `"`)
+		if end < 0 {
+			return TaskLink{}, fmt.Errorf("missing closing quote")
+		}
+		target, desc = input[1:end+1], input[end+2:]
+	} else {
+		target, desc, _ = strings.Cut(input, " ")
+	}
+	desc = strings.TrimSpace(desc)
+	if target == "" {
+		return TaskLink{}, fmt.Errorf("enter a file path or URL, optionally followed by a description")
+	}
+	if isURL(target) {
+		return TaskLink{Type: "url", Desc: desc, Link: target}, nil
+	}
+
+	if rest, ok := strings.CutPrefix(target, "~"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return TaskLink{}, fmt.Errorf("failed to get home directory: %v", err)
+		}
+		target = home + rest
+	}
+	path, err := filepath.Abs(os.ExpandEnv(target))
+	if err != nil {
+		return TaskLink{}, fmt.Errorf("invalid path %q: %v", target, err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return TaskLink{}, fmt.Errorf("no such file: %s", path)
+	}
+	return TaskLink{Type: attachmentType, Desc: desc, Link: path}, nil
+}
+
+// attachmentMissing reports whether a file attachment no longer exists
+func attachmentMissing(link TaskLink) bool {
+	if link.Type != attachmentType {
+		return false
+	}
+	_, err := os.Stat(link.Link)
+	return os.IsNotExist(err)
+}
+
+// attach adds a file or URL to the selected task's links
+func (m *model) attach(input string) {
+	task := m.selectedTask()
+	if task == nil {
+		return
+	}
+	link, err := parseAttachment(input)
+	if err != nil {
+		m.statusMsg = err.Error()
+		return
+	}
+
+	task.Links = append(task.Links, link)
+	task.Updated = time.Now()
+	m.syncToGoogle(*task)
+	m.saveTasks()
+	m.statusMsg = "Attached " + filepath.Base(link.Link)
+}
@@ -0,0 +1,84 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// autosaveInterval is how often unsaved changes are retried if a write failed
+const autosaveInterval = 30 * time.Second
+
+// saveDelay is how long edits are gathered before the tasks are written, so
+// rapid edits cost one write instead of one each
+const saveDelay = 250 * time.Millisecond
+
+// autosaveMsg fires on every autosave tick
+type autosaveMsg time.Time
+
+func autosaveTick() tea.Cmd {
+	return tea.Tick(autosaveInterval, func(t time.Time) tea.Msg {
+		return autosaveMsg(t)
+	})
+}
+
+// saveFlushMsg fires saveDelay after the first unsaved edit
+type saveFlushMsg struct{}
+
+func saveFlushTick() tea.Cmd {
+	return tea.Tick(saveDelay, func(time.Time) tea.Msg {
+		return saveFlushMsg{}
+	})
+}
+
+// saveTasks marks the tasks as changed. They are written shortly after, by
+// scheduleSave and autosave, from whatever the tree is by then.
+func (m *model) saveTasks() {
+	m.dirty = true
+}
+
+// scheduleSave starts the save delay when there are unsaved changes and no
+// write is pending yet
+func (m *model) scheduleSave() tea.Cmd {
+	if !m.dirty || m.saveScheduled {
+		return nil
+	}
+	m.saveScheduled = true
+	return saveFlushTick()
+}
+
+// allTasks returns the full task tree, active and completed, for saving
+func (m *model) allTasks() []Task {
+	all := make([]Task, 0, len(m.tasks)+len(m.completedTasks))
+	all = append(all, m.tasks...)
+	return append(all, m.completedTasks...)
+}
+
+// autosave writes the tasks if anything changed since the last write. It
+// only runs inside Update, so it always writes the latest tree and never
+// races with edits.
+func (m *model) autosave() {
+	if !m.dirty {
+		return
+	}
+	if err := DefaultStore().Save(m.allTasks()); err != nil {
+		m.statusMsg = fmt.Sprintf("Error saving tasks: %v", err)
+		return
+	}
+	m.dirty = false
+}
+
+// quitOnHangup asks the program to quit when the terminal goes away, so the
+// final save still happens. Bubble Tea already turns SIGINT and SIGTERM into a quit.
+func quitOnHangup(p *tea.Program) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP)
+	go func() {
+		<-sigs
+		p.Quit()
+	}()
+}
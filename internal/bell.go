@@ -0,0 +1,21 @@
+package internal
+
+import (
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// completionBell rings the terminal bell after a task is completed, if
+// CompletionBell is enabled. The bell moves no cursor, so it can't disturb
+// the rendered frame.
+func completionBell() tea.Cmd {
+	config := GetGlobalConfig()
+	if config == nil || !config.CompletionBell {
+		return nil
+	}
+	return func() tea.Msg {
+		os.Stdout.WriteString("\a")
+		return nil
+	}
+}
@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// googleCacheFile is where the last fetched tasks of the active Google
+// account are kept
+func googleCacheFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error getting home directory: %v", err)
+	}
+	return accountFile(filepath.Join(home, ".local", "share", "godo", "google_tasks_cache.json")), nil
+}
+
+// cacheTTL is how old the cache may get before it is ignored in favor of a
+// fresh fetch, from CacheTTLHours. Zero means the cache never expires.
+func cacheTTL() time.Duration {
+	config := GetGlobalConfig()
+	if config == nil || config.CacheTTLHours <= 0 {
+		return 0
+	}
+	return time.Duration(config.CacheTTLHours) * time.Hour
+}
+
+// ClearGoogleCache deletes the cache file and empties the cache in memory,
+// so the next load has to fetch from Google
+func ClearGoogleCache() error {
+	cacheFile, err := googleCacheFile()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(cacheFile); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing cache file: %v", err)
+	}
+	if taskCache != nil {
+		taskCache.mu.Lock()
+		taskCache.Tasks = make([]Task, 0)
+		taskCache.LastSync = time.Time{}
+		taskCache.mu.Unlock()
+	}
+	return nil
+}
+
+// RefreshGoogleCache fetches the tasks from Google into the cache and
+// returns how many task lists there are
+func RefreshGoogleCache() (int, error) {
+	tasks, _, err := refreshGoogleCache()
+	return len(tasks), err
+}
+
+func init() {
+	registerCommand(paletteCommand{Name: "clear-cache", Desc: "Clear the Google Tasks cache and fetch again", Run: clearCacheCommand})
+}
+
+func clearCacheCommand(m *model, args string) tea.Cmd {
+	if m.googleTasks == nil {
+		m.statusMsg = "There is no Google cache in local mode"
+		return nil
+	}
+	if err := ClearGoogleCache(); err != nil {
+		m.statusMsg = err.Error()
+		return nil
+	}
+	m.statusMsg = "Cleared the cache"
+	return m.manualSync()
+}
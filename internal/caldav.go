@@ -0,0 +1,392 @@
+package internal
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// UseCalDAV keeps tasks on a CalDAV server instead of Google or the local file
+	UseCalDAV bool
+	// CalDAVClientVar is the client for the configured CalDAV server
+	CalDAVClientVar *CalDAVClient
+)
+
+// CalDAVClient talks to a CalDAV server, such as Nextcloud or Radicale.
+// Calendars that hold VTODOs become task lists, and each VTODO a task.
+type CalDAVClient struct {
+	baseURL  *url.URL
+	username string
+	password string
+	http     *http.Client
+
+	mu     sync.Mutex            // Guards synced and serializes pushes
+	synced map[string]syncedTodo // Tasks as last read from or written to the server, by ID; nil until loaded
+
+	pushMu  sync.Mutex
+	pending []Task // Latest tree waiting to be pushed
+	pushing bool   // A push loop is running
+}
+
+// syncedTodo is what the client knows about a task stored on the server
+type syncedTodo struct {
+	Href     string // Resource path of the task
+	Calendar string // Href of the calendar holding it
+	Etag     string
+	Data     string // iCalendar text as the server has it, updated in place on writes
+	Fields   string // godo's fields as last synced, to spot changes
+}
+
+// NewCalDAVClient returns a client for the calendar or calendar home at rawURL
+func NewCalDAVClient(rawURL, username, password string) (*CalDAVClient, error) {
+	base, err := url.Parse(rawURL)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("invalid CalDAV URL %q", rawURL)
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+	return &CalDAVClient{
+		baseURL:  base,
+		username: username,
+		password: password,
+		http:     &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// InitializeCalDAV connects to the server set by CalDAVURL, CalDAVUsername
+// and CalDAVPassword
+func InitializeCalDAV() error {
+	config := GetGlobalConfig()
+	if config == nil || config.CalDAVURL == "" {
+		return fmt.Errorf("set CalDAVURL, and CalDAVUsername and CalDAVPassword if needed, in %s", configFilePath)
+	}
+	client, err := NewCalDAVClient(config.CalDAVURL, config.CalDAVUsername, config.CalDAVPassword)
+	if err != nil {
+		return err
+	}
+	CalDAVClientVar = client
+	return nil
+}
+
+// request sends a WebDAV request and returns the response body, failing on
+// any status outside 2xx
+func (c *CalDAVClient) request(method, href string, headers map[string]string, body string) (*http.Response, []byte, error) {
+	target, err := c.baseURL.Parse(href)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := http.NewRequest(method, target.String(), strings.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	recordAPICall("caldav " + strings.ToLower(method))
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s %s: %v", method, target.Path, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp, data, fmt.Errorf("%s %s: %s", method, target.Path, resp.Status)
+	}
+	return resp, data, nil
+}
+
+// multistatus is the WebDAV response to PROPFIND and REPORT
+type multistatus struct {
+	Responses []struct {
+		Href     string `xml:"href"`
+		Propstat []struct {
+			Status string `xml:"status"`
+			Prop   struct {
+				DisplayName  string `xml:"displayname"`
+				ResourceType struct {
+					Calendar *struct{} `xml:"urn:ietf:params:xml:ns:caldav calendar"`
+				} `xml:"resourcetype"`
+				Components struct {
+					Comp []struct {
+						Name string `xml:"name,attr"`
+					} `xml:"comp"`
+				} `xml:"urn:ietf:params:xml:ns:caldav supported-calendar-component-set"`
+				Etag         string `xml:"getetag"`
+				CalendarData string `xml:"urn:ietf:params:xml:ns:caldav calendar-data"`
+			} `xml:"prop"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+const calendarsQuery = `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop><d:displayname/><d:resourcetype/><c:supported-calendar-component-set/></d:prop>
+</d:propfind>`
+
+const todosQuery = `<?xml version="1.0" encoding="utf-8"?>
+<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop><d:getetag/><c:calendar-data/></d:prop>
+  <c:filter><c:comp-filter name="VCALENDAR"><c:comp-filter name="VTODO"/></c:comp-filter></c:filter>
+</c:calendar-query>`
+
+// calendars lists the calendars under the configured URL that can hold
+// tasks, as empty task lists. The URL may also be a single calendar.
+func (c *CalDAVClient) calendars() ([]Task, error) {
+	_, data, err := c.request("PROPFIND", c.baseURL.Path, map[string]string{"Depth": "1", "Content-Type": "application/xml"}, calendarsQuery)
+	if err != nil {
+		return nil, err
+	}
+	var result multistatus
+	if err := xml.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("error parsing calendars: %v", err)
+	}
+
+	var lists []Task
+	for _, response := range result.Responses {
+		for _, propstat := range response.Propstat {
+			prop := propstat.Prop
+			if prop.ResourceType.Calendar == nil || !strings.Contains(propstat.Status, "200") {
+				continue
+			}
+			// Servers that don't list components allow all of them
+			todos := len(prop.Components.Comp) == 0
+			for _, comp := range prop.Components.Comp {
+				todos = todos || comp.Name == "VTODO"
+			}
+			if !todos {
+				continue
+			}
+			title := prop.DisplayName
+			if title == "" {
+				title = strings.Trim(response.Href, "/")
+			}
+			lists = append(lists, Task{Id: response.Href, Title: title, Kind: "tasks#taskList", Tasks: []Task{}})
+		}
+	}
+	return lists, nil
+}
+
+// LoadTasks fetches every task from every calendar, with subtasks nested
+// under the tasks they are related to
+func (c *CalDAVClient) LoadTasks() ([]Task, error) {
+	lists, err := c.calendars()
+	if err != nil {
+		return nil, err
+	}
+
+	synced := make(map[string]syncedTodo)
+	for i, list := range lists {
+		_, data, err := c.request("REPORT", list.Id, map[string]string{"Depth": "1", "Content-Type": "application/xml"}, todosQuery)
+		if err != nil {
+			return nil, err
+		}
+		var result multistatus
+		if err := xml.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("error parsing tasks of %s: %v", list.Title, err)
+		}
+
+		var todos []Task
+		for _, response := range result.Responses {
+			for _, propstat := range response.Propstat {
+				task, ok := todoFromICal(propstat.Prop.CalendarData)
+				if !ok {
+					continue
+				}
+				task.SelfLink = response.Href
+				task.Etag = propstat.Prop.Etag
+				synced[task.Id] = syncedTodo{
+					Href:     response.Href,
+					Calendar: list.Id,
+					Etag:     task.Etag,
+					Data:     propstat.Prop.CalendarData,
+					Fields:   todoToICal(task, time.Time{}),
+				}
+				todos = append(todos, task)
+			}
+		}
+		lists[i].Tasks = nestTodos(todos)
+	}
+
+	c.mu.Lock()
+	c.synced = synced
+	c.mu.Unlock()
+	return lists, nil
+}
+
+// nestTodos builds the task tree from the flat VTODOs of one calendar.
+// Tasks whose parent isn't there stay at the top.
+func nestTodos(todos []Task) []Task {
+	children := make(map[string][]Task)
+	ids := make(map[string]bool)
+	for _, todo := range todos {
+		ids[todo.Id] = true
+	}
+	var roots []Task
+	for _, todo := range todos {
+		if todo.Parent != "" && ids[todo.Parent] {
+			children[todo.Parent] = append(children[todo.Parent], todo)
+		} else {
+			todo.Parent = ""
+			roots = append(roots, todo)
+		}
+	}
+	var attach func(tasks []Task) []Task
+	attach = func(tasks []Task) []Task {
+		for i := range tasks {
+			tasks[i].Tasks = attach(children[tasks[i].Id])
+		}
+		if tasks == nil {
+			return []Task{}
+		}
+		return tasks
+	}
+	return attach(roots)
+}
+
+// Push writes a task tree to the server: new and changed tasks are put,
+// tasks no longer in the tree are deleted. Only calendars found in the tree
+// have tasks deleted, so a tree that isn't from this server never empties
+// it. Updates send the etag they were read with, so changes made elsewhere
+// in between aren't overwritten.
+func (c *CalDAVClient) Push(tasks []Task) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Without knowing what the server has, every task would look new
+	if c.synced == nil {
+		return fmt.Errorf("the tasks weren't loaded from the CalDAV server, so changes sync after a restart")
+	}
+
+	now := time.Now()
+	seen := make(map[string]bool)
+	walked := make(map[string]bool) // Calendars whose tasks are all in seen
+	var errs []string
+	for _, list := range tasks {
+		if list.Kind != "tasks#taskList" || list.Deleted {
+			continue
+		}
+		// Calendars are known by their href; lists made in godo have plain IDs
+		if !strings.Contains(list.Id, "/") {
+			errs = append(errs, fmt.Sprintf("%s: creating calendars isn't supported, create it on the server", list.Title))
+			continue
+		}
+		walked[list.Id] = true
+		var walk func(tasks []Task, parentID string)
+		walk = func(tasks []Task, parentID string) {
+			for _, task := range tasks {
+				if task.Deleted {
+					continue
+				}
+				seen[task.Id] = true
+				task.Parent = parentID
+				if err := c.putTodo(list.Id, task, now); err != nil {
+					errs = append(errs, err.Error())
+				}
+				walk(task.Tasks, task.Id)
+			}
+		}
+		walk(list.Tasks, "")
+	}
+
+	for id, todo := range c.synced {
+		if seen[id] || !walked[todo.Calendar] {
+			continue
+		}
+		headers := map[string]string{}
+		if todo.Etag != "" {
+			headers["If-Match"] = todo.Etag
+		}
+		if resp, _, err := c.request("DELETE", todo.Href, headers, ""); err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+			errs = append(errs, err.Error())
+			continue
+		}
+		delete(c.synced, id)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("CalDAV sync: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// putTodo stores one task if it is new or changed since the last sync.
+// Changed tasks are merged into the server's copy, keeping whatever godo
+// doesn't manage. Callers must hold c.mu.
+func (c *CalDAVClient) putTodo(calendar string, task Task, now time.Time) error {
+	known, exists := c.synced[task.Id]
+	// Compare without the timestamp, which changes on every write
+	fields := todoToICal(task, time.Time{})
+	if exists && known.Fields == fields {
+		return nil
+	}
+
+	headers := map[string]string{"Content-Type": "text/calendar; charset=utf-8"}
+	href, data := known.Href, todoToICal(task, now)
+	if !exists {
+		href = strings.TrimSuffix(calendar, "/") + "/" + url.PathEscape(icalFileName(task))
+		headers["If-None-Match"] = "*"
+	} else {
+		if known.Data != "" {
+			data = mergeTodo(known.Data, task, now)
+		}
+		if known.Etag != "" {
+			headers["If-Match"] = known.Etag
+		}
+	}
+
+	resp, _, err := c.request("PUT", href, headers, data)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusPreconditionFailed {
+			return fmt.Errorf("%q was changed elsewhere, reload to see the other version", task.Title)
+		}
+		return err
+	}
+	c.synced[task.Id] = syncedTodo{Href: href, Calendar: calendar, Etag: resp.Header.Get("ETag"), Data: data, Fields: fields}
+	return nil
+}
+
+// queuePush pushes the tree in the background. Pushes run one at a time and
+// only the latest tree waiting is pushed, so a slow server never gets an
+// older state after a newer one.
+func (c *CalDAVClient) queuePush(tasks []Task) {
+	c.pushMu.Lock()
+	c.pending = cloneTasks(tasks)
+	start := !c.pushing
+	c.pushing = true
+	c.pushMu.Unlock()
+	if start {
+		goSync(c.pushLoop)
+	}
+}
+
+func (c *CalDAVClient) pushLoop() {
+	for {
+		c.pushMu.Lock()
+		tasks := c.pending
+		c.pending = nil
+		if tasks == nil {
+			c.pushing = false
+			c.pushMu.Unlock()
+			return
+		}
+		c.pushMu.Unlock()
+
+		if err := c.Push(tasks); err != nil {
+			reportSyncError(err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingServer accepts every request and remembers its method and path
+func recordingServer(t *testing.T) (*CalDAVClient, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("ETag", `"new"`)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewCalDAVClient(server.URL+"/calendars/", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.synced = map[string]syncedTodo{
+		"a": {Href: "/calendars/work/a.ics", Calendar: "/calendars/work/", Etag: `"1"`},
+		"b": {Href: "/calendars/home/b.ics", Calendar: "/calendars/home/", Etag: `"1"`},
+	}
+	return client, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), requests...)
+	}
+}
+
+func TestPushWithoutCalendarsDeletesNothing(t *testing.T) {
+	client, requests := recordingServer(t)
+	// A Google or local tree, as left behind by switching modes
+	tasks := []Task{
+		{Id: "googleList", Title: "Google", Kind: "tasks#taskList", Tasks: []Task{{Id: "x", Title: "X"}}},
+		{Id: "localList", Title: "Local", Kind: "tasks#task", Tasks: []Task{{Id: "y", Title: "Y"}}},
+	}
+
+	client.Push(tasks)
+
+	for _, request := range requests() {
+		if strings.HasPrefix(request, "DELETE") {
+			t.Errorf("pushing a tree without calendars sent %s", request)
+		}
+	}
+	if len(client.synced) != 2 {
+		t.Errorf("the synced tasks went from 2 to %d", len(client.synced))
+	}
+}
+
+func TestPushDeletesOnlyFromWalkedCalendars(t *testing.T) {
+	client, requests := recordingServer(t)
+	// Only the work calendar is in the tree, and a is gone from it
+	tasks := []Task{{Id: "/calendars/work/", Title: "Work", Kind: "tasks#taskList", Tasks: []Task{}}}
+
+	if err := client.Push(tasks); err != nil {
+		t.Fatal(err)
+	}
+
+	got := requests()
+	if len(got) != 1 || got[0] != "DELETE /calendars/work/a.ics" {
+		t.Errorf("sent %v, want only the delete of a", got)
+	}
+}
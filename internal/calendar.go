@@ -0,0 +1,168 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// dayKey identifies a calendar day regardless of the time of day
+func dayKey(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// tasksDueBy groups the unfinished tasks of every list by the day they are due
+func tasksDueBy(tasks []Task) map[string][]Task {
+	due := make(map[string][]Task)
+	var walk func(tasks []Task)
+	walk = func(tasks []Task) {
+		for _, task := range tasks {
+			if task.Deleted {
+				continue
+			}
+			if !task.Completed && !task.DueDate.IsZero() && task.Kind != "tasks#taskList" {
+				key := dayKey(task.DueDate)
+				due[key] = append(due[key], task)
+			}
+			walk(task.Tasks)
+		}
+	}
+	walk(tasks)
+	for _, day := range due {
+		sort.SliceStable(day, func(i, j int) bool { return day[i].DueDate.Before(day[j].DueDate) })
+	}
+	return due
+}
+
+// openCalendar shows the month view with today focused
+func (m *model) openCalendar() {
+	now := time.Now()
+	m.calendarMode = true
+	m.calendarDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	m.calendarDay = false
+	m.calendarCursor = 0
+}
+
+// calendarKey handles keys while the calendar is open. In the grid they move
+// the focused day; after enter they pick one of that day's tasks.
+func (m *model) calendarKey(key string) {
+	if m.calendarDay {
+		tasks := tasksDueBy(m.allTasks())[dayKey(m.calendarDate)]
+		switch key {
+		case "esc", "h", "left":
+			m.calendarDay = false
+		case "down", "j":
+			if m.calendarCursor < len(tasks)-1 {
+				m.calendarCursor++
+			}
+		case "up", "k":
+			if m.calendarCursor > 0 {
+				m.calendarCursor--
+			}
+		case "enter":
+			if m.calendarCursor < len(tasks) {
+				m.calendarMode = false
+				m.jumpTo(tasks[m.calendarCursor].Id)
+			}
+		}
+		return
+	}
+
+	switch key {
+	case "C", "esc":
+		m.calendarMode = false
+	case "left", "h":
+		m.calendarDate = m.calendarDate.AddDate(0, 0, -1)
+	case "right", "l":
+		m.calendarDate = m.calendarDate.AddDate(0, 0, 1)
+	case "up", "k":
+		m.calendarDate = m.calendarDate.AddDate(0, 0, -7)
+	case "down", "j":
+		m.calendarDate = m.calendarDate.AddDate(0, 0, 7)
+	case "<":
+		m.calendarDate = m.calendarDate.AddDate(0, -1, 0)
+	case ">":
+		m.calendarDate = m.calendarDate.AddDate(0, 1, 0)
+	case "t":
+		m.openCalendar()
+	case "enter":
+		if len(tasksDueBy(m.allTasks())[dayKey(m.calendarDate)]) > 0 {
+			m.calendarDay = true
+			m.calendarCursor = 0
+		}
+	}
+}
+
+// renderCalendar draws the month grid, with the number of tasks due on each
+// day, and the tasks due on the focused day below it
+func (m *model) renderCalendar() string {
+	now := time.Now()
+	due := tasksDueBy(m.allTasks())
+	focused := m.calendarDate
+	first := time.Date(focused.Year(), focused.Month(), 1, 0, 0, 0, 0, focused.Location())
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	var s strings.Builder
+	s.WriteString(lipgloss.NewStyle().Bold(true).Render(first.Format("January 2006")) + "\n\n")
+	s.WriteString(dim.Render("Mo    Tu    We    Th    Fr    Sa    Su") + "\n")
+
+	// Weeks start on Monday
+	offset := (int(first.Weekday()) + 6) % 7
+	s.WriteString(strings.Repeat("      ", offset))
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		count := len(due[dayKey(day)])
+		cell := fmt.Sprintf("%2d", day.Day())
+		switch {
+		case count > 9:
+			cell += " •+"
+		case count > 0:
+			cell += fmt.Sprintf(" •%d", count)
+		default:
+			cell += "   "
+		}
+
+		style := lipgloss.NewStyle()
+		switch {
+		case count > 0 && dayKey(day) < dayKey(now):
+			style = overdueStyle
+		case count == 0:
+			style = dim
+		}
+		if dayKey(day) == dayKey(now) {
+			style = style.Bold(true).Underline(true)
+		}
+		if dayKey(day) == dayKey(focused) {
+			style = style.Reverse(true).Foreground(m.accentColor())
+		}
+		s.WriteString(style.Render(cell))
+
+		if day.Weekday() == time.Sunday {
+			s.WriteString("\n")
+		} else {
+			s.WriteString(" ")
+		}
+	}
+
+	s.WriteString("\n\nDue " + focused.Format("Monday, January 2") + ":\n")
+	tasks := due[dayKey(focused)]
+	if len(tasks) == 0 {
+		s.WriteString(dim.Render("  Nothing due") + "\n")
+	}
+	for i, task := range tasks {
+		cursor := " "
+		if m.calendarDay && i == m.calendarCursor {
+			cursor = ">"
+		}
+		s.WriteString(fmt.Sprintf("%s %s%s\n", cursor, priorityMarker(task.Priority), task.Title))
+	}
+
+	help := "h/j/k/l: Move day  </>: Month  t: Today  Enter: Show day's tasks  C/esc: Close"
+	if m.calendarDay {
+		help = "j/k: Select  Enter: Go to task  esc: Back to month"
+	}
+	s.WriteString("\n" + dim.Render(help))
+	return s.String()
+}
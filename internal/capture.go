@@ -0,0 +1,63 @@
+package internal
+
+import "strings"
+
+// InboxTitle is the list captured tasks land in
+const InboxTitle = "Inbox"
+
+// Capture adds a task to the Inbox list, creating the list if needed, or on
+// a CalDAV server the Inbox calendar if there is one. It
+// parses text like quick add and touches nothing but the Inbox, so it is
+// cheap enough to bind to a global hotkey.
+func Capture(text string) (Task, error) {
+	task := ParseQuickAdd(text)
+
+	if UseGoogleTasks.Load() {
+		listID, err := GoogleTasksClientVar.FindOrCreateList(InboxTitle)
+		if err != nil {
+			return Task{}, err
+		}
+		return NewGoogleStore(GoogleTasksClientVar, listID).Add(task)
+	}
+
+	if UseCalDAV {
+		store := NewCalDAVStore(CalDAVClientVar)
+		tasks, err := store.List()
+		if err != nil {
+			return Task{}, err
+		}
+		// Calendars can't be created from godo, so without an Inbox
+		// calendar the task goes to the first one
+		if inbox := localInbox(tasks); inbox != nil {
+			task.Parent = inbox.Id
+		}
+		return store.Add(task)
+	}
+
+	store := NewLocalStore()
+	tasks, err := store.List()
+	if err != nil {
+		return Task{}, err
+	}
+	inbox := localInbox(tasks)
+	if inbox == nil {
+		created, err := store.Add(Task{Title: InboxTitle, Kind: "tasks#taskList"})
+		if err != nil {
+			return Task{}, err
+		}
+		inbox = &created
+	}
+	task.Parent = inbox.Id
+	return store.Add(task)
+}
+
+// localInbox finds the top-level Inbox list in a local or CalDAV tree
+func localInbox(tasks []Task) *Task {
+	for i := range tasks {
+		task := &tasks[i]
+		if task.Kind == "tasks#taskList" && !task.Deleted && strings.EqualFold(task.Title, InboxTitle) {
+			return task
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// checklistLine matches a Markdown checklist item such as "- [ ] Milk" or
+// "* [x] Eggs", capturing the text up to the box, the mark and the label
+var checklistLine = regexp.MustCompile(`^(\s*[-*] \[)([ xX])\] (.*)$`)
+
+// checklistItem is one checklist line in a task's notes
+type checklistItem struct {
+	Line  int // Index of the line in the notes
+	Done  bool
+	Label string
+}
+
+// checklist returns the checklist items in notes, in order
+func checklist(notes string) []checklistItem {
+	var items []checklistItem
+	for i, line := range strings.Split(notes, "\n") {
+		if match := checklistLine.FindStringSubmatch(line); match != nil {
+			items = append(items, checklistItem{Line: i, Done: match[2] != " ", Label: strings.TrimRight(match[3], "\r")})
+		}
+	}
+	return items
+}
+
+// checklistProgress counts the ticked and total checklist items in notes
+func checklistProgress(notes string) (done, total int) {
+	for _, item := range checklist(notes) {
+		total++
+		if item.Done {
+			done++
+		}
+	}
+	return done, total
+}
+
+// toggleChecklistItem ticks or unticks the nth checklist item, counting
+// from 1. Only the mark changes, so the rest of the notes stay as they were.
+func toggleChecklistItem(notes string, n int) (string, error) {
+	items := checklist(notes)
+	if n < 1 || n > len(items) {
+		return notes, fmt.Errorf("no checklist item %d", n)
+	}
+	lines := strings.Split(notes, "\n")
+	line := lines[items[n-1].Line]
+	box := len(checklistLine.FindStringSubmatch(line)[1])
+	mark := "x"
+	if items[n-1].Done {
+		mark = " "
+	}
+	lines[items[n-1].Line] = line[:box] + mark + line[box+1:]
+	return strings.Join(lines, "\n"), nil
+}
+
+// renderChecklist numbers the checklist items of notes for the toggle prompt
+func renderChecklist(notes string) string {
+	var s strings.Builder
+	for i, item := range checklist(notes) {
+		mark := "[ ]"
+		if item.Done {
+			mark = "[x]"
+		}
+		fmt.Fprintf(&s, "  %d. %s %s\n", i+1, mark, item.Label)
+	}
+	return s.String()
+}
+
+// openChecklist asks which checklist item of the selected task to toggle
+func (m *model) openChecklist() {
+	task := m.selectedTask()
+	if task == nil {
+		return
+	}
+	if _, total := checklistProgress(task.Notes); total == 0 {
+		m.statusMsg = "The notes have no checklist; start lines with - [ ] to add one"
+		return
+	}
+	m.inputActive = true
+	m.inputAction = "checklist"
+	m.input.Placeholder = "Number of the item to tick or untick"
+	m.input.SetValue("")
+	m.input.Focus()
+}
+
+// toggleChecklist ticks or unticks the checklist item numbered input
+func (m *model) toggleChecklist(input string) {
+	task := m.selectedTask()
+	if task == nil {
+		return
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil {
+		m.statusMsg = "Enter the number of a checklist item"
+		return
+	}
+	notes, err := toggleChecklistItem(task.Notes, n)
+	if err != nil {
+		m.statusMsg = err.Error()
+		return
+	}
+	task.Notes = notes
+	task.Updated = time.Now()
+	m.syncToGoogle(*task)
+	m.saveTasks()
+}
@@ -0,0 +1,199 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// clearedTask is a completed task kept locally after Google cleared it
+type clearedTask struct {
+	ListID    string    `json:"listId"`
+	ClearedAt time.Time `json:"clearedAt"`
+	Task      Task      `json:"task"`
+}
+
+// archiveMu serializes appends to cleared.json
+var archiveMu sync.Mutex
+
+// archiveCleared appends tasks about to be cleared from a list to
+// cleared.json, so clearing tidies Google without losing history
+func archiveCleared(listID string, tasks []Task, now time.Time) error {
+	archiveMu.Lock()
+	defer archiveMu.Unlock()
+
+	path, err := storageFile("cleared.json")
+	if err != nil {
+		return err
+	}
+	var archive []clearedTask
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &archive); err != nil {
+			return fmt.Errorf("error parsing cleared tasks: %v", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("error reading cleared tasks: %v", err)
+	}
+
+	for _, task := range tasks {
+		archive = append(archive, clearedTask{ListID: listID, ClearedAt: now, Task: task})
+	}
+	data, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling cleared tasks: %v", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// completedIn returns the completed tasks at any depth, each with its subtree
+func completedIn(tasks []Task) []Task {
+	var completed []Task
+	for _, task := range tasks {
+		if task.Deleted {
+			continue
+		}
+		if task.Completed {
+			completed = append(completed, task)
+			continue
+		}
+		completed = append(completed, completedIn(task.Tasks)...)
+	}
+	return completed
+}
+
+// withoutCompleted returns the tree with completed tasks, and their
+// subtasks, left out
+func withoutCompleted(tasks []Task) []Task {
+	kept := make([]Task, 0, len(tasks))
+	for _, task := range tasks {
+		if task.Completed {
+			continue
+		}
+		task.Tasks = withoutCompleted(task.Tasks)
+		kept = append(kept, task)
+	}
+	return kept
+}
+
+// ClearCompleted archives the completed tasks of the given lists and has
+// Google clear them, returning the tree without them so they don't come
+// back on the next sync. It stops at the first list that fails. With a nil
+// client only the tree and the archive change.
+func ClearCompleted(tasks []Task, listIDs []string, client *GoogleTasksClient) ([]Task, int, error) {
+	now := time.Now()
+	cleared := 0
+	for _, listID := range listIDs {
+		list := findTask(tasks, listID)
+		if list == nil {
+			continue
+		}
+		completed := completedIn(list.Tasks)
+		if len(completed) == 0 {
+			continue
+		}
+		if err := archiveCleared(listID, completed, now); err != nil {
+			return tasks, cleared, err
+		}
+		if client != nil {
+			if err := client.clearCompleted(listID); err != nil {
+				return tasks, cleared, err
+			}
+		}
+		list.Tasks = withoutCompleted(list.Tasks)
+		cleared += len(completed)
+	}
+	return tasks, cleared, nil
+}
+
+// autoClearEnabled reports whether sync should clear completed tasks itself
+func autoClearEnabled() bool {
+	config := GetGlobalConfig()
+	return config != nil && config.AutoClearCompleted
+}
+
+// listIDs returns the IDs of the task lists at the top of a tree
+func listIDs(tasks []Task) []string {
+	var ids []string
+	for _, task := range tasks {
+		if task.Kind == "tasks#taskList" && !task.Deleted {
+			ids = append(ids, task.Id)
+		}
+	}
+	return ids
+}
+
+// clearTargets returns the lists X clears: the open list, or every list
+// from the top level
+func (m *model) clearTargets() []string {
+	if len(m.currentPath) > 0 {
+		return []string{m.currentPath[0].Id}
+	}
+	return listIDs(m.allTasks())
+}
+
+// promptClearCompleted asks before clearing completed tasks from Google
+func (m *model) promptClearCompleted() {
+	if m.googleTasks == nil {
+		m.statusMsg = "Clearing completed tasks only applies to Google Tasks"
+		return
+	}
+	count := 0
+	for _, id := range m.clearTargets() {
+		if list := m.findTask(id); list != nil {
+			count += len(completedIn(list.Tasks))
+		}
+	}
+	if count == 0 {
+		m.statusMsg = "No completed tasks to clear"
+		return
+	}
+
+	m.inputActive = true
+	m.inputAction = "clear_completed"
+	m.input.Placeholder = fmt.Sprintf("Type 'yes' to clear %d completed task(s) from Google", count)
+	m.input.SetValue("")
+	m.input.Focus()
+}
+
+// clearCompleted clears the completed tasks of the targeted lists. They
+// leave the tree and go to the archive at once; Google clears them in the
+// background.
+func (m *model) clearCompleted() {
+	var targets []string
+	for _, id := range m.clearTargets() {
+		if list := m.findTask(id); list != nil && len(completedIn(list.Tasks)) > 0 {
+			targets = append(targets, id)
+		}
+	}
+	tasks, cleared, err := ClearCompleted(m.allTasks(), targets, nil)
+	m.tasks, m.completedTasks = splitTasks(tasks)
+	// Google only clears the lists whose tasks made it into the archive
+	var done []string
+	for _, id := range targets {
+		if list := findTask(tasks, id); list != nil && len(completedIn(list.Tasks)) == 0 {
+			done = append(done, id)
+		}
+	}
+	if count := m.visibleCount(); m.cursor >= count {
+		m.cursor = max(count-1, 0)
+	}
+	m.saveTasks()
+
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Cleared %d completed task(s), then failed: %v", cleared, err)
+	} else {
+		m.statusMsg = fmt.Sprintf("Cleared %d completed task(s), kept in cleared.json", cleared)
+	}
+
+	client := m.googleTasks
+	goSync(func() {
+		for _, listID := range done {
+			if err := client.clearCompleted(listID); err != nil {
+				reportSyncError(err)
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/atotto/clipboard"
+)
+
+// copyToClipboard puts text on the system clipboard. The clipboard library
+// picks pbcopy, the Windows API or xclip/xsel/wl-copy depending on the platform.
+func copyToClipboard(text string) error {
+	if clipboard.Unsupported {
+		return fmt.Errorf("no clipboard utility found (install xclip, xsel or wl-clipboard)")
+	}
+	return clipboard.WriteAll(text)
+}
+
+// taskClipboardText formats a task for pasting: the title, then its notes and,
+// for Google tasks, the link to it
+func taskClipboardText(task Task) string {
+	parts := []string{task.Title}
+	if notes := strings.TrimSpace(task.Notes); notes != "" {
+		parts = append(parts, notes)
+	}
+	if UseGoogleTasks.Load() && task.SelfLink != "" {
+		parts = append(parts, task.SelfLink)
+	}
+	return strings.Join(parts, "\n\n")
+}
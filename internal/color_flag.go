@@ -0,0 +1,38 @@
+package internal
+
+import "github.com/charmbracelet/lipgloss"
+
+// colorFlags are the flag colors in the order F cycles through them
+var colorFlags = []struct {
+	name  string
+	color lipgloss.Color
+}{
+	{"red", lipgloss.Color("196")},
+	{"yellow", lipgloss.Color("226")},
+	{"green", lipgloss.Color("46")},
+	{"blue", lipgloss.Color("33")},
+}
+
+// nextColorFlag returns the flag after current, going back to no flag after the last
+func nextColorFlag(current string) string {
+	for i, flag := range colorFlags {
+		if flag.name == current {
+			if i+1 < len(colorFlags) {
+				return colorFlags[i+1].name
+			}
+			return ""
+		}
+	}
+	return colorFlags[0].name
+}
+
+// colorFlag returns a colored bullet to prefix task titles with, or nothing
+// for unflagged tasks
+func colorFlag(name string) string {
+	for _, flag := range colorFlags {
+		if flag.name == name {
+			return lipgloss.NewStyle().Foreground(flag.color).Render("●") + " "
+		}
+	}
+	return ""
+}
@@ -0,0 +1,163 @@
+package internal
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// setCompleted marks a task done or not done, keeping Status and
+// CompletedDate consistent with Completed
+func setCompleted(task *Task, completed bool, now time.Time) {
+	task.Completed = completed
+	task.AutoCompleted = false
+	// Done tasks aren't in progress, and reopened ones start over
+	task.InProgress = false
+	if completed {
+		task.Status = "completed"
+		task.CompletedDate = now
+	} else {
+		task.Status = "needsAction"
+		task.CompletedDate = time.Time{}
+	}
+	task.Updated = now
+}
+
+// cascadeCompletion brings the subtasks of a task that was just toggled in
+// line with it and returns the descendants that changed, so they can be synced.
+//
+// Completing a parent completes every descendant when CascadeCompletion is on.
+// Reopening a parent reopens every descendant only when ReactivateSubtasks is
+// on; otherwise subtasks that were done stay done.
+func cascadeCompletion(task *Task, now time.Time) []Task {
+	config := GetGlobalConfig()
+	if config == nil {
+		return nil
+	}
+	if task.Completed && !config.CascadeCompletion || !task.Completed && !config.ReactivateSubtasks {
+		return nil
+	}
+
+	var changed []Task
+	var walk func(tasks []Task)
+	walk = func(tasks []Task) {
+		for i := range tasks {
+			child := &tasks[i]
+			if child.Deleted {
+				continue
+			}
+			if child.Completed != task.Completed {
+				setCompleted(child, task.Completed, now)
+				changed = append(changed, *child)
+			}
+			walk(child.Tasks)
+		}
+	}
+	walk(task.Tasks)
+
+	return changed
+}
+
+// allSubtasksDone reports whether a task has subtasks and every direct one
+// is completed
+func allSubtasksDone(task Task) bool {
+	total := 0
+	for _, sub := range task.Tasks {
+		if sub.Deleted {
+			continue
+		}
+		if !sub.Completed {
+			return false
+		}
+		total++
+	}
+	return total > 0
+}
+
+// propagateCompletion updates the ancestors of a task that was just toggled
+// and returns the ones that changed. With AutoCompleteParents, a parent
+// whose direct subtasks are now all done is completed, which may in turn
+// complete its own parent, and reopening a subtask reopens the parents that
+// were completed this way. Parents completed by hand stay completed, and
+// blocked parents aren't completed. The top level holds lists in both modes,
+// so it is never completed this way.
+func propagateCompletion(tasks []Task, id string, now time.Time) []Task {
+	config := GetGlobalConfig()
+	if config == nil || !config.AutoCompleteParents {
+		return nil
+	}
+	path, found := findTaskByID(tasks, id)
+	if !found {
+		return nil
+	}
+	lookup := func(id string) *Task { return findTask(tasks, id) }
+
+	var changed []Task
+	for i := len(path) - 2; i >= 1; i-- {
+		parent := findTask(tasks, path[i].Id)
+		if parent == nil || parent.Kind == "tasks#taskList" {
+			break
+		}
+		switch done := allSubtasksDone(*parent); {
+		case !parent.Completed && done && !isBlocked(*parent, lookup):
+			setCompleted(parent, true, now)
+			parent.AutoCompleted = true
+		case parent.Completed && parent.AutoCompleted && !done:
+			setCompleted(parent, false, now)
+		default:
+			// Nothing changed here, so nothing above changes either
+			return changed
+		}
+		changed = append(changed, *parent)
+	}
+	return changed
+}
+
+// refileRoots splits the top level of tasks into m.tasks and
+// m.completedTasks, for when completing subtasks changed their parents
+func (m *model) refileRoots(tasks []Task) {
+	active := make([]Task, 0, len(tasks))
+	completed := make([]Task, 0)
+	for _, task := range tasks {
+		if task.Completed {
+			completed = append(completed, task)
+		} else {
+			active = append(active, task)
+		}
+	}
+	m.tasks, m.completedTasks = active, completed
+}
+
+// Orders for the completed section of a list, set with CompletedSort
+const (
+	CompletedRecent = "recent" // Most recently completed first
+	CompletedOldest = "oldest" // Earliest completed first
+	CompletedManual = "manual" // Keep the stored order
+)
+
+// sortCompleted orders completed tasks by CompletedDate as the CompletedSort
+// config asks, leaving the input untouched. Tasks without a completion date
+// go last either way.
+func sortCompleted(tasks []Task) []Task {
+	mode := CompletedRecent
+	if config := GetGlobalConfig(); config != nil && config.CompletedSort != "" {
+		mode = strings.ToLower(config.CompletedSort)
+	}
+	if mode != CompletedRecent && mode != CompletedOldest {
+		return tasks
+	}
+
+	sorted := make([]Task, len(tasks))
+	copy(sorted, tasks)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].CompletedDate, sorted[j].CompletedDate
+		if a.IsZero() != b.IsZero() {
+			return !a.IsZero()
+		}
+		if mode == CompletedOldest {
+			return a.Before(b)
+		}
+		return a.After(b)
+	})
+	return sorted
+}
@@ -0,0 +1,187 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+// twoLevelTree is a parent with two subtasks, the first of which has a
+// subtask of its own and the second of which is already done
+func twoLevelTree() []Task {
+	return []Task{{
+		Id: "parent", Title: "Parent", Status: "needsAction",
+		Tasks: []Task{
+			{Id: "child", Parent: "parent", Title: "Child", Status: "needsAction",
+				Tasks: []Task{{Id: "grandchild", Parent: "child", Title: "Grandchild", Status: "needsAction"}}},
+			{Id: "done", Parent: "parent", Title: "Done", Status: "completed", Completed: true},
+		},
+	}}
+}
+
+// completedIDs lists the IDs of every completed task in tasks, and checks
+// Status agrees with Completed on the way
+func completedIDs(t *testing.T, tasks []Task) map[string]bool {
+	t.Helper()
+	done := make(map[string]bool)
+	var walk func(tasks []Task)
+	walk = func(tasks []Task) {
+		for _, task := range tasks {
+			if task.Completed != (task.Status == "completed") {
+				t.Errorf("%s: Completed is %v but Status is %q", task.Id, task.Completed, task.Status)
+			}
+			if task.Completed {
+				done[task.Id] = true
+			}
+			walk(task.Tasks)
+		}
+	}
+	walk(tasks)
+	return done
+}
+
+func taskIDs(tasks []Task) map[string]bool {
+	ids := make(map[string]bool)
+	for _, task := range tasks {
+		ids[task.Id] = true
+	}
+	return ids
+}
+
+func TestCascadeCompletion(t *testing.T) {
+	defer SetGlobalConfig(GetGlobalConfig())
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		cascade     bool
+		reactivate  bool
+		reopen      bool // Complete the parent first, then reopen it
+		wantDone    []string
+		wantChanged []string
+	}{
+		{
+			name:        "completing cascades to every level",
+			cascade:     true,
+			wantDone:    []string{"parent", "child", "grandchild", "done"},
+			wantChanged: []string{"child", "grandchild"},
+		},
+		{
+			name:     "completing leaves subtasks without CascadeCompletion",
+			wantDone: []string{"parent", "done"},
+		},
+		{
+			name:        "reopening reactivates every level",
+			cascade:     true,
+			reactivate:  true,
+			reopen:      true,
+			wantChanged: []string{"child", "grandchild", "done"},
+		},
+		{
+			name:     "reopening keeps subtasks done without ReactivateSubtasks",
+			cascade:  true,
+			reopen:   true,
+			wantDone: []string{"child", "grandchild", "done"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetGlobalConfig(&GodoConfig{CascadeCompletion: tt.cascade, ReactivateSubtasks: tt.reactivate})
+			tasks := twoLevelTree()
+			parent := &tasks[0]
+
+			setCompleted(parent, true, now)
+			changed := cascadeCompletion(parent, now)
+			if tt.reopen {
+				setCompleted(parent, false, now)
+				changed = cascadeCompletion(parent, now)
+			}
+
+			done := completedIDs(t, tasks)
+			if len(done) != len(tt.wantDone) {
+				t.Errorf("completed %v, want %v", done, tt.wantDone)
+			}
+			for _, id := range tt.wantDone {
+				if !done[id] {
+					t.Errorf("%s isn't completed, want completed %v", id, tt.wantDone)
+				}
+			}
+
+			got := taskIDs(changed)
+			if len(got) != len(tt.wantChanged) {
+				t.Errorf("changed %v, want %v", got, tt.wantChanged)
+			}
+			for _, id := range tt.wantChanged {
+				if !got[id] {
+					t.Errorf("%s isn't reported as changed, want %v", id, tt.wantChanged)
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteCascadesToSubtasks(t *testing.T) {
+	tasks := append(twoLevelTree(), Task{Id: "other", Title: "Other"})
+
+	tasks = removeTaskByID(tasks, "parent")
+
+	for _, id := range []string{"parent", "child", "grandchild", "done"} {
+		if findTask(tasks, id) != nil {
+			t.Errorf("%s is still in the tree after deleting its ancestor", id)
+		}
+	}
+	if findTask(tasks, "other") == nil {
+		t.Error("deleting parent removed an unrelated task")
+	}
+}
+
+func TestPropagateCompletion(t *testing.T) {
+	defer SetGlobalConfig(GetGlobalConfig())
+	SetGlobalConfig(&GodoConfig{AutoCompleteParents: true})
+	now := time.Now()
+
+	// Google lists have their own Kind; local ones are plain top-level tasks
+	for _, listKind := range []string{"tasks#taskList", "tasks#task"} {
+		t.Run(listKind, func(t *testing.T) {
+			tasks := []Task{{
+				Id: "list", Title: "List", Kind: listKind, Status: "needsAction",
+				Tasks: []Task{{
+					Id: "grandparent", Parent: "list", Title: "Grandparent", Kind: "tasks#task", Status: "needsAction",
+					Tasks: []Task{{
+						Id: "parent", Parent: "grandparent", Title: "Parent", Kind: "tasks#task", Status: "needsAction",
+						Tasks: []Task{{Id: "child", Parent: "parent", Title: "Child", Kind: "tasks#task", Status: "needsAction"}},
+					}},
+				}},
+			}}
+
+			setCompleted(findTask(tasks, "child"), true, now)
+			changed := propagateCompletion(tasks, "child", now)
+			done := completedIDs(t, tasks)
+			for _, id := range []string{"child", "parent", "grandparent"} {
+				if !done[id] {
+					t.Errorf("completing the child left %s open", id)
+				}
+			}
+			if done["list"] {
+				t.Error("completing the last task in a list completed the list")
+			}
+			if got := taskIDs(changed); len(got) != 2 || !got["parent"] || !got["grandparent"] {
+				t.Errorf("completing changed %v, want parent and grandparent", got)
+			}
+
+			setCompleted(findTask(tasks, "child"), false, now)
+			changed = propagateCompletion(tasks, "child", now)
+			if done := completedIDs(t, tasks); len(done) != 0 {
+				t.Errorf("reopening the child left %v completed", done)
+			}
+			if got := taskIDs(changed); len(got) != 2 || !got["parent"] || !got["grandparent"] {
+				t.Errorf("reopening changed %v, want parent and grandparent", got)
+			}
+			for _, id := range []string{"parent", "grandparent"} {
+				if findTask(tasks, id).AutoCompleted {
+					t.Errorf("%s is reopened but still marked as auto-completed", id)
+				}
+			}
+		})
+	}
+}
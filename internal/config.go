@@ -6,30 +6,122 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 )
 
 // GodoConfig struct with field names that match the config keys
 type GodoConfig struct {
-	StoragePath             string `config:"StoragePath"`
-	GoogleClientID          string `config:"GoogleClientID"`
-	GoogleClientSecret      string `config:"GoogleClientSecret"`
-	GoogleTokenPath         string `config:"GoogleTokenPath"`
+	StoragePath               string `config:"StoragePath"`
+	Profile                   string `config:"Profile"`
+	GoogleClientID            string `config:"GoogleClientID"`
+	GoogleClientSecret        string `config:"GoogleClientSecret"`
+	GoogleTokenPath           string `config:"GoogleTokenPath"`
+	ServerAddr                string `config:"ServerAddr"`
+	TombstoneDays             int    `config:"TombstoneDays"`
+	ShowCompleted             bool   `config:"ShowCompleted"`
+	CompletionBell            bool   `config:"CompletionBell"`
+	CascadeCompletion         bool   `config:"CascadeCompletion"`
+	ReactivateSubtasks        bool   `config:"ReactivateSubtasks"`
+	DateFormat                string `config:"DateFormat"`
+	SyncIntervalSeconds       int    `config:"SyncIntervalSeconds"`
+	OverdueNotifications      bool   `config:"OverdueNotifications"`
+	ConfirmComplete           bool   `config:"ConfirmComplete"`
+	ShowWelcome               bool   `config:"ShowWelcome"`
+	SubtaskCountRecursive     bool   `config:"SubtaskCountRecursive"`
+	DeepSubtasks              string `config:"DeepSubtasks"`
+	ErrorLogFile              bool   `config:"ErrorLogFile"`
+	DetailsPanelWidth         string `config:"DetailsPanelWidth"`
+	DetailsPanelLayout        string `config:"DetailsPanelLayout"`
+	AutoClearCompleted        bool   `config:"AutoClearCompleted"`
+	DueDateInput              string `config:"DueDateInput"`
+	EscSavesInput             bool   `config:"EscSavesInput"`
+	CompletedSort             string `config:"CompletedSort"`
+	WorkingDays               string `config:"WorkingDays"`
+	WorkingHours              string `config:"WorkingHours"`
+	EnterOnLeaf               string `config:"EnterOnLeaf"`
+	TrashDays                 int    `config:"TrashDays"`
+	AutoCompleteParents       bool   `config:"AutoCompleteParents"`
+	UseGoogleTasks            bool   `config:"UseGoogleTasks"`
+	IDScheme                  string `config:"IDScheme"`
+	StaleAfterDays            int    `config:"StaleAfterDays"`
+	AccentColor               string `config:"AccentColor"`
+	QuitWaitSeconds           int    `config:"QuitWaitSeconds"`
+	AdvancedMode              bool   `config:"AdvancedMode"`
+	CollapseCompleted         bool   `config:"CollapseCompleted"`
+	CalDAVURL                 string `config:"CalDAVURL"`
+	CalDAVUsername            string `config:"CalDAVUsername"`
+	CalDAVPassword            string `config:"CalDAVPassword"`
+	RemoteChangeNotifications bool   `config:"RemoteChangeNotifications"`
+	CacheTTLHours             int    `config:"CacheTTLHours"`
+	OAuthCallbackPort         int    `config:"OAuthCallbackPort"`
+	WrapWidth                 int    `config:"WrapWidth"`
+	TerminalTitle             bool   `config:"TerminalTitle"`
+	ListSort                  string `config:"ListSort"`
+	ListOrder                 string `config:"ListOrder"`
+	GoogleAccount             string `config:"GoogleAccount"`
 }
 
 // Default configuration values as a map
 func defaultConfigMap() map[string]string {
 	return map[string]string{
-		"StoragePath":             "$HOME/.local/share/godo",
-		"GoogleClientID":          "",
-		"GoogleClientSecret":      "",
-		"GoogleTokenPath":         "$HOME/.local/share/godo/google_token.json",
+		"StoragePath":               "$HOME/.local/share/godo",
+		"Profile":                   "",
+		"GoogleClientID":            "",
+		"GoogleClientSecret":        "",
+		"GoogleTokenPath":           "$HOME/.local/share/godo/google_token.json",
+		"ServerAddr":                "127.0.0.1:8787",
+		"TombstoneDays":             "30",
+		"ShowCompleted":             "true",
+		"CompletionBell":            "false",
+		"CascadeCompletion":         "true",
+		"ReactivateSubtasks":        "false",
+		"DateFormat":                "2006-01-02 15:04",
+		"SyncIntervalSeconds":       "30",
+		"OverdueNotifications":      "false",
+		"ConfirmComplete":           "false",
+		"ShowWelcome":               "true",
+		"SubtaskCountRecursive":     "false",
+		"DeepSubtasks":              "notes",
+		"ErrorLogFile":              "true",
+		"DetailsPanelWidth":         "33%",
+		"DetailsPanelLayout":        "side",
+		"AutoClearCompleted":        "false",
+		"DueDateInput":              "text",
+		"EscSavesInput":             "false",
+		"CompletedSort":             "recent",
+		"WorkingDays":               "",
+		"WorkingHours":              "",
+		"EnterOnLeaf":               "descend",
+		"TrashDays":                 "30",
+		"AutoCompleteParents":       "false",
+		"UseGoogleTasks":            "false",
+		"IDScheme":                  "short",
+		"StaleAfterDays":            "0",
+		"AccentColor":               defaultAccent,
+		"QuitWaitSeconds":           "10",
+		"AdvancedMode":              "false",
+		"CollapseCompleted":         "false",
+		"CalDAVURL":                 "",
+		"CalDAVUsername":            "",
+		"CalDAVPassword":            "",
+		"RemoteChangeNotifications": "false",
+		"CacheTTLHours":             "24",
+		"OAuthCallbackPort":         "8080",
+		"WrapWidth":                 "0",
+		"TerminalTitle":             "false",
+		"ListSort":                  "default",
+		"ListOrder":                 "",
+		"GoogleAccount":             "",
 	}
 }
 
 var globalConfig *GodoConfig
 
+// configFilePath is where the loaded config lives, for commands that edit it
+var configFilePath string
+
 func SetGlobalConfig(config *GodoConfig) {
 	globalConfig = config
 }
@@ -41,13 +133,18 @@ func GetGlobalConfig() *GodoConfig {
 // LoadConfig reads or creates the config file, adds missing fields, and returns the populated CurdConfig struct
 func LoadConfig(configPath string) (GodoConfig, error) {
 	configPath = os.ExpandEnv(configPath) // Substitute environment variables like $HOME
+	configFilePath = configPath
 
 	// Check if config file exists
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		// Create the config file with default values if it doesn't exist
 		fmt.Println("Config file not found. Creating default config...")
 		if err := createDefaultConfig(configPath); err != nil {
-			return GodoConfig{}, fmt.Errorf("error creating default config file: %v", err)
+			// Not being able to write the file shouldn't stop godo from running
+			fmt.Printf("Warning: could not create default config, using defaults: %v\n", err)
+			config := populateConfig(defaultConfigMap())
+			SetGlobalConfig(&config)
+			return config, nil
 		}
 	}
 
@@ -57,6 +154,20 @@ func LoadConfig(configPath string) (GodoConfig, error) {
 		return GodoConfig{}, fmt.Errorf("error loading config file: %v", err)
 	}
 
+	// Fill in keys added since the file was written and save them so they can be edited
+	missing := make(map[string]string)
+	for key, value := range defaultConfigMap() {
+		if _, exists := configMap[key]; !exists {
+			configMap[key] = value
+			missing[key] = value
+		}
+	}
+	if len(missing) > 0 {
+		if err := appendConfigKeys(configPath, missing); err != nil {
+			fmt.Printf("Warning: could not add new settings to config: %v\n", err)
+		}
+	}
+
 	// Populate config struct
 	config := populateConfig(configMap)
 	
@@ -83,17 +194,77 @@ func createDefaultConfig(path string) error {
 	}
 	defer file.Close()
 
-	writer := bufio.NewWriter(file)
-	for key, value := range defaultConfig {
-		line := fmt.Sprintf("%s=%s\n", key, value)
-		if _, err := writer.WriteString(line); err != nil {
-			return fmt.Errorf("error writing to file: %v", err)
+	if err := writeConfigLines(file, defaultConfig); err != nil {
+		return fmt.Errorf("error writing to file: %v", err)
+	}
+	return nil
+}
+
+// appendConfigKeys adds settings to the end of an existing config file,
+// leaving the user's lines and comments untouched
+func appendConfigKeys(path string, configMap map[string]string) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return writeConfigLines(file, configMap)
+}
+
+// setConfigValues updates keys in the config file in place, appending any
+// that aren't there yet, and keeps everything else as the user wrote it
+func setConfigValues(path string, values map[string]string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	remaining := make(map[string]string, len(values))
+	for key, value := range values {
+		remaining[key] = value
+	}
+
+	var lines []string
+	if len(data) > 0 {
+		lines = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	}
+	for i, line := range lines {
+		key, _, found := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if value, ok := remaining[key]; found && ok {
+			lines[i] = key + "=" + value
+			delete(remaining, key)
 		}
 	}
-	if err := writer.Flush(); err != nil {
-		return fmt.Errorf("error flushing writer: %v", err)
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
 	}
-	return nil
+	defer file.Close()
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(file, line); err != nil {
+			return err
+		}
+	}
+	return writeConfigLines(file, remaining)
+}
+
+// writeConfigLines writes key=value lines sorted by key so files are stable
+func writeConfigLines(file *os.File, configMap map[string]string) error {
+	keys := make([]string, 0, len(configMap))
+	for key := range configMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	writer := bufio.NewWriter(file)
+	for _, key := range keys {
+		if _, err := fmt.Fprintf(writer, "%s=%s\n", key, configMap[key]); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
 }
 
 // Load config file from disk into a map (key=value format)
@@ -107,18 +278,21 @@ func loadConfigFromFile(path string) (map[string]string, error) {
 	configMap := make(map[string]string)
 	scanner := bufio.NewScanner(file)
 
+	lineNumber := 0
 	for scanner.Scan() {
+		lineNumber++
 		line := strings.TrimSpace(scanner.Text())
 		if line == "" || strings.HasPrefix(line, "#") {
 			continue // Skip empty lines and comments
 		}
 
+		// Anything else must be a key=value pair, or the file is corrupt
 		parts := strings.SplitN(line, "=", 2)
-		if len(parts) == 2 {
-			key := strings.TrimSpace(parts[0])
-			value := strings.TrimSpace(parts[1])
-			configMap[key] = value
+		key := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || key == "" {
+			return nil, fmt.Errorf("%s:%d: expected key=value, got %q", path, lineNumber, line)
 		}
+		configMap[key] = strings.TrimSpace(parts[1])
 	}
 
 	if err := scanner.Err(); err != nil {
@@ -136,14 +310,7 @@ func saveConfigToFile(path string, configMap map[string]string) error {
 	}
 	defer file.Close()
 
-	writer := bufio.NewWriter(file)
-	for key, value := range configMap {
-		line := fmt.Sprintf("%s=%s\n", key, value)
-		if _, err := writer.WriteString(line); err != nil {
-			return err
-		}
-	}
-	return writer.Flush()
+	return writeConfigLines(file, configMap)
 }
 
 // Populate the CurdConfig struct from a map
@@ -0,0 +1,168 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// configEditedMsg reports that the editor opened on the config file exited
+type configEditedMsg struct {
+	err error
+}
+
+// editorCommand opens path in $VISUAL or $EDITOR, falling back to vi. The
+// variable may carry arguments, as in "code --wait".
+func editorCommand(path string) *exec.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{"vi"}
+	}
+	return exec.Command(args[0], append(args[1:], path)...)
+}
+
+// EditConfig opens the config file at path in the editor, creating it with
+// the defaults first if needed, and checks it once the editor exits, for
+// godo config edit
+func EditConfig(path string) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := createDefaultConfig(path); err != nil {
+			return err
+		}
+	}
+	cmd := editorCommand(path)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("error running editor: %v", err)
+	}
+	configMap, err := loadConfigFromFile(path)
+	if err != nil {
+		return err
+	}
+	return validateConfig(configMap)
+}
+
+// restartOnlyKeys are the settings godo only reads at startup, to pick the
+// storage, profile and accounts, so a reload can't apply them
+var restartOnlyKeys = map[string]bool{
+	"StoragePath":        true,
+	"Profile":            true,
+	"UseGoogleTasks":     true,
+	"GoogleClientID":     true,
+	"GoogleClientSecret": true,
+	"GoogleTokenPath":    true,
+	"GoogleAccount":      true,
+	"OAuthCallbackPort":  true,
+	"CalDAVURL":          true,
+	"CalDAVUsername":     true,
+	"CalDAVPassword":     true,
+	"ServerAddr":         true,
+}
+
+// ReloadConfig reads the config file again and makes it the global config.
+// A file that doesn't parse leaves the current config in place. Restart-only
+// settings keep their current values, and the keys of those changed in the
+// file are returned so the user can be told to restart.
+func ReloadConfig() (GodoConfig, []string, error) {
+	configMap, err := loadConfigFromFile(configFilePath)
+	if err != nil {
+		return GodoConfig{}, nil, err
+	}
+	if err := validateConfig(configMap); err != nil {
+		return GodoConfig{}, nil, err
+	}
+	for key, value := range defaultConfigMap() {
+		if _, exists := configMap[key]; !exists {
+			configMap[key] = value
+		}
+	}
+	config := populateConfig(configMap)
+	var held []string
+	if current := GetGlobalConfig(); current != nil {
+		held = keepRestartOnly(&config, *current)
+	}
+	SetGlobalConfig(&config)
+	return config, held, nil
+}
+
+// keepRestartOnly copies the restart-only settings of current into config,
+// returning the keys where config had a different value
+func keepRestartOnly(config *GodoConfig, current GodoConfig) []string {
+	var held []string
+	next, running := reflect.ValueOf(config).Elem(), reflect.ValueOf(current)
+	for i := 0; i < next.NumField(); i++ {
+		key := next.Type().Field(i).Tag.Get("config")
+		if !restartOnlyKeys[key] {
+			continue
+		}
+		if !reflect.DeepEqual(next.Field(i).Interface(), running.Field(i).Interface()) {
+			held = append(held, key)
+		}
+		next.Field(i).Set(running.Field(i))
+	}
+	return held
+}
+
+// validateConfig checks that numbers and true/false settings parse, which
+// populateConfig would otherwise quietly read as zero or false
+func validateConfig(configMap map[string]string) error {
+	configType := reflect.TypeOf(GodoConfig{})
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		key := field.Tag.Get("config")
+		value, exists := configMap[key]
+		if !exists {
+			continue
+		}
+		switch field.Type.Kind() {
+		case reflect.Int:
+			if _, err := strconv.Atoi(value); err != nil {
+				return fmt.Errorf("%s must be a whole number, not %q", key, value)
+			}
+		case reflect.Bool:
+			if _, err := strconv.ParseBool(value); err != nil {
+				return fmt.Errorf("%s must be true or false, not %q", key, value)
+			}
+		}
+	}
+	return nil
+}
+
+// editConfig suspends the UI while the config file is open in the editor
+func (m *model) editConfig() tea.Cmd {
+	if configFilePath == "" {
+		m.statusMsg = "No config file loaded"
+		return nil
+	}
+	return tea.ExecProcess(editorCommand(configFilePath), func(err error) tea.Msg {
+		return configEditedMsg{err: err}
+	})
+}
+
+// reloadConfig applies the edited config, keeping the old one if the file
+// doesn't parse
+func (m *model) reloadConfig(msg configEditedMsg) {
+	if msg.err != nil {
+		m.statusMsg = "Error running editor: " + msg.err.Error()
+		return
+	}
+	_, held, err := ReloadConfig()
+	if err != nil {
+		m.statusMsg = "Config not reloaded: " + err.Error()
+		return
+	}
+	m.applyListSettings()
+	m.statusMsg = "Reloaded the config"
+	if len(held) > 0 {
+		m.statusMsg += "; restart godo to apply " + strings.Join(held, ", ")
+	}
+}
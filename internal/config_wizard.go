@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// dateFormatChoices are the date formats the setup wizard offers by number
+var dateFormatChoices = []string{"2006-01-02 15:04", "02/01/2006 15:04", "01/02/2006 3:04 PM", "Jan 2 2006 15:04"}
+
+// accentChoices are the themes the setup wizard offers, by cursor color
+var accentChoices = []struct {
+	Name  string
+	Color string
+}{
+	{"teal", defaultAccent},
+	{"blue", "33"},
+	{"green", "42"},
+	{"orange", "208"},
+	{"pink", "205"},
+}
+
+// RunConfigWizard asks for the storage location, date format and theme and
+// saves the answers to the config file. Pressing enter keeps the current
+// value, and keys the wizard doesn't ask about are left alone.
+func RunConfigWizard(config *GodoConfig, in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	ask := func(prompt, current string) (string, error) {
+		fmt.Fprintf(out, "%s [%s]: ", prompt, current)
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("setup cancelled")
+		}
+		if value := strings.TrimSpace(line); value != "" {
+			return value, nil
+		}
+		return current, nil
+	}
+
+	defaults := defaultConfigMap()
+	if config.StoragePath != defaults["StoragePath"] || config.DateFormat != defaults["DateFormat"] || config.AccentColor != defaults["AccentColor"] {
+		answer, err := ask("godo is already set up. Reconfigure it? (y/n)", "n")
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(strings.ToLower(answer), "y") {
+			return nil
+		}
+	}
+
+	fmt.Fprintln(out, "Where should godo keep its tasks? $HOME and other variables are expanded.")
+	storagePath, err := ask("Storage directory", config.StoragePath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(os.ExpandEnv(storagePath), 0755); err != nil {
+		return fmt.Errorf("error creating %s: %v", storagePath, err)
+	}
+
+	fmt.Fprintln(out, "\nHow should dates look? Pick a number or type a Go time layout.")
+	now := time.Now()
+	for i, format := range dateFormatChoices {
+		fmt.Fprintf(out, "  %d. %s\n", i+1, now.Format(format))
+	}
+	dateFormat, err := ask("Date format", config.DateFormat)
+	if err != nil {
+		return err
+	}
+	var choice int
+	if _, err := fmt.Sscanf(dateFormat, "%d", &choice); err == nil && choice >= 1 && choice <= len(dateFormatChoices) {
+		dateFormat = dateFormatChoices[choice-1]
+	}
+
+	fmt.Fprintln(out, "\nPick a theme by name or type a terminal color number. Lists can still set their own accent.")
+	names := make([]string, len(accentChoices))
+	for i, accent := range accentChoices {
+		names[i] = accent.Name
+	}
+	fmt.Fprintln(out, "  "+strings.Join(names, ", "))
+	accent, err := ask("Theme", accentName(config.AccentColor))
+	if err != nil {
+		return err
+	}
+	for _, choice := range accentChoices {
+		if strings.EqualFold(accent, choice.Name) {
+			accent = choice.Color
+		}
+	}
+
+	if err := setConfigValues(configFilePath, map[string]string{
+		"StoragePath": storagePath,
+		"DateFormat":  dateFormat,
+		"AccentColor": accent,
+	}); err != nil {
+		return fmt.Errorf("error saving config: %v", err)
+	}
+	config.StoragePath = storagePath
+	config.DateFormat = dateFormat
+	config.AccentColor = accent
+	fmt.Fprintf(out, "\nSaved to %s\n", configFilePath)
+	return nil
+}
+
+// accentName returns the theme name of a color, or the color itself
+func accentName(color string) string {
+	for _, choice := range accentChoices {
+		if choice.Color == color {
+			return choice.Name
+		}
+	}
+	return color
+}
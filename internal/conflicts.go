@@ -0,0 +1,229 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"google.golang.org/api/googleapi"
+	v1 "google.golang.org/api/tasks/v1"
+)
+
+// ConflictError reports an update Google refused because the task changed
+// elsewhere since godo last fetched it
+type ConflictError struct {
+	ListID string
+	Local  Task // The version godo tried to save
+	Remote Task // The version now in Google, without subtasks
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%q was changed elsewhere", e.Local.Title)
+}
+
+// isPreconditionFailed reports whether Google rejected a call because the
+// etag sent with it is out of date
+func isPreconditionFailed(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusPreconditionFailed
+}
+
+// conflict fetches the current version of a task whose update was refused
+func (c *GoogleTasksClient) conflict(listID string, local Task, cause error) error {
+	var remote *v1.Task
+	err := withRetry("get task", func() error {
+		var err error
+		remote, err = c.service.Tasks.Get(listID, local.Id).Do()
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("%v, and fetching the other version failed: %v", cause, err)
+	}
+	return &ConflictError{ListID: listID, Local: local, Remote: taskFromGoogle(remote)}
+}
+
+// latestEtag returns the etag to send with an update: the one Google gave
+// this client's last update of the task, or else the fetched one
+func (c *GoogleTasksClient) latestEtag(task Task) string {
+	c.etagsMu.Lock()
+	defer c.etagsMu.Unlock()
+	if etag, ok := c.etags[task.Id]; ok {
+		return etag
+	}
+	return task.Etag
+}
+
+// rememberEtag records the etag of a task after this client changed it, as
+// the copies in the UI still carry the old one
+func (c *GoogleTasksClient) rememberEtag(id, etag string) {
+	c.etagsMu.Lock()
+	defer c.etagsMu.Unlock()
+	if c.etags == nil {
+		c.etags = make(map[string]string)
+	}
+	c.etags[id] = etag
+}
+
+// forgetEtags drops the remembered etags once fresh tasks are fetched
+func (c *GoogleTasksClient) forgetEtags() {
+	c.etagsMu.Lock()
+	defer c.etagsMu.Unlock()
+	c.etags = nil
+}
+
+// addConflict queues a conflict for the user to resolve, replacing an older
+// one for the same task
+func (m *model) addConflict(conflict ConflictError) {
+	for i, queued := range m.conflicts {
+		if queued.Local.Id == conflict.Local.Id {
+			m.conflicts[i] = conflict
+			return
+		}
+	}
+	m.conflicts = append(m.conflicts, conflict)
+}
+
+// conflictHint tells the user there are conflicts waiting, or is empty
+func (m *model) conflictHint() string {
+	if len(m.conflicts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("⚠ %d sync conflict(s), press ! to resolve", len(m.conflicts))
+}
+
+// conflictKey handles keys while a conflict is shown
+func (m *model) conflictKey(key string) {
+	conflict := m.conflicts[0]
+	switch key {
+	case "m":
+		m.resolveConflict(conflict, m.currentVersion(conflict.Local))
+	case "t":
+		m.keepTheirs(conflict)
+	case "b":
+		m.resolveConflict(conflict, mergeConflict(m.currentVersion(conflict.Local), conflict.Remote))
+	case "s":
+		// Look at the next one first
+		m.conflicts = append(m.conflicts[1:], conflict)
+		return
+	case "esc":
+		m.showConflict = false
+		return
+	default:
+		return
+	}
+	m.conflicts = m.conflicts[1:]
+	if len(m.conflicts) == 0 {
+		m.showConflict = false
+	}
+}
+
+// currentVersion returns the task as it is in the UI now, which may have
+// been edited again since the conflicting update
+func (m *model) currentVersion(task Task) Task {
+	if current := m.findTask(task.Id); current != nil {
+		return *current
+	}
+	return task
+}
+
+// resolveConflict saves task over the version in Google and in the UI
+func (m *model) resolveConflict(conflict ConflictError, task Task) {
+	m.replaceTask(task)
+	client := m.googleTasks
+	if client == nil {
+		return
+	}
+	goSync(func() {
+		// Overwrite the version the user has now seen
+		client.rememberEtag(task.Id, conflict.Remote.Etag)
+		if err := client.UpdateTask(task, conflict.ListID); err != nil {
+			reportSyncError(err)
+		}
+	})
+	m.statusMsg = "Saved your version of " + task.Title
+}
+
+// keepTheirs replaces the task in the UI with the version from Google
+func (m *model) keepTheirs(conflict ConflictError) {
+	remote := conflict.Remote
+	remote.Tasks = m.currentVersion(conflict.Local).Tasks
+	m.replaceTask(remote)
+	if m.googleTasks != nil {
+		m.googleTasks.rememberEtag(remote.Id, remote.Etag)
+	}
+	m.statusMsg = "Kept the other version of " + remote.Title
+}
+
+// replaceTask swaps a task in the tree for task, keeping its subtasks where
+// they are, and moves top-level tasks between the active and completed ones
+func (m *model) replaceTask(task Task) {
+	current := m.findTask(task.Id)
+	if current == nil {
+		return
+	}
+	task.Tasks = current.Tasks
+	*current = task
+	m.refileRoots(m.allTasks())
+	m.dirty = true
+}
+
+// mergeConflict combines both versions of a task: the local title, due date
+// and status win, notes written on either side are kept, and a due date set
+// only elsewhere is taken
+func mergeConflict(local, remote Task) Task {
+	merged := local
+	if remote.Notes != "" && remote.Notes != local.Notes && !strings.Contains(local.Notes, remote.Notes) {
+		if merged.Notes != "" {
+			merged.Notes += "\n\n"
+		}
+		merged.Notes += remote.Notes
+	}
+	if merged.DueDate.IsZero() {
+		merged.DueDate = remote.DueDate
+	}
+	merged.Updated = time.Now()
+	return merged
+}
+
+// renderConflict shows the first queued conflict side by side
+func (m *model) renderConflict() string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	bold := lipgloss.NewStyle().Bold(true)
+	conflict := m.conflicts[0]
+	local := m.currentVersion(conflict.Local)
+	remote := conflict.Remote
+
+	version := func(heading string, task Task) string {
+		status := "open"
+		if task.Completed {
+			status = "done"
+		}
+		due := "none"
+		if !task.DueDate.IsZero() {
+			due = formatDate(task.DueDate)
+		}
+		return strings.Join([]string{
+			bold.Render(heading),
+			"Title:  " + task.Title,
+			"Status: " + status,
+			"Due:    " + due,
+			"Notes:  " + task.Notes,
+		}, "\n")
+	}
+	width := 40
+	if m.width > 0 {
+		width = max(m.width/2-2, 20)
+	}
+	column := lipgloss.NewStyle().Width(width)
+
+	var s strings.Builder
+	s.WriteString(bold.Render(fmt.Sprintf("Sync conflict 1 of %d", len(m.conflicts))) + "\n")
+	s.WriteString(dim.Render(local.Title+" was changed here and elsewhere") + "\n\n")
+	s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top,
+		column.Render(version("Yours", local)), "  ", column.Render(version("Theirs", remote))) + "\n")
+	s.WriteString("\n" + dim.Render("m: Keep mine  t: Keep theirs  b: Merge both  s: Skip  esc: Later"))
+	return s.String()
+}
@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// csvHeader lists the columns written by ExportCSV
+var csvHeader = []string{"id", "title", "notes", "status", "completed", "due_date", "created", "parent", "depth", "list"}
+
+// ExportCSV writes the task tree as one flat row per task. The parent and
+// depth columns keep the hierarchy recoverable; task list containers become
+// the list column rather than rows of their own.
+func ExportCSV(w io.Writer, tasks []Task) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	var walk func(tasks []Task, parent, list string, depth int) error
+	walk = func(tasks []Task, parent, list string, depth int) error {
+		for _, task := range tasks {
+			if task.Deleted {
+				continue
+			}
+			if task.Kind == "tasks#taskList" {
+				if err := walk(task.Tasks, "", task.Title, depth); err != nil {
+					return err
+				}
+				continue
+			}
+
+			row := []string{
+				task.Id,
+				task.Title,
+				task.Notes,
+				task.Status,
+				strconv.FormatBool(task.Completed),
+				csvTime(task.DueDate),
+				csvTime(task.CreatedAt),
+				parent,
+				strconv.Itoa(depth),
+				list,
+			}
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+			if err := walk(task.Tasks, task.Id, list, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(tasks, "", "", 0); err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvTime formats t as ISO-8601, leaving unset times empty
+func csvTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,213 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Ways of entering a due date with t, set with DueDateInput
+const (
+	DueInputText   = "text"   // Type the date into the text input
+	DueInputPicker = "picker" // Pick it with the arrow keys
+)
+
+// Fields of the date picker, in the order left and right move through them
+const (
+	pickerDay = iota
+	pickerMonth
+	pickerYear
+	pickerHour
+	pickerMinute
+	pickerFieldCount
+)
+
+// pickerMinuteStep is how far up and down move the minutes
+const pickerMinuteStep = 5
+
+// datePicker edits a date and time one field at a time
+type datePicker struct {
+	value time.Time
+	field int
+}
+
+// newDatePicker starts from the current due date, or today at the default
+// due time when there is none
+func newDatePicker(current, now time.Time) datePicker {
+	if current.IsZero() {
+		current = time.Date(now.Year(), now.Month(), now.Day(), defaultDueHour, defaultDueMinute, 0, 0, time.Local)
+	}
+	return datePicker{value: current.Local(), field: pickerDay}
+}
+
+// dueDateInput returns the configured way of entering due dates
+func dueDateInput() string {
+	if config := GetGlobalConfig(); config != nil && strings.EqualFold(config.DueDateInput, DueInputPicker) {
+		return DueInputPicker
+	}
+	return DueInputText
+}
+
+// addMonths moves t by months, clamping the day so Jan 31 becomes Feb 28
+// instead of rolling over into March
+func addMonths(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), 0, 0, t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// step moves the focused field by delta
+func (p *datePicker) step(delta int) {
+	switch p.field {
+	case pickerDay:
+		p.value = p.value.AddDate(0, 0, delta)
+	case pickerMonth:
+		p.value = addMonths(p.value, delta)
+	case pickerYear:
+		p.value = addMonths(p.value, 12*delta)
+	case pickerHour:
+		p.value = p.value.Add(time.Duration(delta) * time.Hour)
+	case pickerMinute:
+		p.value = p.value.Add(time.Duration(delta*pickerMinuteStep) * time.Minute)
+	}
+}
+
+// Update handles a key while the picker is open. done reports that enter or
+// esc closed it, and ok that enter confirmed the value.
+func (p datePicker) Update(msg tea.KeyMsg) (picker datePicker, done, ok bool) {
+	switch msg.String() {
+	case "esc":
+		return p, true, false
+	case "enter":
+		return p, true, true
+	case "left", "h", "shift+tab":
+		p.field = (p.field + pickerFieldCount - 1) % pickerFieldCount
+	case "right", "l", "tab":
+		p.field = (p.field + 1) % pickerFieldCount
+	case "up", "k", "+":
+		p.step(1)
+	case "down", "j", "-":
+		p.step(-1)
+	case "pgup":
+		p.value = addMonths(p.value, -1)
+	case "pgdown":
+		p.value = addMonths(p.value, 1)
+	case "t":
+		now := time.Now()
+		p.value = time.Date(now.Year(), now.Month(), now.Day(), p.value.Hour(), p.value.Minute(), 0, 0, time.Local)
+	}
+	return p, false, false
+}
+
+// View draws the fields with the focused one highlighted, and the month
+// around the picked day
+func (p datePicker) View(accent lipgloss.Color) string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	focused := lipgloss.NewStyle().Reverse(true).Foreground(accent)
+
+	fields := [pickerFieldCount]string{
+		fmt.Sprintf("%02d", p.value.Day()),
+		p.value.Format("Jan"),
+		fmt.Sprintf("%d", p.value.Year()),
+		fmt.Sprintf("%02d", p.value.Hour()),
+		fmt.Sprintf("%02d", p.value.Minute()),
+	}
+	for i := range fields {
+		if i == p.field {
+			fields[i] = focused.Render(fields[i])
+		}
+	}
+
+	var s strings.Builder
+	s.WriteString(fmt.Sprintf("%s %s %s %s  %s:%s\n\n",
+		p.value.Format("Mon"), fields[pickerDay], fields[pickerMonth], fields[pickerYear], fields[pickerHour], fields[pickerMinute]))
+
+	// Weeks start on Monday, as in the calendar
+	first := time.Date(p.value.Year(), p.value.Month(), 1, 0, 0, 0, 0, time.Local)
+	today := dayKey(time.Now())
+	s.WriteString(dim.Render("Mo Tu We Th Fr Sa Su") + "\n")
+	s.WriteString(strings.Repeat("   ", (int(first.Weekday())+6)%7))
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		cell := fmt.Sprintf("%2d", day.Day())
+		style := lipgloss.NewStyle()
+		if dayKey(day) == today {
+			style = style.Bold(true).Underline(true)
+		}
+		if day.Day() == p.value.Day() {
+			style = style.Reverse(true).Foreground(accent)
+		}
+		s.WriteString(style.Render(cell))
+		if day.Weekday() == time.Sunday {
+			s.WriteString("\n")
+		} else {
+			s.WriteString(" ")
+		}
+	}
+
+	s.WriteString("\n\n" + dim.Render("h/l: Field  j/k: Change  pgup/pgdown: Month  t: Today  /: Type instead  Enter: Set  esc: Cancel"))
+	return s.String() + "\n\n"
+}
+
+// openDueDateInput starts editing the selected task's due date with the
+// configured input
+func (m *model) openDueDateInput(picker bool) {
+	task := m.selectedTask()
+	if task == nil {
+		return
+	}
+	if picker {
+		m.datePicker = newDatePicker(task.DueDate, time.Now())
+		m.datePicking = true
+		return
+	}
+
+	m.inputActive = true
+	m.inputAction = "due_date"
+	m.input.Placeholder = "Format: YYYY-MM-DD HH:mm, YYYY-MM-DD, MM/DD/YYYY, or DD-MM-YYYY"
+
+	// Show current due date if it exists
+	if !task.DueDate.IsZero() {
+		m.input.SetValue(task.DueDate.Format("2006-01-02 15:04"))
+	} else {
+		m.input.SetValue("")
+	}
+	m.input.Focus()
+}
+
+// updateDatePicker passes keys to the open date picker, setting the due date
+// when it is confirmed. / falls back to typing the date.
+func (m *model) updateDatePicker(msg tea.KeyMsg) {
+	if msg.String() == "/" {
+		m.datePicking = false
+		m.openDueDateInput(false)
+		m.input.SetValue(m.datePicker.value.Format("2006-01-02 15:04"))
+		return
+	}
+
+	picker, done, ok := m.datePicker.Update(msg)
+	m.datePicker = picker
+	if !done {
+		return
+	}
+	m.datePicking = false
+	if !ok {
+		return
+	}
+
+	task := m.selectedTask()
+	if task == nil {
+		return
+	}
+	task.DueDate = picker.value
+	task.Updated = time.Now()
+	m.saveTasks()
+	m.syncToGoogle(*task)
+	m.statusMsg = "Due " + formatDate(task.DueDate)
+}
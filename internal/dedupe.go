@@ -0,0 +1,154 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Duplicate is a task with the same title and parent as an older task in
+// the same list
+type Duplicate struct {
+	ListID string // Empty for tasks outside any list
+	Keep   Task
+	Remove Task
+}
+
+// FindDuplicates returns every task that repeats an older sibling. Of each
+// set of identical tasks the oldest by createdTime is kept, and of those
+// equally old the first in list order.
+func FindDuplicates(tasks []Task) []Duplicate {
+	var dups []Duplicate
+	var walk func(listID string, siblings []Task)
+	walk = func(listID string, siblings []Task) {
+		groups := make(map[string][]Task)
+		var titles []string
+		for _, task := range siblings {
+			if task.Deleted {
+				continue
+			}
+			if task.Kind == "tasks#taskList" {
+				walk(task.Id, task.Tasks)
+				continue
+			}
+			if _, seen := groups[task.Title]; !seen {
+				titles = append(titles, task.Title)
+			}
+			groups[task.Title] = append(groups[task.Title], task)
+			walk(listID, task.Tasks)
+		}
+
+		for _, title := range titles {
+			group := groups[title]
+			sort.SliceStable(group, func(i, j int) bool {
+				return createdTime(group[i]).Before(createdTime(group[j]))
+			})
+			for _, task := range group[1:] {
+				dups = append(dups, Duplicate{ListID: listID, Keep: group[0], Remove: task})
+			}
+		}
+	}
+	walk("", tasks)
+	return dups
+}
+
+// createdTime returns when a task was created, whichever field has it.
+// Google doesn't report creation times, so for its tasks the last update
+// stands in, as a copy made later was usually also updated later.
+func createdTime(task Task) time.Time {
+	if !task.Created.IsZero() {
+		return task.Created
+	}
+	if !task.CreatedAt.IsZero() {
+		return task.CreatedAt
+	}
+	return task.Updated
+}
+
+// RemoveDuplicates merges each duplicate into the task it repeats: its
+// subtasks move to the kept task and the duplicate is deleted, from Google
+// Tasks as well when client is set. It returns the updated tree and how
+// many duplicates were removed, which is less than len(dups) on error.
+func RemoveDuplicates(tasks []Task, dups []Duplicate, client *GoogleTasksClient) ([]Task, int, error) {
+	for i, dup := range dups {
+		removed := findTask(tasks, dup.Remove.Id)
+		if removed == nil || findTask(tasks, dup.Keep.Id) == nil {
+			continue
+		}
+		// Earlier merges may have changed its subtasks
+		dup.Remove = *removed
+		if client != nil {
+			if err := removeDuplicateIn(client, dup); err != nil {
+				return tasks, i, err
+			}
+		}
+		tasks = mergeDuplicate(tasks, dup)
+	}
+	return tasks, len(dups), nil
+}
+
+// mergeDuplicate moves the subtasks of a duplicate to the task it repeats
+// and removes it from the tree
+func mergeDuplicate(tasks []Task, dup Duplicate) []Task {
+	children := findTask(tasks, dup.Remove.Id).Tasks
+	tasks = removeTaskByID(tasks, dup.Remove.Id)
+	kept := findTask(tasks, dup.Keep.Id)
+	for _, child := range children {
+		child.Parent = kept.Id
+		kept.Tasks = append(kept.Tasks, child)
+	}
+	return tasks
+}
+
+// removeDuplicateIn merges a duplicate in Google Tasks, leaving a tombstone
+// so a sync from before the delete can't bring it back
+func removeDuplicateIn(client *GoogleTasksClient, dup Duplicate) error {
+	// Move subtasks out first, deleting a task takes its subtasks with it
+	for _, child := range dup.Remove.Tasks {
+		if err := client.moveTaskIn(dup.ListID, child.Id, dup.Keep.Id); err != nil {
+			return fmt.Errorf("failed to move subtasks of %q: %v", dup.Remove.Title, err)
+		}
+	}
+	if err := recordTombstone(dup.Remove.Id, time.Now()); err != nil {
+		logError("Error recording tombstone: %v", err)
+	}
+	if err := client.deleteTaskIn(dup.ListID, dup.Remove.Id); err != nil {
+		return fmt.Errorf("failed to delete duplicate %q: %v", dup.Remove.Title, err)
+	}
+	return nil
+}
+
+// removeDuplicates merges duplicate tasks across the whole tree after the
+// user confirmed it. The tree changes at once; Google follows in the
+// background.
+func (m *model) removeDuplicates() {
+	tasks := m.allTasks()
+	var merged []Duplicate
+	for _, dup := range FindDuplicates(tasks) {
+		removed := findTask(tasks, dup.Remove.Id)
+		if removed == nil || findTask(tasks, dup.Keep.Id) == nil {
+			continue
+		}
+		// Google gets the subtasks as they are after the earlier merges
+		dup.Remove = cloneTasks([]Task{*removed})[0]
+		merged = append(merged, dup)
+		tasks = mergeDuplicate(tasks, dup)
+	}
+	m.tasks, m.completedTasks = splitTasks(tasks)
+	if count := m.visibleCount(); m.cursor >= count {
+		m.cursor = max(count-1, 0)
+	}
+	m.saveTasks()
+	m.statusMsg = fmt.Sprintf("Removed %d duplicate(s)", len(merged))
+
+	if client := m.googleTasks; client != nil && len(merged) > 0 {
+		goSync(func() {
+			for _, dup := range merged {
+				if err := removeDuplicateIn(client, dup); err != nil {
+					reportSyncError(err)
+					return
+				}
+			}
+		})
+	}
+}
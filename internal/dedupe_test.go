@@ -0,0 +1,35 @@
+package internal
+
+import (
+	"testing"
+
+	v1 "google.golang.org/api/tasks/v1"
+)
+
+func TestFindDuplicatesGoogleTasks(t *testing.T) {
+	// Google tasks carry no creation time, only when they were last updated
+	var siblings []Task
+	for _, googleTask := range []*v1.Task{
+		{Id: "newer", Title: "Pay rent", Updated: "2024-05-02T10:00:00.000Z", Position: "1"},
+		{Id: "older", Title: "Pay rent", Updated: "2024-05-01T10:00:00.000Z", Position: "2"},
+		{Id: "tied", Title: "Pay rent", Updated: "2024-05-02T10:00:00.000Z", Position: "3"},
+	} {
+		task := taskFromGoogle(googleTask)
+		if !task.Created.IsZero() || !task.CreatedAt.IsZero() {
+			t.Fatalf("%s has a creation time, so this doesn't test the fallback", task.Id)
+		}
+		siblings = append(siblings, task)
+	}
+	tasks := []Task{{Id: "list", Title: "List", Kind: "tasks#taskList", Tasks: siblings}}
+
+	dups := FindDuplicates(tasks)
+	if len(dups) != 2 {
+		t.Fatalf("found %d duplicates, want 2", len(dups))
+	}
+	for i, want := range []string{"newer", "tied"} {
+		if dups[i].Keep.Id != "older" || dups[i].Remove.Id != want || dups[i].ListID != "list" {
+			t.Errorf("duplicate %d keeps %s and removes %s in %q, want older, %s and list",
+				i, dups[i].Keep.Id, dups[i].Remove.Id, dups[i].ListID, want)
+		}
+	}
+}
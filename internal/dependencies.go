@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"fmt"
+	"time"
+)
+
+// findTask searches a task tree for the task with the given ID
+func findTask(tasks []Task, id string) *Task {
+	for i := range tasks {
+		if tasks[i].Id == id {
+			return &tasks[i]
+		}
+		if found := findTask(tasks[i].Tasks, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// isBlocked reports whether any of the task's blockers is still unfinished.
+// Blockers that no longer exist don't hold the task up.
+func isBlocked(task Task, lookup func(string) *Task) bool {
+	for _, id := range task.BlockedBy {
+		if blocker := lookup(id); blocker != nil && !blocker.Completed {
+			return true
+		}
+	}
+	return false
+}
+
+// wouldCreateCycle reports whether making taskID blocked by blockerID
+// would close a loop in the dependency graph
+func wouldCreateCycle(lookup func(string) *Task, taskID, blockerID string) bool {
+	seen := make(map[string]bool)
+	stack := []string{blockerID}
+
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if id == taskID {
+			return true
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if task := lookup(id); task != nil {
+			stack = append(stack, task.BlockedBy...)
+		}
+	}
+	return false
+}
+
+// addBlocker links a task to a blocker, rejecting unknown IDs and cycles
+func addBlocker(task *Task, blockerID string, lookup func(string) *Task) error {
+	switch {
+	case blockerID == task.Id:
+		return fmt.Errorf("a task can't block itself")
+	case lookup(blockerID) == nil:
+		return fmt.Errorf("no task with ID %s", blockerID)
+	case wouldCreateCycle(lookup, task.Id, blockerID):
+		return fmt.Errorf("that dependency would create a cycle")
+	}
+
+	for _, id := range task.BlockedBy {
+		if id == blockerID {
+			return fmt.Errorf("already blocked by that task")
+		}
+	}
+	task.BlockedBy = append(task.BlockedBy, blockerID)
+	return nil
+}
+
+// linkMarker flags the task marked as the pending blocker
+const linkMarker = "⛓ "
+
+// linkTasks drives the two-step dependency link on B: the first press marks
+// the selected task as a blocker, the next makes the task selected then
+// blocked by it. esc drops the mark.
+func (m *model) linkTasks() {
+	task := m.selectedTask()
+	if task == nil {
+		return
+	}
+	if m.linkFrom == "" {
+		m.linkFrom = task.Id
+		return
+	}
+
+	if err := addBlocker(task, m.linkFrom, m.findTask); err != nil {
+		m.statusMsg = "Can't add dependency: " + err.Error()
+		return
+	}
+	blocker := m.findTask(m.linkFrom)
+	m.linkFrom = ""
+	task.Updated = time.Now()
+	m.syncToGoogle(*task)
+	m.saveTasks()
+	m.statusMsg = "Now blocked by " + blocker.Title
+}
+
+// linkHint names the task marked as the pending blocker, if any
+func (m *model) linkHint() string {
+	if m.linkFrom == "" {
+		return ""
+	}
+	blocker := m.findTask(m.linkFrom)
+	if blocker == nil {
+		return ""
+	}
+	return linkMarker + "Blocker: " + blocker.Title + " (B on another task to link, esc to cancel)"
+}
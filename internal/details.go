@@ -0,0 +1,275 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+)
+
+// Layouts for the details panel, set with DetailsPanelLayout
+const (
+	LayoutSide    = "side"    // Beside the list, hidden when the terminal is too narrow
+	LayoutAuto    = "auto"    // Beside the list, or below it when the terminal is too narrow
+	LayoutStacked = "stacked" // Always below the list
+	LayoutOff     = "off"     // Never shown
+)
+
+const (
+	minMainWidth    = 30 // Minimum width for main panel
+	minDetailsWidth = 30 // Minimum width for details panel
+	panelPadding    = 3  // Space between panels
+	minStackedLines = 6  // Minimum height of the details panel below the list
+)
+
+// detailsLayout returns where the details panel goes at the current
+// terminal size: LayoutSide, LayoutStacked or LayoutOff
+func (m *model) detailsLayout() string {
+	layout := LayoutSide
+	if config := GetGlobalConfig(); config != nil && config.DetailsPanelLayout != "" {
+		layout = strings.ToLower(config.DetailsPanelLayout)
+	}
+
+	narrow := m.width < minMainWidth+minDetailsWidth+panelPadding
+	switch layout {
+	case LayoutOff, LayoutStacked:
+		return layout
+	case LayoutAuto:
+		if narrow {
+			return LayoutStacked
+		}
+	default:
+		if narrow {
+			return LayoutOff
+		}
+	}
+	return LayoutSide
+}
+
+// detailsShare returns the percentage of the terminal given to the details
+// panel, read from DetailsPanelWidth as e.g. "40%" or "40"
+func detailsShare() int {
+	share := 33
+	if config := GetGlobalConfig(); config != nil {
+		if n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(config.DetailsPanelWidth), "%")); err == nil {
+			share = n
+		}
+	}
+	return min(max(share, 10), 90)
+}
+
+// panelWidths splits the terminal between the task list and the details
+// panel. The details panel is 0 wide when it is hidden, and as wide as the
+// list when stacked below it.
+func (m *model) panelWidths() (int, int) {
+	switch m.detailsLayout() {
+	case LayoutOff:
+		return m.width, 0
+	case LayoutStacked:
+		return m.width, m.width
+	}
+
+	mainPanelWidth := m.width * (100 - detailsShare()) / 100
+	detailsPanelWidth := m.width - mainPanelWidth - panelPadding
+	if detailsPanelWidth < minDetailsWidth {
+		// Ensure details panel has minimum width if shown
+		detailsPanelWidth = minDetailsWidth
+		mainPanelWidth = m.width - minDetailsWidth - panelPadding
+	}
+	if mainPanelWidth < minMainWidth {
+		mainPanelWidth = minMainWidth
+		detailsPanelWidth = m.width - minMainWidth - panelPadding
+	}
+
+	return mainPanelWidth, detailsPanelWidth
+}
+
+// panelHeights returns the heights of the task list and the details panel,
+// which only share the terminal height when stacked
+func (m *model) panelHeights() (int, int) {
+	if m.detailsLayout() != LayoutStacked {
+		return m.height, m.height
+	}
+	detailsHeight := max(m.height*detailsShare()/100, minStackedLines)
+	return max(m.height-detailsHeight, 1), detailsHeight
+}
+
+// detailsContent renders the details of the selected task wrapped to width
+func (m *model) detailsContent(width int) string {
+	var detailsPanel strings.Builder
+	detailsPanel.WriteString("Task Details\n\n")
+
+	// Get the currently selected task
+	selectedTask := m.selectedTask()
+
+	if selectedTask != nil {
+		// Function to wrap text to fit panel width
+		wrapText := func(text string) string {
+			return strings.Join(wrap(text, wrapWidth(width)), "\n")
+		}
+
+		// Show task details with text wrapping
+		detailsPanel.WriteString("Title: " + wrapText(selectedTask.Title) + "\n\n")
+
+		detailsPanel.WriteString("Description: \n")
+		if selectedTask.Description == "" {
+			detailsPanel.WriteString("(Press 'i' to add description)\n")
+		} else {
+			detailsPanel.WriteString(wrapText(selectedTask.Description) + "\n")
+		}
+		detailsPanel.WriteString("\n")
+
+		detailsPanel.WriteString("Notes: \n")
+		if selectedTask.Notes == "" {
+			detailsPanel.WriteString("(Press 'o' to add notes)\n")
+		} else {
+			detailsPanel.WriteString(wrapText(selectedTask.Notes) + "\n")
+			if done, total := checklistProgress(selectedTask.Notes); total > 0 {
+				detailsPanel.WriteString(fmt.Sprintf("Checklist: %d/%d (x to tick)\n", done, total))
+			}
+		}
+		detailsPanel.WriteString("\n")
+
+		if selectedTask.InProgress {
+			detailsPanel.WriteString("Status: In progress\n")
+		}
+		detailsPanel.WriteString("Created: " + formatDate(selectedTask.CreatedAt) + "\n")
+		if advancedMode() {
+			detailsPanel.WriteString("Position: " + selectedTask.Position + " (</>: Move to top/bottom)\n")
+		}
+
+		detailsPanel.WriteString("Due Date: ")
+		if selectedTask.DueDate.IsZero() {
+			detailsPanel.WriteString("(Press 't' to set due date)\n")
+		} else {
+			detailsPanel.WriteString(formatDate(selectedTask.DueDate) + "\n")
+		}
+
+		if spent := timeSpent(*selectedTask, time.Now()); spent > 0 || !selectedTask.TimerStarted.IsZero() {
+			detailsPanel.WriteString("Time spent: " + formatTimeSpent(spent))
+			if !selectedTask.TimerStarted.IsZero() {
+				detailsPanel.WriteString(" (running)")
+			}
+			detailsPanel.WriteString("\n")
+		}
+		if selectedTask.Estimate > 0 {
+			detailsPanel.WriteString("Estimate: " + formatEstimate(selectedTask.Estimate) + "\n")
+		}
+		if rollup := renderEffort(effort([]Task{*selectedTask})); rollup != "" && len(selectedTask.Tasks) > 0 {
+			detailsPanel.WriteString(rollup + "\n")
+		}
+		if selectedTask.Priority != "" {
+			detailsPanel.WriteString("Priority: " + selectedTask.Priority + "\n")
+		}
+		if selectedTask.Color != "" {
+			detailsPanel.WriteString("Flag: " + colorFlag(selectedTask.Color) + selectedTask.Color + "\n")
+		}
+		if len(selectedTask.Tags) > 0 {
+			detailsPanel.WriteString("Tags: #" + strings.Join(selectedTask.Tags, " #") + "\n")
+		}
+		if len(selectedTask.BlockedBy) > 0 {
+			detailsPanel.WriteString("Blocked by:\n")
+			for _, id := range selectedTask.BlockedBy {
+				if blocker := m.findTask(id); blocker != nil {
+					status := " "
+					if blocker.Completed {
+						status = "✓"
+					}
+					detailsPanel.WriteString(fmt.Sprintf("  %s %s\n", status, wrapText(blocker.Title)))
+				}
+			}
+		}
+		if len(selectedTask.Links) > 0 {
+			detailsPanel.WriteString(renderLinks(selectedTask.Links, wrapText))
+		}
+		detailsPanel.WriteString("ID: " + selectedTask.Id + "\n")
+
+		// Add keyboard shortcuts at the bottom if there's space
+		if m.height > 20 {
+			detailsPanel.WriteString("\n\nKeyboard Shortcuts:\n")
+			detailsPanel.WriteString("n: New task    d: Delete\n")
+			detailsPanel.WriteString("N: New task with notes and due date\n")
+			detailsPanel.WriteString("r: Rename      i: Edit description\n")
+			detailsPanel.WriteString("o: Edit notes  t: Set due date\n")
+			detailsPanel.WriteString("b: Set blocker v: Tree view\n")
+			detailsPanel.WriteString("B: Mark blocker, B again to link\n")
+			detailsPanel.WriteString("+/-: Expand/collapse all in tree view\n")
+			detailsPanel.WriteString("f: Focus mode  T: Set due time\n")
+			detailsPanel.WriteString("w: Snooze       m: Move to list\n")
+			detailsPanel.WriteString("F: Cycle color flag  W: Review\n")
+			detailsPanel.WriteString("H: Hide/show completed  S: Sort\n")
+			detailsPanel.WriteString("c: Collapse/expand completed\n")
+			detailsPanel.WriteString("P: Not started/in progress/done\n")
+			detailsPanel.WriteString("x: Tick a checklist item in the notes\n")
+			detailsPanel.WriteString(",: Edit the config in $EDITOR\n")
+			detailsPanel.WriteString("Y: Copy to clipboard  s: Start/stop timer\n")
+			detailsPanel.WriteString("p: Pin list/task  J/K: Move list/task\n")
+			detailsPanel.WriteString("g: Jump to task  R: Sync now\n")
+			detailsPanel.WriteString("G: Switch local/Google mode  I: API stats\n")
+			detailsPanel.WriteString("E: Error log   e: Set estimate\n")
+			detailsPanel.WriteString("U: New task from template  C: Calendar\n")
+			detailsPanel.WriteString("X: Clear completed from Google\n")
+			detailsPanel.WriteString("A: Next action of each list  u: Trash\n")
+			detailsPanel.WriteString("L: Add link  a: Attach file  O: Open\n")
+			detailsPanel.WriteString("Tab: Scroll details  ':': Commands\n")
+			detailsPanel.WriteString("ctrl+p: Switch list  !: Sync conflicts\n")
+			detailsPanel.WriteString("]/[: Next/previous task due soon\n")
+			detailsPanel.WriteString("q: Quit once synced  Q: Quit now\n")
+			detailsPanel.WriteString("Space: Toggle completion\n")
+			detailsPanel.WriteString("←/h: Back      →/l: Enter sublist\n")
+			detailsPanel.WriteString("?: Toggle legend  D: Remove duplicates\n")
+			detailsPanel.WriteString("\nLegend:\n" + renderLegendBlock())
+		}
+	} else {
+		detailsPanel.WriteString("No task selected")
+	}
+	return detailsPanel.String()
+}
+
+// detailsViewport returns the details panel as a viewport sized for a panel
+// of the given outer size, recomputed from the current terminal size on
+// every call. The scroll offset only applies to the task it was scrolled on,
+// so moving the selection starts the new task at the top.
+func (m *model) detailsViewport(panelWidth, panelHeight int) viewport.Model {
+	vp := m.details
+	vp.Width = max(panelWidth-4, 1)   // Border and padding on both sides
+	vp.Height = max(panelHeight-4, 1) // Border and padding above and below
+	vp.SetContent(m.detailsContent(vp.Width))
+
+	if task := m.selectedTask(); task == nil || task.Id != m.detailsTaskID {
+		vp.GotoTop()
+	} else {
+		// A taller terminal may leave the old offset past the end
+		vp.SetYOffset(vp.YOffset)
+	}
+	return vp
+}
+
+// scrollDetails handles keys while the details panel has focus
+func (m *model) scrollDetails(key string) {
+	_, width := m.panelWidths()
+	_, height := m.panelHeights()
+	vp := m.detailsViewport(width, height)
+
+	switch key {
+	case "down", "j":
+		vp.LineDown(1)
+	case "up", "k":
+		vp.LineUp(1)
+	case "pgdown", " ":
+		vp.ViewDown()
+	case "pgup":
+		vp.ViewUp()
+	case "home", "g":
+		vp.GotoTop()
+	case "end", "G":
+		vp.GotoBottom()
+	}
+
+	m.details = vp
+	if task := m.selectedTask(); task != nil {
+		m.detailsTaskID = task.Id
+	}
+}
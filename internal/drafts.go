@@ -0,0 +1,76 @@
+package internal
+
+import "github.com/charmbracelet/lipgloss"
+
+// draftField returns the field of task an input action edits, and whether
+// the action keeps drafts at all
+func draftField(task *Task, action string) (string, bool) {
+	switch action {
+	case "rename":
+		return task.Title, true
+	case "description":
+		return task.Description, true
+	case "notes":
+		return task.Notes, true
+	}
+	return "", false
+}
+
+// draftKey identifies the field being edited, or is empty when the current
+// input doesn't keep drafts
+func (m *model) draftKey() string {
+	task := m.selectedTask()
+	if task == nil {
+		return ""
+	}
+	if _, ok := draftField(task, m.inputAction); !ok {
+		return ""
+	}
+	return m.inputAction + ":" + task.Id
+}
+
+// escSavesInput reports whether esc saves text edits instead of discarding them
+func escSavesInput() bool {
+	config := GetGlobalConfig()
+	return config != nil && config.EscSavesInput
+}
+
+// cancelInput closes the input on esc. Unsaved edits of the title,
+// description and notes are kept as a draft that ctrl+r brings back the next
+// time the same field is edited.
+func (m *model) cancelInput() {
+	if key := m.draftKey(); key != "" {
+		task := m.selectedTask()
+		if saved, _ := draftField(task, m.inputAction); m.input.Value() != saved {
+			if m.drafts == nil {
+				m.drafts = make(map[string]string)
+			}
+			m.drafts[key] = m.input.Value()
+			m.statusMsg = "Edit kept as a draft, ctrl+r restores it"
+		}
+	}
+	m.inputActive = false
+	m.input.Blur()
+}
+
+// restoreDraft puts the stashed draft of the field being edited back
+func (m *model) restoreDraft() {
+	if draft, ok := m.drafts[m.draftKey()]; ok {
+		m.input.SetValue(draft)
+		m.input.CursorEnd()
+	}
+}
+
+// clearDraft forgets the draft of the field being saved
+func (m *model) clearDraft() {
+	delete(m.drafts, m.draftKey())
+}
+
+// draftHint tells the user a draft of the field being edited is waiting
+func (m *model) draftHint() string {
+	draft, ok := m.drafts[m.draftKey()]
+	if !ok || draft == m.input.Value() {
+		return ""
+	}
+	return "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("Unsaved draft from earlier, ctrl+r restores it")
+}
@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Due dates entered without a time land at the end of the day instead of
+// midnight, so a task isn't overdue the moment its due date begins
+const (
+	defaultDueHour   = 23
+	defaultDueMinute = 59
+)
+
+var (
+	dueDateTimeFormats = []string{"2006-01-02 15:04", "2006-01-02T15:04"}
+	dueDateFormats     = []string{"2006-01-02", "01/02/2006", "02-01-2006"}
+	dueTimeFormats     = []string{"15:04", "3:04pm", "3pm", "3:04 pm", "3 pm"}
+)
+
+// parseDueDate reads a full date and time, or just a date. A date on its own
+// keeps the time of day of the current due date, if there is one.
+func parseDueDate(input string, current time.Time) (time.Time, error) {
+	input = strings.TrimSpace(input)
+
+	for _, format := range dueDateTimeFormats {
+		if due, err := time.ParseInLocation(format, input, time.Local); err == nil {
+			return due, nil
+		}
+	}
+
+	for _, format := range dueDateFormats {
+		date, err := time.ParseInLocation(format, input, time.Local)
+		if err != nil {
+			continue
+		}
+		hour, minute := defaultDueHour, defaultDueMinute
+		if !current.IsZero() {
+			hour, minute = current.Hour(), current.Minute()
+		}
+		return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, time.Local), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD HH:mm, YYYY-MM-DD, MM/DD/YYYY or DD-MM-YYYY", input)
+}
+
+// parseDueTime replaces the time of day of current, keeping its date. An
+// empty input resets the time to the default.
+func parseDueTime(input string, current time.Time) (time.Time, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	current = current.Local()
+
+	hour, minute := defaultDueHour, defaultDueMinute
+	if input != "" {
+		parsed, err := parseTimeOfDay(input)
+		if err != nil {
+			return time.Time{}, err
+		}
+		hour, minute = parsed.Hour(), parsed.Minute()
+	}
+
+	return time.Date(current.Year(), current.Month(), current.Day(), hour, minute, 0, 0, time.Local), nil
+}
+
+func parseTimeOfDay(input string) (time.Time, error) {
+	for _, format := range dueTimeFormats {
+		if t, err := time.Parse(format, input); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, use HH:mm or 3pm", input)
+}
+
+// formatDate renders a date for display using the configured DateFormat
+func formatDate(t time.Time) string {
+	format := "2006-01-02 15:04"
+	if config := GetGlobalConfig(); config != nil && config.DateFormat != "" {
+		format = config.DateFormat
+	}
+	return t.Format(format)
+}
+
+// isOverdue reports whether an unfinished task is past its due date. Due
+// dates on days off count from the next working day of wt.
+func isOverdue(task Task, now time.Time, wt workingTime) bool {
+	return !task.Completed && !task.DueDate.IsZero() && wt.effectiveDue(task.DueDate).Before(now)
+}
@@ -0,0 +1,44 @@
+package internal
+
+import "time"
+
+// dueSoonWindow is how far ahead ] and [ look for tasks coming due
+const dueSoonWindow = 24 * time.Hour
+
+// dueSoon reports whether an unfinished task is overdue or due within
+// dueSoonWindow
+func dueSoon(task Task, now time.Time, wt workingTime) bool {
+	if task.Completed || task.DueDate.IsZero() {
+		return false
+	}
+	return isOverdue(task, now, wt) || task.DueDate.Before(now.Add(dueSoonWindow))
+}
+
+// visibleTasks returns the tasks the cursor moves across, in display order
+func (m *model) visibleTasks() []Task {
+	if m.treeView {
+		rows := m.treeRows()
+		tasks := make([]Task, len(rows))
+		for i, row := range rows {
+			tasks[i] = row.task
+		}
+		return tasks
+	}
+	active, completed := m.getCurrentTasks()
+	return append(append([]Task(nil), active...), completed...)
+}
+
+// jumpToDue moves the cursor to the next visible task that is overdue or due
+// soon, or the previous one when step is -1, wrapping around the list
+func (m *model) jumpToDue(step int) {
+	tasks := m.visibleTasks()
+	now, wt := time.Now(), currentWorkingTime()
+	for i := 1; i <= len(tasks); i++ {
+		j := ((m.cursor+i*step)%len(tasks) + len(tasks)) % len(tasks)
+		if dueSoon(tasks[j], now, wt) {
+			m.cursor = j
+			return
+		}
+	}
+	m.statusMsg = "No overdue or upcoming due tasks"
+}
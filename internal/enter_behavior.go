@@ -0,0 +1,56 @@
+package internal
+
+import "strings"
+
+// What enter does on a task without subtasks, set with EnterOnLeaf. l and
+// right always descend.
+const (
+	EnterDescend = "descend" // Open its empty sublist to add subtasks
+	EnterDetails = "details" // Focus the details panel
+	EnterEdit    = "edit"    // Edit its notes
+	EnterNone    = "none"    // Do nothing
+)
+
+// enterOnLeaf returns the configured enter behavior for leaf tasks
+func enterOnLeaf() string {
+	if config := GetGlobalConfig(); config != nil {
+		switch mode := strings.ToLower(config.EnterOnLeaf); mode {
+		case EnterDetails, EnterEdit, EnterNone:
+			return mode
+		}
+	}
+	return EnterDescend
+}
+
+// enterLeaf applies the EnterOnLeaf behavior to the selected task, reporting
+// false when enter should descend as usual: for lists, tasks with subtasks,
+// or when configured to
+func (m *model) enterLeaf() bool {
+	task := m.selectedTask()
+	if task == nil || task.Kind == "tasks#taskList" {
+		return false
+	}
+	if _, total := subtaskProgress(*task); total > 0 {
+		return false
+	}
+
+	switch enterOnLeaf() {
+	case EnterDetails:
+		if _, width := m.panelWidths(); width > 0 {
+			m.detailsFocus = true
+		} else {
+			m.statusMsg = "The details panel is hidden"
+		}
+	case EnterEdit:
+		m.inputActive = true
+		m.inputAction = "notes"
+		m.input.Placeholder = ""
+		m.input.SetValue(task.Notes)
+		m.input.Focus()
+		m.input.CursorEnd()
+	case EnterNone:
+	default:
+		return false
+	}
+	return true
+}
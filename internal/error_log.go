@@ -0,0 +1,114 @@
+package internal
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// errorLogSize is how many errors the in-memory log keeps
+const errorLogSize = 200
+
+// logEntry is one error in the log
+type logEntry struct {
+	Time time.Time
+	Msg  string
+}
+
+// errorLog is a ring buffer of recent errors, shown with E. Background sync
+// writes to it too, so it is guarded by a mutex. logError adds to it.
+var errorLog struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+// addErrorLogEntry adds an error to the log, dropping the oldest when full
+func addErrorLogEntry(entry logEntry) {
+	errorLog.mu.Lock()
+	defer errorLog.mu.Unlock()
+	errorLog.entries = append(errorLog.entries, entry)
+	if len(errorLog.entries) > errorLogSize {
+		errorLog.entries = errorLog.entries[len(errorLog.entries)-errorLogSize:]
+	}
+}
+
+// errorLogEntries returns a copy of the logged errors, oldest first
+func errorLogEntries() []logEntry {
+	errorLog.mu.Lock()
+	defer errorLog.mu.Unlock()
+	return append([]logEntry(nil), errorLog.entries...)
+}
+
+// clearErrorLog empties the in-memory log; godo.log is left alone
+func clearErrorLog() {
+	errorLog.mu.Lock()
+	defer errorLog.mu.Unlock()
+	errorLog.entries = nil
+}
+
+// openErrorLog shows the error log scrolled to the newest entry
+func (m *model) openErrorLog() {
+	m.showErrorLog = true
+	m.errorLogView = m.errorLogViewport()
+	m.errorLogView.GotoBottom()
+}
+
+// errorLogViewport returns the log viewport sized to the terminal
+func (m *model) errorLogViewport() viewport.Model {
+	vp := m.errorLogView
+	vp.Width = max(m.width-4, 1)
+	vp.Height = max(m.height-4, 1) // Border, title and help line
+
+	entries := errorLogEntries()
+	if len(entries) == 0 {
+		vp.SetContent("No errors logged")
+		return vp
+	}
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		lines[i] = entry.Time.Format("15:04:05") + " " + entry.Msg
+	}
+	vp.SetContent(lipgloss.NewStyle().Width(vp.Width).Render(strings.Join(lines, "\n")))
+	vp.SetYOffset(vp.YOffset)
+	return vp
+}
+
+// errorLogKey handles keys while the error log is open
+func (m *model) errorLogKey(key string) {
+	vp := m.errorLogViewport()
+	switch key {
+	case "E", "esc":
+		m.showErrorLog = false
+	case "c":
+		clearErrorLog()
+		vp = m.errorLogViewport()
+		vp.GotoTop()
+	case "down", "j":
+		vp.LineDown(1)
+	case "up", "k":
+		vp.LineUp(1)
+	case "pgdown", " ":
+		vp.ViewDown()
+	case "pgup":
+		vp.ViewUp()
+	case "home", "g":
+		vp.GotoTop()
+	case "end", "G":
+		vp.GotoBottom()
+	}
+	m.errorLogView = vp
+}
+
+// renderErrorLog draws the error log over the whole screen
+func (m *model) renderErrorLog() string {
+	vp := m.errorLogViewport()
+	help := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).
+		Render("j/k: Scroll  c: Clear  E/esc: Close")
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Padding(0, 1).
+		Render("Error Log\n" + vp.View() + "\n" + help)
+}
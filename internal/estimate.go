@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// workDay is how long "1d" is in an estimate: a working day, not 24 hours
+const workDay = 8 * time.Hour
+
+// parseEstimate reads an estimate like "2h", "30m", "1d" or "1d4h30m". An
+// empty string clears the estimate.
+func parseEstimate(input string) (time.Duration, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	if input == "" {
+		return 0, nil
+	}
+
+	invalid := fmt.Errorf("invalid estimate %q, use e.g. 2h, 30m or 1d", input)
+
+	var days time.Duration
+	if i := strings.Index(input, "d"); i >= 0 {
+		n, err := strconv.ParseFloat(input[:i], 64)
+		if err != nil || n < 0 {
+			return 0, invalid
+		}
+		days = time.Duration(n * float64(workDay))
+		input = input[i+1:]
+	}
+
+	var rest time.Duration
+	if input != "" {
+		var err error
+		if rest, err = time.ParseDuration(input); err != nil || rest < 0 {
+			return 0, invalid
+		}
+	}
+	return days + rest, nil
+}
+
+// formatEstimate renders an estimate in hours and minutes, e.g. "1h30m"
+func formatEstimate(d time.Duration) string {
+	d = d.Round(time.Minute)
+	hours, minutes := int(d/time.Hour), int(d%time.Hour/time.Minute)
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dh%dm", hours, minutes)
+}
+
+// effort sums the estimates of tasks and all their subtasks. Everything
+// counts towards total; remaining leaves out completed tasks, along with
+// the subtasks of completed tasks.
+func effort(tasks []Task) (remaining, total time.Duration) {
+	for _, task := range tasks {
+		if task.Deleted {
+			continue
+		}
+		subRemaining, subTotal := effort(task.Tasks)
+		total += task.Estimate + subTotal
+		if !task.Completed {
+			remaining += task.Estimate + subRemaining
+		}
+	}
+	return remaining, total
+}
+
+// renderEffort describes remaining and total effort, or "" with no estimates
+func renderEffort(remaining, total time.Duration) string {
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Effort: %s remaining of %s total", formatEstimate(remaining), formatEstimate(total))
+}
+
+// levelEffort sums the estimates of every task at the current level,
+// including completed ones the filter is hiding
+func (m *model) levelEffort() string {
+	active, completed := m.levelTasks()
+	return renderEffort(effort(append(active, completed...)))
+}
+
+// setEstimate parses input as the selected task's estimate and saves it
+func (m *model) setEstimate(input string) {
+	task := m.selectedTask()
+	if task == nil {
+		return
+	}
+	estimate, err := parseEstimate(input)
+	if err != nil {
+		m.statusMsg = err.Error()
+		return
+	}
+
+	task.Estimate = estimate
+	task.Updated = time.Now()
+	m.syncToGoogle(*task)
+	m.saveTasks()
+}
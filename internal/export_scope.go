@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SelectSubtree returns the part of the tree rooted at the task or list with
+// the given ID, or failing that the list with the given title, ignoring
+// case. A task is returned inside a copy of its list holding only that task,
+// so exports still know which list it came from.
+func SelectSubtree(tasks []Task, selector string) ([]Task, error) {
+	selector = strings.TrimSpace(selector)
+	if path, found := findTaskByID(tasks, selector); found {
+		return scopedTree(path), nil
+	}
+	for _, list := range tasks {
+		if !list.Deleted && list.Kind == "tasks#taskList" && strings.EqualFold(list.Title, selector) {
+			return []Task{list}, nil
+		}
+	}
+	return nil, fmt.Errorf("no list or task matches %q", selector)
+}
+
+// scopedTree keeps the last task of path with its subtasks, under its list
+func scopedTree(path []Task) []Task {
+	root := path[len(path)-1]
+	if len(path) == 1 {
+		return []Task{root}
+	}
+	list := path[0]
+	list.Tasks = []Task{root}
+	return []Task{list}
+}
+
+// exportView writes the list or task being viewed, with everything below
+// it, to a CSV file. At the top level everything is exported.
+func (m *model) exportView(path string) error {
+	tasks := m.allTasks()
+	name := "godo"
+	if len(m.currentPath) > 0 {
+		parent := m.currentPath[len(m.currentPath)-1]
+		if full, found := findTaskByID(tasks, parent.Id); found {
+			tasks = scopedTree(full)
+		}
+		name = parent.Title
+	}
+	if path == "" {
+		path = exportFileName(name)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %v", path, err)
+	}
+	defer file.Close()
+	if err := ExportCSV(file, tasks); err != nil {
+		return err
+	}
+	m.statusMsg = "Exported to " + path
+	return nil
+}
+
+// exportFileName turns a title into a CSV file name without awkward characters
+func exportFileName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return '-'
+	}, title)
+	name = strings.Trim(name, "-")
+	if name == "" {
+		name = "godo"
+	}
+	return name + ".csv"
+}
+
+func init() {
+	registerCommand(paletteCommand{Name: "export", Desc: "Export the current list or task as CSV, optionally to a file", Run: exportCommand})
+}
+
+func exportCommand(m *model, args string) tea.Cmd {
+	if err := m.exportView(args); err != nil {
+		m.statusMsg = "Export failed: " + err.Error()
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package internal
+
+import "strings"
+
+// How subtasks below the first level are kept when syncing to Google Tasks,
+// which only supports one level of nesting. Set with the DeepSubtasks key.
+const (
+	// DeepSubtasksNotes writes deeper subtasks as an indented checklist in
+	// the notes of their first-level ancestor
+	DeepSubtasksNotes = "notes"
+	// DeepSubtasksPrefix moves deeper subtasks up to the first level, with
+	// the titles of the ancestors they lost prefixed to their own
+	DeepSubtasksPrefix = "prefix"
+)
+
+// deepSubtasksMode returns the configured flattening, defaulting to notes
+func deepSubtasksMode() string {
+	if config := GetGlobalConfig(); config != nil && config.DeepSubtasks == DeepSubtasksPrefix {
+		return DeepSubtasksPrefix
+	}
+	return DeepSubtasksNotes
+}
+
+// flattenForGoogle returns the top-level tasks of a list with their subtrees
+// cut down to the single level of subtasks Google Tasks can hold. Nothing is
+// dropped: deeper subtasks end up in notes or as prefixed subtasks.
+func flattenForGoogle(tasks []Task, mode string) []Task {
+	flat := make([]Task, len(tasks))
+	for i, task := range tasks {
+		var children []Task
+		for _, child := range task.Tasks {
+			if child.Deleted {
+				continue
+			}
+			if mode == DeepSubtasksPrefix {
+				children = append(children, hoistSubtasks(child, task.Id, "")...)
+			} else {
+				child.Notes = appendChecklist(child.Notes, child.Tasks, 0)
+				child.Tasks = nil
+				children = append(children, child)
+			}
+		}
+		task.Tasks = children
+		flat[i] = task
+	}
+	return flat
+}
+
+// hoistSubtasks returns task followed by all of its descendants as siblings
+// under parentID, each title prefixed with its path below the first level
+func hoistSubtasks(task Task, parentID, prefix string) []Task {
+	children := task.Tasks
+	task.Parent = parentID
+	task.Title = prefix + task.Title
+	task.Tasks = nil
+
+	hoisted := []Task{task}
+	for _, child := range children {
+		if child.Deleted {
+			continue
+		}
+		hoisted = append(hoisted, hoistSubtasks(child, parentID, task.Title+" › ")...)
+	}
+	return hoisted
+}
+
+// appendChecklist adds tasks and their subtasks to notes as an indented
+// checklist, marking completed ones
+func appendChecklist(notes string, tasks []Task, depth int) string {
+	var lines []string
+	var walk func(tasks []Task, depth int)
+	walk = func(tasks []Task, depth int) {
+		for _, task := range tasks {
+			if task.Deleted {
+				continue
+			}
+			box := "[ ]"
+			if task.Completed {
+				box = "[x]"
+			}
+			lines = append(lines, strings.Repeat("  ", depth)+"- "+box+" "+task.Title)
+			walk(task.Tasks, depth+1)
+		}
+	}
+	walk(tasks, depth)
+
+	if len(lines) == 0 {
+		return notes
+	}
+	if notes == "" {
+		return strings.Join(lines, "\n")
+	}
+	return notes + "\n\n" + strings.Join(lines, "\n")
+}
@@ -0,0 +1,33 @@
+package internal
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// renderFocus draws only the selected task, centered, for distraction-free work
+func (m *model) renderFocus() string {
+	task := m.selectedTask()
+	if task == nil {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center,
+			lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("Nothing to focus on"))
+	}
+
+	width := m.width * 2 / 3
+	if width < 20 {
+		width = m.width
+	}
+
+	var s strings.Builder
+	s.WriteString(lipgloss.NewStyle().Bold(true).Foreground(m.accentColor()).Render(task.Title))
+	if task.Notes != "" {
+		s.WriteString("\n\n" + task.Notes)
+	}
+	if !task.DueDate.IsZero() {
+		s.WriteString("\n\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("Due "+formatDate(task.DueDate)))
+	}
+
+	content := lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(s.String())
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
@@ -4,14 +4,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/oauth2"
@@ -23,9 +25,9 @@ import (
 var (
 	googleConfig *oauth2.Config
 	taskService  *v1.Service
-	UseGoogleTasks bool
+	// UseGoogleTasks is switched at runtime by the UI while sync goroutines read it
+	UseGoogleTasks atomic.Bool
 	taskCache    *GoogleTasksCache
-	currentModel *model // Reference to current UI model
 	GoogleTasksClientVar *GoogleTasksClient
 )
 
@@ -39,6 +41,9 @@ type GoogleTasksCache struct {
 // GoogleTasksClient wraps Google Tasks service
 type GoogleTasksClient struct {
 	service *v1.Service
+
+	etagsMu sync.Mutex
+	etags   map[string]string // Etags of tasks this client updated since the last fetch
 }
 
 // NewGoogleTasksClient returns a new GoogleTasksClient instance
@@ -48,21 +53,27 @@ func NewGoogleTasksClient(service *v1.Service) *GoogleTasksClient {
 
 // CreateTask creates a new task in the specified task list
 func (c *GoogleTasksClient) CreateTask(task Task, listID string) (Task, error) {
+	return c.createTaskAfter(task, listID, "")
+}
+
+// createTaskAfter creates a task right after previousID, one of its
+// siblings, or first among them when previousID is empty
+func (c *GoogleTasksClient) createTaskAfter(task Task, listID, previousID string) (Task, error) {
 	if listID == "" {
 		// Fallback to first list if no list ID provided
-		taskList, err := c.service.Tasklists.List().Do()
-		if err != nil || len(taskList.Items) == 0 {
-			return task, fmt.Errorf("no task lists found: %v", err)
+		var err error
+		listID, err = c.firstListID()
+		if err != nil {
+			return task, err
 		}
-		listID = taskList.Items[0].Id
 	}
 
-	fmt.Printf("Debug: Creating task with Title: %s, Parent: %s in list: %s\n", task.Title, task.Parent, listID)
+	logDebug("Creating task with Title: %s, Parent: %s in list: %s", task.Title, task.Parent, listID)
 
 	// Create the task with required fields
 	newTask := &v1.Task{
 		Title:    task.Title,
-		Notes:    task.Notes,
+		Notes:    encodeNotes(task),
 		Status:   task.Status,
 		Parent:   task.Parent, // This is important for subtasks
 		Position: task.Position,
@@ -73,19 +84,22 @@ func (c *GoogleTasksClient) CreateTask(task Task, listID string) (Task, error) {
 		newTask.Due = task.DueDate.Format(time.RFC3339)
 	}
 
-	var err error
 	var createdTask *v1.Task
-
-	if task.Parent != "" {
-		// If this is a subtask, use Insert with parent
-		createdTask, err = c.service.Tasks.Insert(listID, newTask).Parent(task.Parent).Do()
-	} else {
-		// If this is a top-level task, use regular Insert
-		createdTask, err = c.service.Tasks.Insert(listID, newTask).Do()
-	}
-
+	err := withRetry("create task", func() error {
+		var err error
+		call := c.service.Tasks.Insert(listID, newTask)
+		if task.Parent != "" {
+			// If this is a subtask, use Insert with parent
+			call = call.Parent(task.Parent)
+		}
+		if previousID != "" {
+			call = call.Previous(previousID)
+		}
+		createdTask, err = call.Do()
+		return err
+	})
 	if err != nil {
-		return task, fmt.Errorf("failed to create task: %v", err)
+		return task, err
 	}
 
 	// Update the task with the response from Google Tasks
@@ -99,37 +113,203 @@ func (c *GoogleTasksClient) CreateTask(task Task, listID string) (Task, error) {
 	return task, nil
 }
 
-// UpdateTask updates an existing task in the first task list
-func (c *GoogleTasksClient) UpdateTask(task Task) error {
-	// Implement task update logic
-	taskList, err := c.service.Tasklists.List().Do()
-	if err != nil || len(taskList.Items) == 0 {
-		return fmt.Errorf("no task lists found: %v", err)
+// CreateTaskList creates a new task list and returns its ID
+func (c *GoogleTasksClient) CreateTaskList(title string) (string, error) {
+	var list *v1.TaskList
+	err := withRetry("create task list", func() error {
+		var err error
+		list, err = c.service.Tasklists.Insert(&v1.TaskList{Title: title}).Do()
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create task list: %v", err)
+	}
+	return list.Id, nil
+}
+
+// PushTasks creates a task tree in a list, pointing subtasks at their newly
+// created parents and keeping siblings in order. Pushing to the top of a
+// list first flattens subtasks deeper than Google supports, as set by
+// DeepSubtasks.
+func (c *GoogleTasksClient) PushTasks(listID, parentID string, tasks []Task) error {
+	if parentID == "" {
+		tasks = flattenForGoogle(tasks, deepSubtasksMode())
+	}
+	return c.pushTasks(listID, parentID, tasks, nil, nil)
+}
+
+// pushTasks creates tasks under parentID, each after the one before it, and
+// their subtasks under them. Tasks in existing, which maps local IDs to
+// Google IDs, are already in Google and not created again. pushed, if set,
+// is called with each task and its Google ID.
+func (c *GoogleTasksClient) pushTasks(listID, parentID string, tasks []Task, existing map[string]string, pushed func(task Task, googleID string) error) error {
+	previousID := ""
+	for _, task := range tasks {
+		if task.Deleted {
+			continue
+		}
+		googleID, ok := existing[task.Id]
+		if !ok {
+			task.Parent = parentID
+			created, err := c.createTaskAfter(task, listID, previousID)
+			if err != nil {
+				return fmt.Errorf("error pushing %q: %v", task.Title, err)
+			}
+			googleID = created.Id
+		}
+		if pushed != nil {
+			if err := pushed(task, googleID); err != nil {
+				return err
+			}
+		}
+		if err := c.pushTasks(listID, googleID, task.Tasks, existing, pushed); err != nil {
+			return err
+		}
+		previousID = googleID
+	}
+	return nil
+}
+
+// UpdateTask updates an existing task in the specified task list
+func (c *GoogleTasksClient) UpdateTask(task Task, listID string) error {
+	if listID == "" {
+		// Fallback to first list if no list ID provided
+		var err error
+		listID, err = c.firstListID()
+		if err != nil {
+			return err
+		}
 	}
 
 	updatedTask := &v1.Task{
 		Id:          task.Id,
 		Title:       task.Title,
-		Notes:       task.Notes,
+		Notes:       encodeNotes(task),
 		Status:      task.Status,
 		Due:         task.DueDate.Format(time.RFC3339),
 		Parent:      task.Parent,
 		Position:    task.Position,
 	}
 
-	_, err = c.service.Tasks.Update(taskList.Items[0].Id, task.Id, updatedTask).Do()
-	return err
+	// Only update the task if nobody changed it since we last saw it
+	etag := c.latestEtag(task)
+	var result *v1.Task
+	err := withRetry("update task", func() error {
+		call := c.service.Tasks.Update(listID, task.Id, updatedTask)
+		if etag != "" {
+			call.Header().Set("If-Match", etag)
+		}
+		var err error
+		result, err = call.Do()
+		return err
+	})
+	if isPreconditionFailed(err) {
+		return c.conflict(listID, task, err)
+	}
+	if err != nil {
+		return err
+	}
+	c.rememberEtag(task.Id, result.Etag)
+	return nil
 }
 
-// DeleteTask deletes a task from the first task list
-func (c *GoogleTasksClient) DeleteTask(taskID string) error {
-	// Implement task deletion logic
-	taskList, err := c.service.Tasklists.List().Do()
-	if err != nil || len(taskList.Items) == 0 {
-		return fmt.Errorf("no task lists found: %v", err)
+// DeleteTask deletes a task from the specified task list
+func (c *GoogleTasksClient) DeleteTask(taskID, listID string) error {
+	if listID == "" {
+		// Fallback to first list if no list ID provided
+		var err error
+		listID, err = c.firstListID()
+		if err != nil {
+			return err
+		}
 	}
+	return c.deleteTaskIn(listID, taskID)
+}
 
-	return c.service.Tasks.Delete(taskList.Items[0].Id, taskID).Do()
+// moveTaskIn makes a task a subtask of parentID within the given list
+func (c *GoogleTasksClient) moveTaskIn(listID, taskID, parentID string) error {
+	return withRetry("move task", func() error {
+		_, err := c.service.Tasks.Move(listID, taskID).Parent(parentID).Do()
+		return err
+	})
+}
+
+// moveTaskAfter places a task directly after previousID under parentID, or
+// first when previousID is empty, and returns the position Google gave it
+func (c *GoogleTasksClient) moveTaskAfter(listID, taskID, parentID, previousID string) (string, error) {
+	var moved *v1.Task
+	err := withRetry("move task", func() error {
+		var err error
+		moved, err = c.service.Tasks.Move(listID, taskID).Parent(parentID).Previous(previousID).Do()
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return moved.Position, nil
+}
+
+// deleteTaskIn deletes a task, and with it its subtasks, from the given list
+func (c *GoogleTasksClient) deleteTaskIn(listID, taskID string) error {
+	return withRetry("delete task", func() error {
+		return c.service.Tasks.Delete(listID, taskID).Do()
+	})
+}
+
+// clearCompleted hides every completed task in a list, as the Clear button
+// in Google Tasks does
+func (c *GoogleTasksClient) clearCompleted(listID string) error {
+	return withRetry("clear completed tasks", func() error {
+		return c.service.Tasks.Clear(listID).Do()
+	})
+}
+
+// firstListID returns the ID of the first task list, which single-list operations target
+func (c *GoogleTasksClient) firstListID() (string, error) {
+	var taskList *v1.TaskLists
+	err := withRetry("list task lists", func() error {
+		var err error
+		taskList, err = c.service.Tasklists.List().Do()
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(taskList.Items) == 0 {
+		return "", fmt.Errorf("no task lists found")
+	}
+	return taskList.Items[0].Id, nil
+}
+
+// listIDOf returns the ID of the task list holding the task with the given
+// ID, or empty if it isn't in any list
+func listIDOf(tasks []Task, id string) string {
+	for _, list := range tasks {
+		if list.Kind == "tasks#taskList" && findTask(list.Tasks, id) != nil {
+			return list.Id
+		}
+	}
+	return ""
+}
+
+// FindOrCreateList returns the ID of the task list with the given title,
+// creating the list if there is none
+func (c *GoogleTasksClient) FindOrCreateList(title string) (string, error) {
+	var taskLists *v1.TaskLists
+	err := withRetry("list task lists", func() error {
+		var err error
+		taskLists, err = c.service.Tasklists.List().MaxResults(100).Do()
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	for _, list := range taskLists.Items {
+		if strings.EqualFold(list.Title, title) {
+			return list.Id, nil
+		}
+	}
+	return c.CreateTaskList(title)
 }
 
 // LoadTasks retrieves tasks from the first task list
@@ -138,19 +318,46 @@ func (c *GoogleTasksClient) LoadTasks() ([]Task, error) {
 	return fetchGoogleTasks()
 }
 
-// InitializeGoogleTasks sets up the Google Tasks API client and cache
-func InitializeGoogleTasks() error {
-	// Initialize OAuth2 config
-	config := GetGlobalConfig()
-	googleConfig = &oauth2.Config{
+// newOAuthConfig builds the OAuth2 client config from the godo config
+func newOAuthConfig(config *GodoConfig) *oauth2.Config {
+	return &oauth2.Config{
 		ClientID:     config.GoogleClientID,
 		ClientSecret: config.GoogleClientSecret,
-		RedirectURL:  "http://localhost:8080/callback",
+		RedirectURL:  oauthRedirectURL(oauthCallbackPort()),
 		Scopes: []string{
 			"https://www.googleapis.com/auth/tasks",
 		},
 		Endpoint: google.Endpoint,
 	}
+}
+
+// Reauthenticate runs the OAuth flow again, asking Google to show the
+// consent screen so missing scopes are granted, and saves the new token
+func Reauthenticate() error {
+	config := GetGlobalConfig()
+	if err := ensureGoogleCredentials(config); err != nil {
+		return err
+	}
+	googleConfig = newOAuthConfig(config)
+
+	token, err := getTokenFromWeb(oauth2.ApprovalForce)
+	if err != nil {
+		return fmt.Errorf("error getting token from web: %v", err)
+	}
+	if err := saveToken(token); err != nil {
+		return fmt.Errorf("error saving token: %v", err)
+	}
+	return nil
+}
+
+// InitializeGoogleTasks sets up the Google Tasks API client and cache
+func InitializeGoogleTasks() error {
+	// Initialize OAuth2 config
+	config := GetGlobalConfig()
+	if err := ensureGoogleCredentials(config); err != nil {
+		return err
+	}
+	googleConfig = newOAuthConfig(config)
 
 	// Load or get new token
 	token, err := loadToken()
@@ -185,9 +392,14 @@ func InitializeGoogleTasks() error {
 		mu:       sync.RWMutex{},
 	}
 
+	// Load local deletions so sync doesn't resurrect them
+	if err := loadTombstones(); err != nil {
+		logError("Error loading tombstones: %v", err)
+	}
+
 	// Load cached tasks
 	if err := loadCachedTasks(); err != nil {
-		fmt.Printf("Error loading cache: %v\n", err)
+		logError("Error loading cache: %v", err)
 	}
 
 	// Start background sync
@@ -196,39 +408,114 @@ func InitializeGoogleTasks() error {
 	return nil
 }
 
+var (
+	// syncLoopMu guards stopSync and syncStopped, as connecting starts the
+	// loop from a background command while the UI stops it
+	syncLoopMu sync.Mutex
+	// stopSync ends the running background sync when closed
+	stopSync chan struct{}
+	// syncStopped is closed once that loop has returned
+	syncStopped chan struct{}
+)
+
 func startBackgroundSync() {
-	ticker := time.NewTicker(30 * time.Second)
+	// Connecting again, e.g. after switching modes, replaces the old loop
+	stopBackgroundSync()
+
+	interval := syncInterval()
+	if interval == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	stop, stopped := make(chan struct{}), make(chan struct{})
+	syncLoopMu.Lock()
+	stopSync, syncStopped = stop, stopped
+	syncLoopMu.Unlock()
 	go func() {
-		for range ticker.C {
-			tasks, err := fetchGoogleTasks()
+		defer close(stopped)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+
+			purgeTombstones()
+
+			tasks, changed, err := refreshGoogleCache()
 			if err != nil {
-				fmt.Printf("Error in background sync: %v\n", err)
+				reportSyncError(err)
+				var perm *PermissionError
+				if errors.As(err, &perm) {
+					// Syncing again won't help until the user acts
+					return
+				}
 				continue
 			}
-
-			taskCache.mu.Lock()
-			if !tasksEqual(taskCache.Tasks, tasks) {
-				fmt.Println("New tasks found in background sync, updating...")
-				taskCache.Tasks = tasks
-				taskCache.LastSync = time.Now()
-				if err := saveCachedTasks(); err != nil {
-					fmt.Printf("Error saving to cache: %v\n", err)
+			if autoClearEnabled() {
+				var cleared int
+				tasks, cleared, err = ClearCompleted(tasks, listIDs(tasks), GoogleTasksClientVar)
+				if err != nil {
+					reportSyncError(err)
 				}
+				changed = changed || cleared > 0
+			}
+			if changed {
 				notifyUIOfChanges(tasks)
 			}
-			taskCache.mu.Unlock()
 		}
 	}()
 }
 
+// stopBackgroundSync ends the background sync loop, if one is running. The
+// channel it returns is closed once the loop has finished any refresh it was
+// in the middle of. The loop may be sending to the UI, so only wait on it
+// from a command, never in Update.
+func stopBackgroundSync() <-chan struct{} {
+	syncLoopMu.Lock()
+	defer syncLoopMu.Unlock()
+	stopped := syncStopped
+	if stopSync != nil {
+		close(stopSync)
+		stopSync, syncStopped = nil, nil
+	}
+	if stopped == nil {
+		stopped = make(chan struct{})
+		close(stopped)
+	}
+	return stopped
+}
+
+// minSyncInterval keeps background sync from hammering the API
+const minSyncInterval = 10 * time.Second
+
+// syncInterval returns how often to sync in the background, or 0 if disabled
+func syncInterval() time.Duration {
+	config := GetGlobalConfig()
+	if config == nil {
+		return 30 * time.Second
+	}
+	if config.SyncIntervalSeconds <= 0 {
+		return 0
+	}
+	return max(time.Duration(config.SyncIntervalSeconds)*time.Second, minSyncInterval)
+}
+
+// cacheFileData is the layout of the cache file. LastSync is kept in the
+// file because saving edits rewrites it without fetching anything.
+type cacheFileData struct {
+	LastSync time.Time `json:"lastSync"`
+	Tasks    []Task    `json:"tasks"`
+}
+
 func loadCachedTasks() error {
-	home, err := os.UserHomeDir()
+	cacheFile, err := googleCacheFile()
 	if err != nil {
-		return fmt.Errorf("error getting home directory: %v", err)
+		return err
 	}
 
-	cacheFile := filepath.Join(home, ".local", "share", "godo", "google_tasks_cache.json")
-	
 	data, err := os.ReadFile(cacheFile)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -240,32 +527,102 @@ func loadCachedTasks() error {
 		return fmt.Errorf("error reading cache file: %v", err)
 	}
 
-	var tasks []Task
-	if err := json.Unmarshal(data, &tasks); err != nil {
-		return fmt.Errorf("error unmarshaling cache: %v", err)
+	var cached cacheFileData
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		// Caches from before LastSync was saved hold just the tasks
+		err = json.Unmarshal(data, &cached.Tasks)
+		if info, statErr := os.Stat(cacheFile); statErr == nil {
+			cached.LastSync = info.ModTime()
+		}
+	} else {
+		err = json.Unmarshal(data, &cached)
+	}
+	if err != nil {
+		// The next fetch rebuilds it, so a broken cache is just dropped
+		logError("Cache is corrupt, clearing it: %v", err)
+		taskCache.Tasks = make([]Task, 0)
+		taskCache.LastSync = time.Time{}
+		return os.Remove(cacheFile)
+	}
+	if ttl := cacheTTL(); ttl > 0 && time.Since(cached.LastSync) > ttl {
+		// Too old to show, so the UI waits for a fresh fetch instead
+		logInfo("Cache is older than %v, ignoring it", ttl)
+		taskCache.Tasks = make([]Task, 0)
+		taskCache.LastSync = time.Time{}
+		return nil
 	}
 
-	taskCache.Tasks = tasks
-	fmt.Printf("Cache loaded from: %s\n", cacheFile) // Debug print
+	taskCache.Tasks = cached.Tasks
+	taskCache.LastSync = cached.LastSync
 	return nil
 }
 
+// CachedTasks returns the tasks from the last sync so the UI can start before fetching
+func CachedTasks() []Task {
+	if taskCache == nil {
+		return nil
+	}
+	taskCache.mu.RLock()
+	defer taskCache.mu.RUnlock()
+	recordCacheLookup(len(taskCache.Tasks) > 0)
+	// The UI edits what it is given, so it gets its own copy
+	return cloneTasks(taskCache.Tasks)
+}
+
+// syncMu serializes fetches so a manual sync never overlaps a scheduled one
+var syncMu sync.Mutex
+
+// refreshGoogleCache fetches the latest tasks and stores them in the cache,
+// reporting whether anything changed since the last sync
+func refreshGoogleCache() ([]Task, bool, error) {
+	syncMu.Lock()
+	defer syncMu.Unlock()
+
+	start := time.Now()
+	tasks, err := fetchGoogleTasks()
+	if err != nil {
+		return nil, false, err
+	}
+
+	taskCache.mu.Lock()
+	defer taskCache.mu.Unlock()
+	changed := !tasksEqual(taskCache.Tasks, tasks)
+	recordSync(time.Since(start), changed)
+	taskCache.Tasks = tasks
+	taskCache.LastSync = time.Now()
+	// Saved even when nothing changed, to record when the fetch happened
+	if err := saveCachedTasks(); err != nil {
+		logError("Error saving to cache: %v", err)
+	}
+	// The cache keeps the fetched tree; callers get a copy they may change
+	return cloneTasks(tasks), changed, nil
+}
+
+// saveGoogleCopy makes tasks the cached copy, as edits made since the last
+// fetch are newer than it. LastSync stays the time of that fetch.
+func saveGoogleCopy(tasks []Task) error {
+	if taskCache == nil {
+		return fmt.Errorf("Google Tasks client not initialized")
+	}
+	taskCache.mu.Lock()
+	defer taskCache.mu.Unlock()
+	taskCache.Tasks = cloneTasks(tasks)
+	return saveCachedTasks()
+}
+
 func saveCachedTasks() error {
-	// Ensure cache directory exists
-	home, err := os.UserHomeDir()
+	cacheFile, err := googleCacheFile()
 	if err != nil {
-		return fmt.Errorf("error getting home directory: %v", err)
+		return err
 	}
 
-	cacheDir := filepath.Join(home, ".local", "share", "godo")
-	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+	// Ensure cache directory exists
+	if err := os.MkdirAll(filepath.Dir(cacheFile), 0755); err != nil {
 		return fmt.Errorf("error creating cache directory: %v", err)
 	}
-
-	cacheFile := filepath.Join(cacheDir, "google_tasks_cache.json")
 	
 	// Marshal tasks with indentation for readability
-	data, err := json.MarshalIndent(taskCache.Tasks, "", "  ")
+	data, err := json.MarshalIndent(cacheFileData{LastSync: taskCache.LastSync, Tasks: taskCache.Tasks}, "", "  ")
 	if err != nil {
 		return fmt.Errorf("error marshaling tasks: %v", err)
 	}
@@ -282,34 +639,35 @@ func saveCachedTasks() error {
 		return fmt.Errorf("error renaming cache file: %v", err)
 	}
 
-	fmt.Printf("Cache saved to: %s\n", cacheFile) // Debug print
+	logDebug("Cache saved to: %s", cacheFile)
 	return nil
 }
 
 func ImportTasks() ([]Task, error) {
-	if UseGoogleTasks {
+	if UseGoogleTasks.Load() {
 		// First try to load from cache
 		if err := loadCachedTasks(); err != nil {
-			fmt.Printf("Error loading cache: %v\n", err)
+			logError("Error loading cache: %v", err)
 		}
 
 		// Start background fetch from Google immediately
 		go func() {
 			tasks, err := fetchGoogleTasks()
 			if err != nil {
-				fmt.Printf("Error fetching from Google: %v\n", err)
+				logError("Error fetching from Google: %v", err)
 				return
 			}
 
 			taskCache.mu.Lock()
-			if !tasksEqual(taskCache.Tasks, tasks) {
-				fmt.Println("New tasks found in Google, updating...")
-				taskCache.Tasks = tasks
-				taskCache.LastSync = time.Now()
-				if err := saveCachedTasks(); err != nil {
-					fmt.Printf("Error saving to cache: %v\n", err)
-				}
-				notifyUIOfChanges(tasks)
+			changed := !tasksEqual(taskCache.Tasks, tasks)
+			taskCache.Tasks = tasks
+			taskCache.LastSync = time.Now()
+			if err := saveCachedTasks(); err != nil {
+				logError("Error saving to cache: %v", err)
+			}
+			if changed {
+				logInfo("New tasks found in Google, updating...")
+				notifyUIOfChanges(cloneTasks(tasks))
 			}
 			taskCache.mu.Unlock()
 		}()
@@ -319,14 +677,14 @@ func ImportTasks() ([]Task, error) {
 		defer taskCache.mu.RUnlock()
 		
 		if len(taskCache.Tasks) > 0 {
-			fmt.Println("Showing cached tasks while fetching from Google...")
+			fmt.Fprintln(os.Stderr, "Showing cached tasks while fetching from Google...")
 			cachedTasks := make([]Task, len(taskCache.Tasks))
 			copy(cachedTasks, taskCache.Tasks)
 			return cachedTasks, nil
 		}
 
 		// If no cache, wait for Google fetch
-		fmt.Println("No cached tasks available, fetching from Google...")
+		fmt.Fprintln(os.Stderr, "No cached tasks available, fetching from Google...")
 		return fetchGoogleTasks()
 	}
 	return ImportFromLocal()
@@ -344,8 +702,7 @@ func tasksEqual(a, b []Task) bool {
 }
 
 func loadToken() (*oauth2.Token, error) {
-	config := GetGlobalConfig()
-	tokenFile := os.ExpandEnv(config.GoogleTokenPath)
+	tokenFile := GoogleTokenPath()
 	
 	f, err := os.Open(tokenFile)
 	if err != nil {
@@ -359,8 +716,7 @@ func loadToken() (*oauth2.Token, error) {
 }
 
 func saveToken(token *oauth2.Token) error {
-	config := GetGlobalConfig()
-	tokenFile := os.ExpandEnv(config.GoogleTokenPath)
+	tokenFile := GoogleTokenPath()
 	
 	// Ensure directory exists
 	dir := filepath.Dir(tokenFile)
@@ -394,28 +750,23 @@ func saveToken(token *oauth2.Token) error {
 		return fmt.Errorf("error saving token file: %v", err)
 	}
 
-	fmt.Printf("Token saved to: %s\n", tokenFile)
+	logInfo("Token saved to: %s", tokenFile)
 	return nil
 }
 
-func getTokenFromWeb() (*oauth2.Token, error) {
+func getTokenFromWeb(opts ...oauth2.AuthCodeOption) (*oauth2.Token, error) {
+	// Start local server to receive callback, failing now if the port is taken
+	callback, err := listenForCallback(oauthCallbackPort())
+	if err != nil {
+		return nil, err
+	}
+
+	// The redirect has to name the port actually bound
+	config := *googleConfig
+	config.RedirectURL = callback.redirectURL()
+
 	// Generate OAuth URL
-	authURL := googleConfig.AuthCodeURL("state-token", oauth2.AccessTypeOffline)
-	
-	// Start local server to receive callback
-	ch := make(chan string)
-	server := &http.Server{Addr: ":8080"}
-	
-	// Handle callback
-	http.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
-		code := r.URL.Query().Get("code")
-		ch <- code
-		fmt.Fprintf(w, "Authorization successful! You can close this window.")
-		go func() {
-			time.Sleep(time.Second)
-			server.Shutdown(context.Background())
-		}()
-	})
+	authURL := config.AuthCodeURL("state-token", append([]oauth2.AuthCodeOption{oauth2.AccessTypeOffline}, opts...)...)
 
 	// Open browser
 	fmt.Printf("Opening browser for authentication...\n")
@@ -423,18 +774,14 @@ func getTokenFromWeb() (*oauth2.Token, error) {
 		fmt.Printf("Failed to open browser automatically. Please open this URL in your browser:\n%v\n", authURL)
 	}
 
-	// Start server
-	go func() {
-		if err := server.ListenAndServe(); err != http.ErrServerClosed {
-			fmt.Printf("Server error: %v\n", err)
-		}
-	}()
-
 	// Wait for code
-	code := <-ch
+	code, err := callback.wait()
+	if err != nil {
+		return nil, err
+	}
 
 	// Exchange code for token
-	token, err := googleConfig.Exchange(context.Background(), code)
+	token, err := config.Exchange(context.Background(), code)
 	if err != nil {
 		return nil, fmt.Errorf("failed to exchange token: %v", err)
 	}
@@ -463,13 +810,12 @@ func open(url string) error {
 }
 
 func ImportFromLocal() ([]Task, error) {
-	// Read from local storage
-	home, err := os.UserHomeDir()
+	// Read from the active profile in local storage
+	tasksFile, err := tasksFilePath()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %v", err)
+		return nil, err
 	}
 
-	tasksFile := filepath.Join(home, ".local", "share", "godo", "tasks.json")
 	data, err := os.ReadFile(tasksFile)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -478,38 +824,33 @@ func ImportFromLocal() ([]Task, error) {
 		return nil, fmt.Errorf("failed to read tasks file: %v", err)
 	}
 
-	var tasks []Task
-	if err := json.Unmarshal(data, &tasks); err != nil {
+	tasks, err := decodeTaskFile(data)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse tasks file: %v", err)
 	}
 
-	return tasks, nil
+	return pruneDeletedTasks(tasks), nil
 }
 
 func SaveGoogleTasks(tasks []Task) error {
-	if UseGoogleTasks {
+	if UseGoogleTasks.Load() {
 		return ExportToGoogle(tasks)
 	}
 	return SaveToLocal(tasks)
 }
 
 func SaveToLocal(tasks []Task) error {
-	home, err := os.UserHomeDir()
+	tasksFile, err := tasksFilePath()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %v", err)
-	}
-
-	tasksDir := filepath.Join(home, ".local", "share", "godo")
-	if err := os.MkdirAll(tasksDir, 0755); err != nil {
-		return fmt.Errorf("failed to create tasks directory: %v", err)
+		return err
 	}
 
-	tasksFile := filepath.Join(tasksDir, "tasks.json")
-	data, err := json.MarshalIndent(tasks, "", "  ")
+	data, err := encodeTaskFile(tasks)
 	if err != nil {
 		return fmt.Errorf("failed to marshal tasks: %v", err)
 	}
 
+	recordOwnWrite(data)
 	if err := os.WriteFile(tasksFile, data, 0644); err != nil {
 		return fmt.Errorf("failed to write tasks file: %v", err)
 	}
@@ -539,16 +880,23 @@ func ExportToGoogle(tasks []Task) error {
 
 		var err error
 		if taskList.Id != "" {
-			_, err = GoogleTasksClientVar.service.Tasklists.Update(taskList.Id, googleTaskList).Do()
+			err = withRetry("update task list", func() error {
+				_, err := GoogleTasksClientVar.service.Tasklists.Update(taskList.Id, googleTaskList).Do()
+				return err
+			})
 		} else {
-			_, err = GoogleTasksClientVar.service.Tasklists.Insert(googleTaskList).Do()
+			err = withRetry("create task list", func() error {
+				_, err := GoogleTasksClientVar.service.Tasklists.Insert(googleTaskList).Do()
+				return err
+			})
 		}
 		if err != nil {
 			return fmt.Errorf("failed to update/create task list: %v", err)
 		}
 
 		// Export tasks in this list
-		if err := exportTasksInList(taskList.Id, taskList.Tasks); err != nil {
+		// Google keeps one level of subtasks; flatten the rest rather than lose it
+		if err := exportTasksInList(taskList.Id, flattenForGoogle(taskList.Tasks, deepSubtasksMode())); err != nil {
 			return err
 		}
 	}
@@ -558,10 +906,15 @@ func ExportToGoogle(tasks []Task) error {
 
 func exportTasksInList(listID string, tasks []Task) error {
 	for _, task := range tasks {
+		// Tombstoned tasks were already deleted remotely
+		if task.Deleted {
+			continue
+		}
+
 		googleTask := &v1.Task{
 			Id:       task.Id,
 			Title:    task.Title,
-			Notes:    task.Notes,
+			Notes:    encodeNotes(task),
 			Status:   task.Status,
 			Parent:   task.Parent,
 			Position: task.Position,
@@ -583,12 +936,12 @@ func exportTasksInList(listID string, tasks []Task) error {
 
 		var err error
 		if task.Id != "" {
-			err = GoogleTasksClientVar.UpdateTask(task)
+			err = GoogleTasksClientVar.UpdateTask(task, listID)
 		} else {
 			_, err = GoogleTasksClientVar.CreateTask(task, listID)
 		}
 		if err != nil {
-			return fmt.Errorf("failed to update/create task: %v", err)
+			return fmt.Errorf("failed to update/create task: %w", err)
 		}
 
 		// Recursively export child tasks
@@ -606,10 +959,15 @@ func buildTaskHierarchy(tasks []*v1.Task, taskMap map[string]*Task) []Task {
 		return tasks[i].Position < tasks[j].Position
 	})
 
-	// Find all root tasks (tasks with no parent)
+	// Find all root tasks (tasks with no parent). A task whose parent is
+	// missing, because it was deleted or hasn't synced yet, becomes a root too
+	// rather than vanishing from the tree.
 	var rootTasks []Task
 	for _, googleTask := range tasks {
 		task := taskMap[googleTask.Id]
+		if _, ok := taskMap[task.Parent]; task.Parent != "" && !ok {
+			task.Parent = ""
+		}
 		if task.Parent == "" {
 			// Find all children of this task
 			task.Tasks = findChildren(googleTask.Id, tasks, taskMap)
@@ -636,14 +994,9 @@ func findChildren(parentID string, allTasks []*v1.Task, taskMap map[string]*Task
 	return children
 }
 
-func SetCurrentModel(m *model) {
-	currentModel = m
-}
-
+// notifyUIOfChanges hands a fresh task tree to the running UI
 func notifyUIOfChanges(tasks []Task) {
-	if currentModel != nil {
-		currentModel.UpdateTasks(tasks)
-	}
+	sendToUI(tasksUpdatedMsg(tasks))
 }
 
 func fetchGoogleTasks() ([]Task, error) {
@@ -652,11 +1005,19 @@ func fetchGoogleTasks() ([]Task, error) {
 	}
 	
 	// Get all task lists
-	taskLists, err := GoogleTasksClientVar.service.Tasklists.List().Do()
+	var taskLists *v1.TaskLists
+	err := withRetry("list task lists", func() error {
+		var err error
+		taskLists, err = GoogleTasksClientVar.service.Tasklists.List().Do()
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("Unable to retrieve task lists: %v", err)
+		return nil, fmt.Errorf("Unable to retrieve task lists: %w", err)
 	}
 
+	// The fetched tasks carry fresh etags
+	GoogleTasksClientVar.forgetEtags()
+
 	var allTasks []Task
 	
 	// For each task list
@@ -673,51 +1034,26 @@ func fetchGoogleTasks() ([]Task, error) {
 		}
 		
 		// Get all tasks in this list
-		tasks, err := GoogleTasksClientVar.service.Tasks.List(taskList.Id).Do()
+		var tasks *v1.Tasks
+		err := withRetry("list tasks", func() error {
+			var err error
+			tasks, err = GoogleTasksClientVar.service.Tasks.List(taskList.Id).Do()
+			return err
+		})
 		if err != nil {
-			fmt.Printf("Unable to retrieve tasks for list %s: %v\n", taskList.Title, err)
+			var perm *PermissionError
+			if errors.As(err, &perm) {
+				// Every list will fail the same way
+				return nil, err
+			}
+			logError("Unable to retrieve tasks for list %s: %v", taskList.Title, err)
 			continue
 		}
 
 		// First pass: create all tasks
 		taskMap := make(map[string]*Task)
 		for _, googleTask := range tasks.Items {
-			task := Task{
-				Id:          googleTask.Id,
-				Title:       googleTask.Title,
-				Notes:       googleTask.Notes,
-				Status:      googleTask.Status,
-				Completed:   googleTask.Status == "completed",
-				Parent:      googleTask.Parent,
-				Position:    googleTask.Position,
-				Kind:        googleTask.Kind,
-				SelfLink:    googleTask.SelfLink,
-				Etag:        googleTask.Etag,
-				Tasks:       []Task{},
-			}
-
-			// Parse due date if present
-			if googleTask.Due != "" {
-				if dueDate, err := time.Parse(time.RFC3339, googleTask.Due); err == nil {
-					task.DueDate = dueDate
-				}
-			}
-
-			// Parse completed date if present
-			if googleTask.Completed != nil {
-				if completedDate, err := time.Parse(time.RFC3339, *googleTask.Completed); err == nil {
-					task.CompletedDate = completedDate
-					task.Completed = true
-				}
-			}
-
-			// Parse updated time if present
-			if googleTask.Updated != "" {
-				if updatedTime, err := time.Parse(time.RFC3339, googleTask.Updated); err == nil {
-					task.Updated = updatedTime
-				}
-			}
-
+			task := taskFromGoogle(googleTask)
 			taskMap[task.Id] = &task
 		}
 
@@ -725,6 +1061,50 @@ func fetchGoogleTasks() ([]Task, error) {
 		listTask.Tasks = buildTaskHierarchy(tasks.Items, taskMap)
 		allTasks = append(allTasks, listTask)
 	}
-	
-	return allTasks, nil
-}
\ No newline at end of file
+
+	// Local deletions newer than the remote copy win
+	return sortFetchedLists(applyTombstones(allTasks)), nil
+}
+
+// taskFromGoogle converts a task from the API, without its subtasks
+func taskFromGoogle(googleTask *v1.Task) Task {
+	task := Task{
+		Id:        googleTask.Id,
+		Title:     googleTask.Title,
+		Notes:     googleTask.Notes,
+		Status:    googleTask.Status,
+		Completed: googleTask.Status == "completed",
+		Parent:    googleTask.Parent,
+		Position:  googleTask.Position,
+		Kind:      googleTask.Kind,
+		SelfLink:  googleTask.SelfLink,
+		Etag:      googleTask.Etag,
+		Tasks:     []Task{},
+	}
+
+	// Parse due date if present
+	if googleTask.Due != "" {
+		if dueDate, err := time.Parse(time.RFC3339, googleTask.Due); err == nil {
+			task.DueDate = dueDate
+		}
+	}
+
+	// Parse completed date if present
+	if googleTask.Completed != nil {
+		if completedDate, err := time.Parse(time.RFC3339, *googleTask.Completed); err == nil {
+			task.CompletedDate = completedDate
+			task.Completed = true
+		}
+	}
+
+	// Parse updated time if present
+	if googleTask.Updated != "" {
+		if updatedTime, err := time.Parse(time.RFC3339, googleTask.Updated); err == nil {
+			task.Updated = updatedTime
+		}
+	}
+
+	// Pull godo-only fields back out of the notes
+	decodeNotes(&task)
+	return task
+}
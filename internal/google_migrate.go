@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// DefaultMigrateList is the Google list local tasks outside any list go to
+const DefaultMigrateList = "Local tasks"
+
+// migrateProgressFile records which local tasks already exist in Google, so
+// a migration that failed part way can pick up where it stopped
+const migrateProgressFile = "migrate_progress.json"
+
+// migrateProgress maps local task IDs to the IDs of their Google copies
+type migrateProgress map[string]string
+
+// loadMigrateProgress reads the progress of an earlier, unfinished migration
+func loadMigrateProgress() (migrateProgress, error) {
+	progress := make(migrateProgress)
+	path, err := storageFile(accountFile(migrateProgressFile))
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return progress, nil
+		}
+		return nil, fmt.Errorf("error reading migration progress: %v", err)
+	}
+	if err := json.Unmarshal(data, &progress); err != nil {
+		return nil, fmt.Errorf("error parsing migration progress: %v", err)
+	}
+	return progress, nil
+}
+
+// save writes the progress after every task, so a crash loses nothing
+func (p migrateProgress) save() error {
+	path, err := storageFile(accountFile(migrateProgressFile))
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("error marshaling migration progress: %v", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// migrateBatch is a set of tasks headed for one Google list
+type migrateBatch struct {
+	List  string
+	Tasks []Task
+}
+
+// migrateBatches groups local tasks by destination list. Local lists keep
+// their names and loose tasks go to DefaultMigrateList, unless listTitle is
+// set, in which case everything goes there.
+func migrateBatches(tasks []Task, listTitle string) []migrateBatch {
+	var batches []migrateBatch
+	var loose []Task
+	for _, task := range tasks {
+		switch {
+		case task.Deleted:
+		case task.Kind != "tasks#taskList":
+			loose = append(loose, task)
+		case listTitle != "":
+			loose = append(loose, task.Tasks...)
+		default:
+			batches = append(batches, migrateBatch{List: task.Title, Tasks: task.Tasks})
+		}
+	}
+	if len(loose) > 0 {
+		if listTitle == "" {
+			listTitle = DefaultMigrateList
+		}
+		batches = append(batches, migrateBatch{List: listTitle, Tasks: loose})
+	}
+	return batches
+}
+
+// countMigrated counts the tasks a batch will create once flattened
+func countMigrated(tasks []Task) int {
+	count := 0
+	for _, task := range tasks {
+		if !task.Deleted {
+			count += 1 + countMigrated(task.Tasks)
+		}
+	}
+	return count
+}
+
+// MigrateToGoogle pushes the local tasks file to Google Tasks, creating the
+// destination lists as needed. Subtasks deeper than Google allows are
+// flattened as DeepSubtasks says. report is called after each task with the
+// number done so far and the total. Tasks pushed by an earlier, failed run
+// are not created again.
+func MigrateToGoogle(client *GoogleTasksClient, listTitle string, report func(done, total int, task Task)) (int, error) {
+	tasks, err := ImportFromLocal()
+	if err != nil {
+		return 0, err
+	}
+	progress, err := loadMigrateProgress()
+	if err != nil {
+		return 0, err
+	}
+
+	batches := migrateBatches(tasks, listTitle)
+	total := 0
+	for i := range batches {
+		batches[i].Tasks = flattenForGoogle(batches[i].Tasks, deepSubtasksMode())
+		total += countMigrated(batches[i].Tasks)
+	}
+
+	done := 0
+	pushed := func(task Task, googleID string) error {
+		if progress[task.Id] != googleID {
+			progress[task.Id] = googleID
+			if err := progress.save(); err != nil {
+				return err
+			}
+		}
+		done++
+		report(done, total, task)
+		return nil
+	}
+
+	for _, batch := range batches {
+		listID, err := client.FindOrCreateList(batch.List)
+		if err != nil {
+			return done, fmt.Errorf("error creating list %s: %v", batch.List, err)
+		}
+		if err := client.pushTasks(listID, "", batch.Tasks, progress, pushed); err != nil {
+			return done, fmt.Errorf("%v; run the migration again to resume", err)
+		}
+	}
+
+	// Finished, so the next migration starts from scratch
+	if path, err := storageFile(accountFile(migrateProgressFile)); err == nil {
+		os.Remove(path)
+	}
+	return done, nil
+}
+
+// UseGoogleByDefault makes Google Tasks the storage mode godo starts in
+func UseGoogleByDefault() error {
+	return setConfigValues(configFilePath, map[string]string{"UseGoogleTasks": "true"})
+}
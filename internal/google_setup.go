@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// googleSetupGuide explains how to get OAuth credentials for Google mode
+const googleSetupGuide = `Google Tasks needs OAuth credentials before godo can connect.
+
+  1. Open https://console.cloud.google.com/apis/credentials and create or pick a project.
+  2. Enable the Google Tasks API under "APIs & Services" > "Library".
+  3. Configure the OAuth consent screen and add yourself as a test user.
+  4. Create credentials > OAuth client ID > "Desktop app".
+  5. For a "Web application" client instead, add http://localhost:8080/callback
+     as an authorized redirect URI, changing 8080 if you set OAuthCallbackPort.
+  6. Copy the client ID and secret into %s:
+
+       GoogleClientID=<your client ID>
+       GoogleClientSecret=<your client secret>
+`
+
+// ensureGoogleCredentials makes sure a client ID and secret are configured
+// before the OAuth flow starts. On a terminal it offers to prompt for them;
+// otherwise it returns the setup steps as the error.
+func ensureGoogleCredentials(config *GodoConfig) error {
+	if config.GoogleClientID != "" && config.GoogleClientSecret != "" {
+		return nil
+	}
+
+	guide := fmt.Sprintf(googleSetupGuide, configFilePath)
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return fmt.Errorf("missing Google credentials\n\n%s", guide)
+	}
+
+	fmt.Println(guide)
+	return promptGoogleCredentials(config, os.Stdin, os.Stdout)
+}
+
+// promptGoogleCredentials asks for the client ID and secret and saves them to the config file
+func promptGoogleCredentials(config *GodoConfig, in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	ask := func(prompt, current string) (string, error) {
+		if current != "" {
+			return current, nil
+		}
+		fmt.Fprint(out, prompt)
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("setup cancelled")
+		}
+		value := strings.TrimSpace(line)
+		if value == "" {
+			return "", fmt.Errorf("setup cancelled")
+		}
+		return value, nil
+	}
+
+	clientID, err := ask("Paste your client ID (empty to cancel): ", config.GoogleClientID)
+	if err != nil {
+		return err
+	}
+	clientSecret, err := ask("Paste your client secret (empty to cancel): ", config.GoogleClientSecret)
+	if err != nil {
+		return err
+	}
+
+	if err := setConfigValues(configFilePath, map[string]string{
+		"GoogleClientID":     clientID,
+		"GoogleClientSecret": clientSecret,
+	}); err != nil {
+		return fmt.Errorf("error saving credentials: %v", err)
+	}
+	config.GoogleClientID = clientID
+	config.GoogleClientSecret = clientSecret
+	fmt.Fprintf(out, "Saved credentials to %s\n", configFilePath)
+	return nil
+}
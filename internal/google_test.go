@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "google.golang.org/api/tasks/v1"
+)
+
+// treeShape renders a task tree as IDs with their subtasks, for comparing
+func treeShape(tasks []Task) map[string]any {
+	shape := make(map[string]any)
+	for _, task := range tasks {
+		shape[task.Id] = treeShape(task.Tasks)
+	}
+	return shape
+}
+
+func TestBuildTaskHierarchy(t *testing.T) {
+	tests := []struct {
+		name  string
+		tasks []*v1.Task
+		want  map[string]any
+	}{
+		{
+			name: "subtasks nest under their parent",
+			tasks: []*v1.Task{
+				{Id: "a", Position: "1"},
+				{Id: "b", Parent: "a", Position: "1"},
+			},
+			want: map[string]any{"a": map[string]any{"b": map[string]any{}}},
+		},
+		{
+			name: "a dangling parent reference becomes a root",
+			tasks: []*v1.Task{
+				{Id: "a", Position: "1"},
+				{Id: "orphan", Parent: "deleted", Position: "2"},
+			},
+			want: map[string]any{"a": map[string]any{}, "orphan": map[string]any{}},
+		},
+		{
+			name: "the subtasks of a promoted orphan stay with it",
+			tasks: []*v1.Task{
+				{Id: "child", Parent: "orphan", Position: "1"},
+				{Id: "orphan", Parent: "deleted", Position: "1"},
+			},
+			want: map[string]any{"orphan": map[string]any{"child": map[string]any{}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskMap := make(map[string]*Task)
+			for _, googleTask := range tt.tasks {
+				task := taskFromGoogle(googleTask)
+				taskMap[task.Id] = &task
+			}
+
+			roots := buildTaskHierarchy(tt.tasks, taskMap)
+
+			if got := treeShape(roots); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got tree %v, want %v", got, tt.want)
+			}
+			for _, root := range roots {
+				if root.Parent != "" {
+					t.Errorf("root %s still points at parent %q", root.Id, root.Parent)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// TokenStatus describes the stored Google token
+type TokenStatus struct {
+	Path       string
+	Exists     bool
+	Expiry     time.Time // When the access token expires, zero if never
+	Refresh    bool      // A refresh token is stored, so expiry is renewed
+	Valid      bool      // The token can be used now, refreshing it if needed
+	RefreshErr error     // Why refreshing an expired token failed
+}
+
+// GoogleTokenPath is the active account's token file, from the configured
+// GoogleTokenPath
+func GoogleTokenPath() string {
+	return accountFile(os.ExpandEnv(GetGlobalConfig().GoogleTokenPath))
+}
+
+// GoogleTokenInfo reads the stored token and, if the access token has
+// expired, tries to refresh it to tell whether it still works. A token
+// file that can't be read is an error.
+func GoogleTokenInfo() (TokenStatus, error) {
+	status := TokenStatus{Path: GoogleTokenPath()}
+	token, err := loadToken()
+	if os.IsNotExist(err) {
+		return status, nil
+	}
+	if err != nil {
+		return status, fmt.Errorf("error reading %s: %v", status.Path, err)
+	}
+	status.Exists = true
+	status.Expiry = token.Expiry
+	status.Refresh = token.RefreshToken != ""
+	if token.Valid() {
+		status.Valid = true
+		return status, nil
+	}
+	if !status.Refresh {
+		return status, nil
+	}
+
+	config := GetGlobalConfig()
+	if config.GoogleClientID == "" || config.GoogleClientSecret == "" {
+		status.RefreshErr = fmt.Errorf("no Google credentials in the config")
+		return status, nil
+	}
+	fresh, err := newOAuthConfig(config).TokenSource(context.Background(), token).Token()
+	if err != nil {
+		status.RefreshErr = err
+		return status, nil
+	}
+	status.Valid = true
+	status.Expiry = fresh.Expiry
+	return status, nil
+}
+
+// ResetGoogleToken deletes the stored token and signs in from scratch
+func ResetGoogleToken() error {
+	if err := os.Remove(GoogleTokenPath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error deleting token: %v", err)
+	}
+	return Reauthenticate()
+}
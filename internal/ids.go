@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ID schemes for new local tasks, set with IDScheme. IDs already in the
+// tasks file are kept whatever scheme made them.
+const (
+	IDShort = "short" // Time, a per-process counter and a random suffix
+	IDUUID  = "uuid"  // Random UUIDs
+)
+
+// idCounter tells apart IDs created in the same millisecond
+var idCounter atomic.Uint64
+
+// generateID creates a unique ID for a new task
+func generateID() string {
+	scheme := IDShort
+	if config := GetGlobalConfig(); config != nil && config.IDScheme != "" {
+		scheme = strings.ToLower(config.IDScheme)
+	}
+	if scheme == IDUUID {
+		return uuid.NewString()
+	}
+	return shortID(time.Now())
+}
+
+// shortID builds a compact ID from the time, in base 36, and a counter, so
+// IDs from one process never repeat, plus a random suffix so IDs from
+// separate processes don't either
+func shortID(now time.Time) string {
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		logError("Error reading random ID suffix: %v", err)
+	}
+	return strconv.FormatInt(now.UnixMilli(), 36) + "-" +
+		strconv.FormatUint(idCounter.Add(1), 36) + hex.EncodeToString(suffix)
+}
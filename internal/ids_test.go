@@ -0,0 +1,21 @@
+package internal
+
+import "testing"
+
+func TestGenerateIDUnique(t *testing.T) {
+	defer SetGlobalConfig(GetGlobalConfig())
+
+	for _, scheme := range []string{IDShort, IDUUID} {
+		t.Run(scheme, func(t *testing.T) {
+			SetGlobalConfig(&GodoConfig{IDScheme: scheme})
+			seen := make(map[string]bool)
+			for i := 0; i < 10000; i++ {
+				id := generateID()
+				if seen[id] {
+					t.Fatalf("ID %s came up twice after %d IDs", id, i)
+				}
+				seen[id] = true
+			}
+		})
+	}
+}
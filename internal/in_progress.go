@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// inProgressMarker flags tasks that have been started
+const inProgressMarker = "◐ "
+
+// cycleStatus moves the selected task from not started to in progress to
+// done and back. Google Tasks has no in-progress state, so it is kept in the
+// notes metadata and the task stays needsAction there.
+func (m *model) cycleStatus() tea.Cmd {
+	task := m.selectedTask()
+	if task == nil {
+		return nil
+	}
+	if task.Completed || task.InProgress {
+		if !task.Completed && m.isBlocked(*task) {
+			m.statusMsg = "This task is blocked by unfinished tasks"
+			return nil
+		}
+		completing := !task.Completed
+		m.toggleCompletion(task.Id)
+		if completing {
+			return completionBell()
+		}
+		return nil
+	}
+
+	if m.isBlocked(*task) {
+		m.statusMsg = "This task is blocked by unfinished tasks"
+		return nil
+	}
+	task.InProgress = true
+	task.Updated = time.Now()
+	m.syncToGoogle(*task)
+	m.saveTasks()
+	m.statusMsg = "Started " + task.Title
+	return nil
+}
@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// findTaskByID searches the whole tree for a task and returns the chain of
+// tasks from the top level down to it, the task itself last
+func findTaskByID(tasks []Task, id string) (path []Task, found bool) {
+	for _, task := range tasks {
+		if task.Deleted {
+			continue
+		}
+		if task.Id == id {
+			return []Task{task}, true
+		}
+		if rest, ok := findTaskByID(task.Tasks, id); ok {
+			return append([]Task{task}, rest...), true
+		}
+	}
+	return nil, false
+}
+
+// CompleteTask marks a task and, per the cascade settings, its subtasks
+// done. It returns every task that changed so callers can sync them.
+func CompleteTask(tasks []Task, id string) ([]Task, error) {
+	if _, found := findTaskByID(tasks, id); !found {
+		return nil, fmt.Errorf("%w: %s", ErrTaskNotFound, id)
+	}
+	task := findTask(tasks, id)
+	if task.Completed {
+		return nil, fmt.Errorf("task %s is already completed", id)
+	}
+
+	now := time.Now()
+	setCompleted(task, true, now)
+	changed := append([]Task{*task}, cascadeCompletion(task, now)...)
+	return append(changed, propagateCompletion(tasks, id, now)...), nil
+}
+
+// jumpTo moves the view to a task given its ID or, failing that, its
+// 1-based position in the current view. Unknown targets leave the view as is.
+func (m *model) jumpTo(target string) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return
+	}
+
+	path, found := findTaskByID(m.allTasks(), target)
+	if !found {
+		if n, err := strconv.Atoi(strings.TrimPrefix(target, "#")); err == nil && n >= 1 && n <= m.visibleCount() {
+			m.cursor = n - 1
+			return
+		}
+		m.statusMsg = "No task with ID or number " + target
+		return
+	}
+
+	task := path[len(path)-1]
+	m.currentPath = path[:len(path)-1]
+	if len(m.currentPath) > 0 {
+		m.currentListID = m.currentPath[0].Id
+	}
+	m.applyListSettings()
+	if task.Completed {
+		// Show completed tasks so the cursor can land on it
+		m.hideCompleted = false
+		m.expandedCompleted = true
+	}
+
+	if m.treeView {
+		for i, row := range m.treeRows() {
+			if row.task.Id == task.Id {
+				m.cursor = i
+				return
+			}
+		}
+	}
+	active, completed := m.getCurrentTasks()
+	if i := indexOfTask(active, task.Id); i >= 0 {
+		m.cursor = i
+	} else if i := indexOfTask(completed, task.Id); i >= 0 {
+		m.cursor = len(active) + i
+	}
+}
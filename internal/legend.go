@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Styles for task states, shared by the task list and the legend so the
+// legend always matches what is drawn
+var (
+	blockedStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	completedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	overdueStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
+	staleStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Italic(true)
+	inProgressStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("178"))
+)
+
+// legendItems explains each priority marker and task state style
+func legendItems() []string {
+	return []string{
+		priorityMarker(PriorityHigh) + "high",
+		priorityMarker(PriorityMedium) + "medium",
+		priorityMarker(PriorityLow) + "low",
+		overdueStyle.Render("overdue"),
+		blockedStyle.Render("🔒 blocked"),
+		inProgressStyle.Render(inProgressMarker + "in progress"),
+		completedStyle.Render("✓ completed"),
+		staleStyle.Render(staleMarker + "stale"),
+		"📌 pinned",
+	}
+}
+
+// renderLegend lays the legend out on one line, for the footer
+func renderLegend() string {
+	return strings.Join(legendItems(), "  ")
+}
+
+// renderLegendBlock lays the legend out in rows of three, for the help
+func renderLegendBlock() string {
+	items := legendItems()
+	var rows []string
+	for i := 0; i < len(items); i += 3 {
+		rows = append(rows, strings.Join(items[i:min(i+3, len(items))], "  "))
+	}
+	return strings.Join(rows, "\n") + "\n"
+}
@@ -0,0 +1,84 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TaskLink is a reference attached to a task, such as a URL or document
+type TaskLink struct {
+	Type string `json:"type"`
+	Desc string `json:"description"`
+	Link string `json:"link"`
+}
+
+// parseLinkInput reads "<url> [description]" from the input box
+func parseLinkInput(input string) (TaskLink, error) {
+	link, desc, _ := strings.Cut(strings.TrimSpace(input), " ")
+	if link == "" {
+		return TaskLink{}, fmt.Errorf("enter a link, optionally followed by a description")
+	}
+	return TaskLink{Type: "url", Desc: strings.TrimSpace(desc), Link: link}, nil
+}
+
+// addLink attaches a link to the selected task
+func (m *model) addLink(input string) {
+	task := m.selectedTask()
+	if task == nil {
+		return
+	}
+	link, err := parseLinkInput(input)
+	if err != nil {
+		m.statusMsg = err.Error()
+		return
+	}
+
+	task.Links = append(task.Links, link)
+	task.Updated = time.Now()
+	m.syncToGoogle(*task)
+	m.saveTasks()
+}
+
+// openLink opens the link with the given 1-based number on the selected task
+func (m *model) openLink(number string) {
+	task := m.selectedTask()
+	if task == nil {
+		return
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(number))
+	if err != nil || n < 1 || n > len(task.Links) {
+		m.statusMsg = fmt.Sprintf("Pick a link between 1 and %d", len(task.Links))
+		return
+	}
+
+	if attachmentMissing(task.Links[n-1]) {
+		m.statusMsg = "File is missing: " + task.Links[n-1].Link
+		return
+	}
+	if err := open(task.Links[n-1].Link); err != nil {
+		m.statusMsg = "Couldn't open link: " + err.Error()
+	}
+}
+
+// renderLinks lists a task's links and attached files, numbered for the
+// open prompt. Files that have gone missing are flagged.
+func renderLinks(links []TaskLink, wrapText func(string) string) string {
+	var s strings.Builder
+	s.WriteString("Links:\n")
+	for i, link := range links {
+		label := link.Link
+		if link.Desc != "" {
+			label = link.Desc + " (" + link.Link + ")"
+		}
+		if link.Type == attachmentType {
+			label = "📎 " + label
+		}
+		if attachmentMissing(link) {
+			label += " " + overdueStyle.Render("missing")
+		}
+		s.WriteString(fmt.Sprintf("  %d. %s\n", i+1, wrapText(label)))
+	}
+	return s.String()
+}
@@ -0,0 +1,231 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// listOrder is the local display order of top-level lists. The Tasks API has
+// no list ordering, so this layer is only applied when rendering.
+type listOrder struct {
+	Order  []string        `json:"order"`  // List IDs in display order
+	Pinned map[string]bool `json:"pinned"` // Lists kept above all others
+}
+
+var (
+	listOrderMu    sync.Mutex
+	savedListOrder listOrder
+)
+
+// loadListOrder reads the saved list order from the storage directory
+func loadListOrder() error {
+	listOrderMu.Lock()
+	defer listOrderMu.Unlock()
+
+	savedListOrder = listOrder{Pinned: make(map[string]bool)}
+	path, err := storageFile("list_order.json")
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("error reading list order: %v", err)
+	}
+	if err := json.Unmarshal(data, &savedListOrder); err != nil {
+		return fmt.Errorf("error parsing list order: %v", err)
+	}
+	if savedListOrder.Pinned == nil {
+		savedListOrder.Pinned = make(map[string]bool)
+	}
+	return nil
+}
+
+// saveListOrderLocked writes the list order; callers must hold listOrderMu
+func saveListOrderLocked() error {
+	path, err := storageFile("list_order.json")
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(savedListOrder, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling list order: %v", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// orderLists returns the lists in display order with pinned lists first. In
+// manual mode that is the saved order, with lists that have never been ordered
+// keeping their fetched order at the bottom; otherwise the sort mode decides.
+func orderLists(lists []Task, mode string) []Task {
+	listOrderMu.Lock()
+	defer listOrderMu.Unlock()
+
+	rank := make(map[string]int, len(savedListOrder.Order))
+	for i, id := range savedListOrder.Order {
+		rank[id] = i
+	}
+
+	ordered := sortTasks(lists, mode)
+	if mode == "" || mode == SortManual {
+		ordered = make([]Task, len(lists))
+		copy(ordered, lists)
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return rankOf(rank, ordered[i].Id) < rankOf(rank, ordered[j].Id)
+		})
+	}
+
+	result := make([]Task, 0, len(lists))
+	for _, list := range ordered {
+		if savedListOrder.Pinned[list.Id] {
+			result = append(result, list)
+		}
+	}
+	for _, list := range ordered {
+		if !savedListOrder.Pinned[list.Id] {
+			result = append(result, list)
+		}
+	}
+	return result
+}
+
+// sortFetchedLists puts freshly fetched lists in a stable order, as Google
+// returns them in no particular one. ListSort picks it: "alpha" sorts by
+// title, "custom" follows the titles in ListOrder with the rest after them
+// alphabetically, and "default" keeps Google's order.
+func sortFetchedLists(lists []Task) []Task {
+	config := GetGlobalConfig()
+	if config == nil {
+		return lists
+	}
+
+	byTitle := func(a, b Task) bool {
+		return strings.ToLower(a.Title) < strings.ToLower(b.Title)
+	}
+	switch config.ListSort {
+	case "alpha":
+		sort.SliceStable(lists, func(i, j int) bool {
+			return byTitle(lists[i], lists[j])
+		})
+	case "custom":
+		rank := make(map[string]int)
+		for _, title := range strings.Split(config.ListOrder, ",") {
+			if title = strings.ToLower(strings.TrimSpace(title)); title != "" {
+				if _, ok := rank[title]; !ok {
+					rank[title] = len(rank)
+				}
+			}
+		}
+		sort.SliceStable(lists, func(i, j int) bool {
+			ri, ji := rankOf(rank, strings.ToLower(lists[i].Title)), rankOf(rank, strings.ToLower(lists[j].Title))
+			if ri != ji {
+				return ri < ji
+			}
+			return ri == len(rank) && byTitle(lists[i], lists[j])
+		})
+	}
+	return lists
+}
+
+// rankOf returns a list's saved position, placing unknown lists last
+func rankOf(rank map[string]int, id string) int {
+	if i, ok := rank[id]; ok {
+		return i
+	}
+	return len(rank)
+}
+
+// isPinned reports whether a list is pinned to the top
+func isPinned(id string) bool {
+	listOrderMu.Lock()
+	defer listOrderMu.Unlock()
+	return savedListOrder.Pinned[id]
+}
+
+// togglePinned pins or unpins a list
+func togglePinned(id string) error {
+	listOrderMu.Lock()
+	defer listOrderMu.Unlock()
+
+	if savedListOrder.Pinned == nil {
+		savedListOrder.Pinned = make(map[string]bool)
+	}
+	if savedListOrder.Pinned[id] {
+		delete(savedListOrder.Pinned, id)
+	} else {
+		savedListOrder.Pinned[id] = true
+	}
+	return saveListOrderLocked()
+}
+
+// saveOrder stores lists, as currently displayed, as the new order
+func saveOrder(lists []Task) error {
+	listOrderMu.Lock()
+	defer listOrderMu.Unlock()
+
+	savedListOrder.Order = make([]string, len(lists))
+	for i, list := range lists {
+		savedListOrder.Order[i] = list.Id
+	}
+	return saveListOrderLocked()
+}
+
+// moveList swaps the top-level list under the cursor with its neighbour in
+// direction (-1 up, 1 down) and saves the new order
+func (m *model) moveList(direction int) {
+	if len(m.currentPath) > 0 {
+		return
+	}
+	if m.sortMode != SortManual {
+		m.statusMsg = "Switch to manual sort (S) to reorder lists"
+		return
+	}
+
+	lists, _ := m.getCurrentTasks()
+	target := m.cursor + direction
+	if m.cursor >= len(lists) || target < 0 || target >= len(lists) {
+		return
+	}
+	if isPinned(lists[m.cursor].Id) != isPinned(lists[target].Id) {
+		m.statusMsg = "Pinned lists stay above the others"
+		return
+	}
+
+	lists[m.cursor], lists[target] = lists[target], lists[m.cursor]
+	if err := saveOrder(lists); err != nil {
+		m.statusMsg = fmt.Sprintf("Error saving list order: %v", err)
+		return
+	}
+	m.cursor = target
+}
+
+// togglePinnedList pins or unpins the top-level list under the cursor
+func (m *model) togglePinnedList() {
+	if len(m.currentPath) > 0 {
+		m.statusMsg = "Only top-level lists can be pinned"
+		return
+	}
+	lists, _ := m.getCurrentTasks()
+	if m.cursor >= len(lists) {
+		return
+	}
+
+	id := lists[m.cursor].Id
+	if err := togglePinned(id); err != nil {
+		m.statusMsg = fmt.Sprintf("Error saving list order: %v", err)
+		return
+	}
+
+	// Keep the cursor on the list that just moved
+	lists, _ = m.getCurrentTasks()
+	if i := indexOfTask(lists, id); i >= 0 {
+		m.cursor = i
+	}
+}
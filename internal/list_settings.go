@@ -0,0 +1,241 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Sort modes for the tasks in a list
+const (
+	SortManual   = "manual"   // Keep the stored order
+	SortDue      = "due"      // Earliest due date first, undated last
+	SortPriority = "priority" // High priority first
+	SortTitle    = "title"    // Alphabetical
+)
+
+var sortModes = []string{SortManual, SortDue, SortPriority, SortTitle}
+
+// defaultAccent is the cursor color used when a list has no accent of its
+// own and AccentColor isn't set
+const defaultAccent = "86"
+
+// configuredAccent returns the AccentColor theme, or defaultAccent
+func configuredAccent() string {
+	if config := GetGlobalConfig(); config != nil && config.AccentColor != "" {
+		return config.AccentColor
+	}
+	return defaultAccent
+}
+
+// listSettings are the display preferences saved for one task list
+type listSettings struct {
+	Sort          string `json:"sort,omitempty"`
+	ShowCompleted *bool  `json:"showCompleted,omitempty"`
+	Accent        string `json:"accent,omitempty"`
+}
+
+var (
+	listSettingsMu   sync.Mutex
+	listSettingsByID map[string]listSettings // List ID -> saved preferences
+)
+
+// loadListSettings reads the per-list preferences from the storage directory
+func loadListSettings() error {
+	listSettingsMu.Lock()
+	defer listSettingsMu.Unlock()
+
+	listSettingsByID = make(map[string]listSettings)
+	path, err := storageFile("list_settings.json")
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("error reading list settings: %v", err)
+	}
+	if err := json.Unmarshal(data, &listSettingsByID); err != nil {
+		return fmt.Errorf("error parsing list settings: %v", err)
+	}
+	return nil
+}
+
+// updateListSettings changes the saved preferences of a list and writes them out
+func updateListSettings(listID string, change func(*listSettings)) error {
+	listSettingsMu.Lock()
+	defer listSettingsMu.Unlock()
+
+	if listSettingsByID == nil {
+		listSettingsByID = make(map[string]listSettings)
+	}
+	settings := listSettingsByID[listID]
+	change(&settings)
+	listSettingsByID[listID] = settings
+
+	path, err := storageFile("list_settings.json")
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(listSettingsByID, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling list settings: %v", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// settingsForList returns a list's preferences with global defaults filled in
+func settingsForList(listID string) listSettings {
+	listSettingsMu.Lock()
+	saved := listSettingsByID[listID]
+	listSettingsMu.Unlock()
+
+	showCompleted := true
+	if config := GetGlobalConfig(); config != nil {
+		showCompleted = config.ShowCompleted
+	}
+	settings := listSettings{Sort: SortManual, ShowCompleted: &showCompleted, Accent: configuredAccent()}
+
+	if saved.Sort != "" {
+		settings.Sort = saved.Sort
+	}
+	if saved.ShowCompleted != nil {
+		settings.ShowCompleted = saved.ShowCompleted
+	}
+	if saved.Accent != "" {
+		settings.Accent = saved.Accent
+	}
+	return settings
+}
+
+// currentList returns the ID of the top-level list being viewed, or "" at the top
+func (m *model) currentList() string {
+	if len(m.currentPath) == 0 {
+		return ""
+	}
+	return m.currentPath[0].Id
+}
+
+// applyListSettings loads the preferences of the list being viewed and
+// records it as recently used for the list switcher
+func (m *model) applyListSettings() {
+	if id := m.currentList(); id != "" {
+		touchRecentList(id)
+	}
+	settings := settingsForList(m.currentList())
+	m.sortMode = settings.Sort
+	m.hideCompleted = !*settings.ShowCompleted
+	m.accent = settings.Accent
+}
+
+// saveListSetting records a preference for the current list. At the top level
+// the change only lasts for the session.
+func (m *model) saveListSetting(change func(*listSettings)) {
+	listID := m.currentList()
+	if listID == "" {
+		return
+	}
+	if err := updateListSettings(listID, change); err != nil {
+		m.statusMsg = fmt.Sprintf("Error saving list settings: %v", err)
+	}
+}
+
+// accentColor is the cursor color of the current list
+func (m *model) accentColor() lipgloss.Color {
+	if m.accent == "" {
+		return lipgloss.Color(configuredAccent())
+	}
+	return lipgloss.Color(m.accent)
+}
+
+// setSortMode changes and saves how the current list is sorted
+func (m *model) setSortMode(mode string) {
+	m.sortMode = mode
+	m.saveListSetting(func(s *listSettings) { s.Sort = mode })
+	m.statusMsg = "Sorted by " + mode
+}
+
+// cycleSortMode switches the current list to the next sort mode
+func (m *model) cycleSortMode() {
+	next := sortModes[0]
+	for i, mode := range sortModes {
+		if mode == m.sortMode {
+			next = sortModes[(i+1)%len(sortModes)]
+		}
+	}
+	m.setSortMode(next)
+}
+
+// sortTasks returns the tasks ordered by mode, leaving the input untouched
+func sortTasks(tasks []Task, mode string) []Task {
+	if mode == "" || mode == SortManual {
+		return tasks
+	}
+
+	sorted := make([]Task, len(tasks))
+	copy(sorted, tasks)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		switch mode {
+		case SortDue:
+			if a.DueDate.IsZero() != b.DueDate.IsZero() {
+				return !a.DueDate.IsZero()
+			}
+			return a.DueDate.Before(b.DueDate)
+		case SortPriority:
+			return priorityRank(a.Priority) < priorityRank(b.Priority)
+		case SortTitle:
+			return strings.ToLower(a.Title) < strings.ToLower(b.Title)
+		}
+		return false
+	})
+	return sorted
+}
+
+// priorityRank orders priorities from most to least urgent
+func priorityRank(priority string) int {
+	switch priority {
+	case PriorityHigh:
+		return 0
+	case PriorityMedium:
+		return 1
+	case PriorityLow:
+		return 2
+	}
+	return 3
+}
+
+func init() {
+	registerCommand(paletteCommand{Name: "sort", Desc: "Sort the list: manual, due, priority or title", Run: sortCommand})
+	registerCommand(paletteCommand{Name: "accent", Desc: "Set the list's accent color", Run: accentCommand})
+}
+
+func sortCommand(m *model, args string) tea.Cmd {
+	for _, mode := range sortModes {
+		if mode == args {
+			m.setSortMode(mode)
+			return nil
+		}
+	}
+	m.statusMsg = "Sort modes: " + strings.Join(sortModes, ", ")
+	return nil
+}
+
+func accentCommand(m *model, args string) tea.Cmd {
+	if args == "" {
+		m.statusMsg = "Usage: accent <color>, e.g. accent 212 or accent #ff8800"
+		return nil
+	}
+	m.accent = args
+	m.saveListSetting(func(s *listSettings) { s.Accent = args })
+	return nil
+}
@@ -0,0 +1,212 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxRecentLists is how many recently used lists the switcher remembers
+const maxRecentLists = 10
+
+var (
+	recentListsMu sync.Mutex
+	recentLists   []string // List IDs, most recently used first
+)
+
+// loadRecentLists reads the recently used lists from the storage directory
+func loadRecentLists() error {
+	recentListsMu.Lock()
+	defer recentListsMu.Unlock()
+
+	recentLists = nil
+	path, err := storageFile("recent_lists.json")
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("error reading recent lists: %v", err)
+	}
+	if err := json.Unmarshal(data, &recentLists); err != nil {
+		return fmt.Errorf("error parsing recent lists: %v", err)
+	}
+	return nil
+}
+
+// touchRecentList moves a list to the front of the recently used lists
+func touchRecentList(id string) {
+	recentListsMu.Lock()
+	defer recentListsMu.Unlock()
+
+	if len(recentLists) > 0 && recentLists[0] == id {
+		return
+	}
+	recent := []string{id}
+	for _, other := range recentLists {
+		if other != id && len(recent) < maxRecentLists {
+			recent = append(recent, other)
+		}
+	}
+	recentLists = recent
+
+	path, err := storageFile("recent_lists.json")
+	if err == nil {
+		var data []byte
+		if data, err = json.Marshal(recentLists); err == nil {
+			err = os.WriteFile(path, data, 0644)
+		}
+	}
+	if err != nil {
+		logError("Error saving recent lists: %v", err)
+	}
+}
+
+// recentRank returns how recently a list was used, 0 being the latest, or
+// -1 if it isn't among the recent lists
+func recentRank(id string) int {
+	recentListsMu.Lock()
+	defer recentListsMu.Unlock()
+	for i, other := range recentLists {
+		if other == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// matchLists returns the lists matching query, best first. Without a query
+// recently used lists come first in the order they were used; with one,
+// recent use breaks ties between equally good matches.
+func matchLists(lists []Task, query string) []Task {
+	type scored struct {
+		list  Task
+		score int
+		rank  int
+	}
+
+	var matches []scored
+	for _, list := range lists {
+		if list.Deleted {
+			continue
+		}
+		score, ok := fuzzyScore(list.Title, query)
+		if !ok {
+			continue
+		}
+		rank := recentRank(list.Id)
+		if rank < 0 {
+			rank = maxRecentLists
+		}
+		matches = append(matches, scored{list, score, rank})
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if query != "" && matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].rank < matches[j].rank
+	})
+
+	found := make([]Task, len(matches))
+	for i, match := range matches {
+		found[i] = match.list
+	}
+	return found
+}
+
+// listHint tells lists with the same name apart by their size and the end
+// of their ID, or is empty when the name is unique
+func listHint(list Task, lists []Task) string {
+	for _, other := range lists {
+		if other.Id != list.Id && !other.Deleted && strings.EqualFold(other.Title, list.Title) {
+			active, _ := splitTasks(list.Tasks)
+			id := list.Id
+			if len(id) > 6 {
+				id = "…" + id[len(id)-6:]
+			}
+			return fmt.Sprintf("%d open, %s", len(active), id)
+		}
+	}
+	return ""
+}
+
+// openListSwitcher starts the fuzzy list switcher
+func (m *model) openListSwitcher() {
+	m.inputActive = true
+	m.inputAction = "switch_list"
+	m.input.Placeholder = "Type to filter lists..."
+	m.input.SetValue("")
+	m.input.Focus()
+	m.switcherCursor = 0
+}
+
+// switcherKey moves the selection of the list switcher, reporting whether it
+// used the key. Any other key edits the filter, so the selection restarts.
+func (m *model) switcherKey(key string) bool {
+	switch key {
+	case "up", "ctrl+k":
+		if m.switcherCursor > 0 {
+			m.switcherCursor--
+		}
+		return true
+	case "down", "ctrl+j":
+		if m.switcherCursor < len(matchLists(m.tasks, m.input.Value()))-1 {
+			m.switcherCursor++
+		}
+		return true
+	case "enter", "esc":
+		return false
+	}
+	m.switcherCursor = 0
+	return false
+}
+
+// switchToSelectedList opens the list picked in the switcher
+func (m *model) switchToSelectedList() {
+	matches := matchLists(m.tasks, m.input.Value())
+	if m.switcherCursor >= len(matches) {
+		m.statusMsg = "No list matches " + m.input.Value()
+		return
+	}
+	list := matches[m.switcherCursor]
+	m.currentPath = []Task{list}
+	m.currentListID = list.Id
+	m.cursor = 0
+	m.applyListSettings()
+}
+
+// renderListSwitcher shows the matching lists with the selection marked
+func (m *model) renderListSwitcher(limit int) string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	matches := matchLists(m.tasks, m.input.Value())
+
+	// Scroll so the selection stays in view
+	start := max(m.switcherCursor-limit+1, 0)
+	var s strings.Builder
+	for i := start; i < len(matches) && i < start+limit; i++ {
+		prefix := "  "
+		if i == m.switcherCursor {
+			prefix = "> "
+		}
+		line := prefix + matches[i].Title
+		if hint := listHint(matches[i], m.tasks); hint != "" {
+			line += " " + dim.Render("("+hint+")")
+		}
+		if m.input.Value() == "" && recentRank(matches[i].Id) >= 0 {
+			line += " " + dim.Render("recent")
+		}
+		s.WriteString(line + "\n")
+	}
+	if len(matches) == 0 {
+		s.WriteString(dim.Render("  No matching lists") + "\n")
+	}
+	return s.String()
+}
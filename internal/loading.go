@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// googleFetchDoneMsg signals a foreground Google fetch has finished
+type googleFetchDoneMsg struct {
+	tasks  []Task // The fetched tree, nil if the fetch failed
+	listID string // First task list, used as the default for new tasks
+	err    error
+}
+
+// newLoadingSpinner builds the spinner shown while a fetch is in flight
+func newLoadingSpinner() spinner.Model {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	return s
+}
+
+// fetchGoogleCmd fetches the task tree in the background and hands it to
+// Update along with the news that the spinner can stop. It runs on startup
+// and for manual syncs.
+func (m model) fetchGoogleCmd() tea.Msg {
+	tasks, _, err := refreshGoogleCache()
+	if err != nil {
+		return googleFetchDoneMsg{err: err}
+	}
+
+	listID, err := m.googleTasks.firstListID()
+	return googleFetchDoneMsg{tasks: tasks, listID: listID, err: err}
+}
+
+// manualSync starts an immediate fetch unless one is already running
+func (m *model) manualSync() tea.Cmd {
+	if m.googleTasks == nil {
+		m.statusMsg = "Nothing to sync in local mode"
+		return nil
+	}
+	if m.loading {
+		return nil
+	}
+	m.loading = true
+	return tea.Batch(m.spinner.Tick, m.fetchGoogleCmd)
+}
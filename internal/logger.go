@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// logLevel orders how much a log message matters
+type logLevel int
+
+const (
+	levelDebug logLevel = iota
+	levelInfo
+	levelError
+)
+
+func (l logLevel) String() string {
+	switch l {
+	case levelDebug:
+		return "DEBUG"
+	case levelInfo:
+		return "INFO"
+	}
+	return "ERROR"
+}
+
+// DebugLogging enables debug messages, set by the --debug flag
+var DebugLogging bool
+
+// logDebug records a message only useful for tracking down problems. It is
+// dropped unless --debug was given.
+func logDebug(format string, args ...any) {
+	if DebugLogging {
+		writeLog(levelDebug, fmt.Sprintf(format, args...))
+	}
+}
+
+// logInfo records a routine event
+func logInfo(format string, args ...any) {
+	writeLog(levelInfo, fmt.Sprintf(format, args...))
+}
+
+// logError records an error and keeps it in the error log shown with E
+func logError(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	addErrorLogEntry(logEntry{Time: time.Now(), Msg: msg})
+	writeLog(levelError, msg)
+}
+
+// writeLog appends a message to godo.log when ErrorLogFile is set (debug
+// messages always, since asking for them is asking for the file). While the
+// UI runs nothing is printed, as that would corrupt the screen; outside it
+// the message goes to stderr, keeping stdout for command output like export.
+func writeLog(level logLevel, msg string) {
+	if uiProgram.Load() == nil {
+		fmt.Fprintln(os.Stderr, msg)
+	}
+	if config := GetGlobalConfig(); config != nil && (config.ErrorLogFile || level == levelDebug) {
+		appendLogFile(level, msg)
+	}
+}
+
+// appendLogFile writes a line to godo.log in the storage directory. It
+// can't report its own failure anywhere useful, so it doesn't try.
+func appendLogFile(level logLevel, msg string) {
+	path, err := storageFile("godo.log")
+	if err != nil {
+		return
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintf(f, "%s %-5s %s\n", time.Now().Format(time.RFC3339), level, msg)
+}
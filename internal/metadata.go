@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// notesMetadataPrefix marks the line in Google Tasks notes that carries
+// godo-only fields, since the Tasks API has nowhere else to keep them
+const notesMetadataPrefix = "godo-meta: "
+
+// taskMetadata holds the Task fields that are round-tripped through notes
+type taskMetadata struct {
+	Priority     string        `json:"priority,omitempty"`
+	Tags         []string      `json:"tags,omitempty"`
+	BlockedBy    []string      `json:"blockedBy,omitempty"`
+	TimeSpent    time.Duration `json:"timeSpent,omitempty"`
+	TimerStarted *time.Time    `json:"timerStarted,omitempty"`
+	Links        []TaskLink    `json:"links,omitempty"`
+	Color        string        `json:"color,omitempty"`
+	Estimate     time.Duration `json:"estimate,omitempty"`
+	Pinned       bool          `json:"pinned,omitempty"`
+	AutoDone     bool          `json:"autoCompleted,omitempty"`
+	InProgress   bool          `json:"inProgress,omitempty"`
+}
+
+// metadataFromTask collects the fields of a task that need encoding
+func metadataFromTask(task Task) taskMetadata {
+	meta := taskMetadata{
+		Priority:   task.Priority,
+		Tags:       task.Tags,
+		BlockedBy:  task.BlockedBy,
+		TimeSpent:  task.TimeSpent,
+		Links:      task.Links,
+		Color:      task.Color,
+		Estimate:   task.Estimate,
+		Pinned:     task.Pinned,
+		AutoDone:   task.AutoCompleted,
+		InProgress: task.InProgress,
+	}
+	if !task.TimerStarted.IsZero() {
+		meta.TimerStarted = &task.TimerStarted
+	}
+	return meta
+}
+
+// apply copies decoded metadata fields onto the task
+func (meta taskMetadata) apply(task *Task) {
+	task.Priority = meta.Priority
+	task.Tags = meta.Tags
+	task.BlockedBy = meta.BlockedBy
+	task.TimeSpent = meta.TimeSpent
+	task.Links = meta.Links
+	task.Color = meta.Color
+	task.Estimate = meta.Estimate
+	task.Pinned = meta.Pinned
+	task.AutoCompleted = meta.AutoDone
+	task.InProgress = meta.InProgress
+	if meta.TimerStarted != nil {
+		task.TimerStarted = *meta.TimerStarted
+	}
+}
+
+// encodeNotes returns the task notes with a trailing metadata line appended
+func encodeNotes(task Task) string {
+	notes := stripNotesMetadata(task.Notes)
+	data, err := json.Marshal(metadataFromTask(task))
+	if err != nil || string(data) == "{}" {
+		return notes
+	}
+	if notes == "" {
+		return notesMetadataPrefix + string(data)
+	}
+	return notes + "\n\n" + notesMetadataPrefix + string(data)
+}
+
+// decodeNotes strips the metadata line from the task notes and applies it
+func decodeNotes(task *Task) {
+	idx := strings.LastIndex(task.Notes, notesMetadataPrefix)
+	if idx < 0 || (idx > 0 && task.Notes[idx-1] != '\n') {
+		return
+	}
+
+	var meta taskMetadata
+	if err := json.Unmarshal([]byte(task.Notes[idx+len(notesMetadataPrefix):]), &meta); err != nil {
+		return
+	}
+	meta.apply(task)
+	task.Notes = strings.TrimRight(task.Notes[:idx], "\n")
+}
+
+// stripNotesMetadata removes an existing metadata line so it isn't duplicated
+func stripNotesMetadata(notes string) string {
+	task := Task{Notes: notes}
+	decodeNotes(&task)
+	return task.Notes
+}
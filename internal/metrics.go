@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// apiMetrics counts what godo asked of Google during this process, to help
+// track down rate limiting. Everything is atomic so counting stays cheap.
+var apiMetrics struct {
+	calls        sync.Map // Operation name to *atomic.Int64
+	retries      atomic.Int64
+	failures     atomic.Int64
+	syncs        atomic.Int64
+	syncsChanged atomic.Int64
+	lastSync     atomic.Int64 // Duration of the last sync in nanoseconds
+	lastSyncAt   atomic.Int64 // Unix nanoseconds
+	cacheHits    atomic.Int64
+	cacheMisses  atomic.Int64
+}
+
+// recordAPICall counts one request to the Tasks API
+func recordAPICall(op string) {
+	counter, _ := apiMetrics.calls.LoadOrStore(op, new(atomic.Int64))
+	counter.(*atomic.Int64).Add(1)
+}
+
+// recordSync notes how long a full fetch took and whether it found changes
+func recordSync(took time.Duration, changed bool) {
+	apiMetrics.syncs.Add(1)
+	if changed {
+		apiMetrics.syncsChanged.Add(1)
+	}
+	apiMetrics.lastSync.Store(int64(took))
+	apiMetrics.lastSyncAt.Store(time.Now().UnixNano())
+}
+
+// recordCacheLookup counts whether cached tasks were there to start from
+func recordCacheLookup(hit bool) {
+	if hit {
+		apiMetrics.cacheHits.Add(1)
+	} else {
+		apiMetrics.cacheMisses.Add(1)
+	}
+}
+
+// renderAPIStats formats the session's API counters
+func renderAPIStats() string {
+	type opCount struct {
+		op    string
+		count int64
+	}
+	var ops []opCount
+	var total int64
+	apiMetrics.calls.Range(func(key, value any) bool {
+		count := value.(*atomic.Int64).Load()
+		ops = append(ops, opCount{key.(string), count})
+		total += count
+		return true
+	})
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].count > ops[j].count || (ops[i].count == ops[j].count && ops[i].op < ops[j].op)
+	})
+
+	var s strings.Builder
+	s.WriteString(fmt.Sprintf("Google API calls this session: %d (%d retries, %d failed)\n",
+		total, apiMetrics.retries.Load(), apiMetrics.failures.Load()))
+	for _, op := range ops {
+		s.WriteString(fmt.Sprintf("  %-18s %d\n", op.op, op.count))
+	}
+
+	syncs := apiMetrics.syncs.Load()
+	s.WriteString(fmt.Sprintf("Syncs: %d (%d found changes)", syncs, apiMetrics.syncsChanged.Load()))
+	if syncs > 0 {
+		at := time.Unix(0, apiMetrics.lastSyncAt.Load())
+		s.WriteString(fmt.Sprintf(", last took %s at %s", time.Duration(apiMetrics.lastSync.Load()).Round(time.Millisecond), at.Format("15:04:05")))
+	}
+	s.WriteString("\n")
+	s.WriteString(fmt.Sprintf("Cache: %d hit(s), %d miss(es)\n", apiMetrics.cacheHits.Load(), apiMetrics.cacheMisses.Load()))
+	return s.String()
+}
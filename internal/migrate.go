@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// storageVersion is the version of the tasks file format godo writes
+const storageVersion = 3
+
+// taskFile is the envelope tasks are stored in from version 2 on
+type taskFile struct {
+	Version int    `json:"version"`
+	Tasks   []Task `json:"tasks"`
+}
+
+// migrations upgrade tasks from the keyed version to the next one. Add an
+// entry here, and bump storageVersion, whenever the format changes.
+var migrations = map[int]func([]Task) []Task{
+	// Version 1 was a bare array of tasks; the envelope is the only change
+	1: func(tasks []Task) []Task { return tasks },
+	// Version 3 added Order; the slice order up to then was the only order
+	2: numberOrder,
+}
+
+// decodeTaskFile reads a tasks file of any known version, migrates it to the
+// current one and fills in defaults for fields older versions didn't have
+func decodeTaskFile(data []byte) ([]Task, error) {
+	var file taskFile
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		file.Version = 1
+		if err := json.Unmarshal(trimmed, &file.Tasks); err != nil {
+			return nil, err
+		}
+	} else if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	} else if file.Version == 0 {
+		// The envelope came in with version 2, so one without a version is that
+		file.Version = 2
+	}
+
+	if file.Version > storageVersion {
+		return nil, fmt.Errorf("tasks file is version %d, but this godo only understands up to version %d", file.Version, storageVersion)
+	}
+	for version := file.Version; version < storageVersion; version++ {
+		migrate, ok := migrations[version]
+		if !ok {
+			return nil, fmt.Errorf("no migration from tasks file version %d", version)
+		}
+		file.Tasks = migrate(file.Tasks)
+	}
+
+	return backfillDefaults(sortByOrder(file.Tasks), time.Now()), nil
+}
+
+// encodeTaskFile writes tasks in the current file format
+func encodeTaskFile(tasks []Task) ([]byte, error) {
+	return json.MarshalIndent(taskFile{Version: storageVersion, Tasks: numberOrder(tasks)}, "", "  ")
+}
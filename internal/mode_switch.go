@@ -0,0 +1,108 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// modeSwitchedMsg carries the tasks of the storage mode just switched to
+type modeSwitchedMsg struct {
+	google bool
+	tasks  []Task
+	err    error
+
+	accountSwitch   bool   // Switching Google accounts rather than modes
+	previousAccount string // The account to go back to if connecting failed
+}
+
+// switchMode starts switching between local storage and Google Tasks.
+// Pending edits are saved first so nothing is lost in the switch.
+func (m *model) switchMode() tea.Cmd {
+	if UseCalDAV {
+		// Saves would keep going to the server, with another mode's tree
+		m.statusMsg = "Can't switch modes while using CalDAV, restart without --caldav"
+		return nil
+	}
+	m.autosave()
+
+	if UseGoogleTasks.Load() {
+		tasks, err := NewLocalStore().List()
+		if err != nil {
+			m.statusMsg = fmt.Sprintf("Couldn't load local tasks: %v", err)
+			return nil
+		}
+		stopped := stopBackgroundSync()
+		UseGoogleTasks.Store(false)
+		return func() tea.Msg {
+			// Google tasks from a refresh still running arrive before these
+			<-stopped
+			return modeSwitchedMsg{tasks: tasks}
+		}
+	}
+
+	m.statusMsg = "Connecting to Google Tasks..."
+	return connectGoogle
+}
+
+// connectGoogle sets up the Google client. Signing in for the first time
+// prompts in the terminal and opens a browser, so the UI steps aside for it.
+func connectGoogle() tea.Msg {
+	if p := uiProgram.Load(); p != nil && googleNeedsSignIn() {
+		p.ReleaseTerminal()
+		defer p.RestoreTerminal()
+	}
+
+	if err := InitializeGoogleTasks(); err != nil {
+		return modeSwitchedMsg{google: true, err: err}
+	}
+	return modeSwitchedMsg{google: true, tasks: CachedTasks()}
+}
+
+// googleNeedsSignIn reports whether connecting will ask for credentials or
+// run the OAuth flow
+func googleNeedsSignIn() bool {
+	config := GetGlobalConfig()
+	if config == nil || config.GoogleClientID == "" || config.GoogleClientSecret == "" {
+		return true
+	}
+	_, err := loadToken()
+	return os.IsNotExist(err)
+}
+
+// finishModeSwitch shows the tasks of the new mode, starting from the top
+func (m *model) finishModeSwitch(msg modeSwitchedMsg) tea.Cmd {
+	if msg.err != nil {
+		if msg.accountSwitch {
+			// Stay on the account that still works
+			setActiveAccount(msg.previousAccount)
+			if UseGoogleTasks.Load() {
+				startBackgroundSync()
+			}
+		}
+		m.statusMsg = fmt.Sprintf("Couldn't connect to Google Tasks: %v", msg.err)
+		return nil
+	}
+
+	m.tasks, m.completedTasks = splitTasks(msg.tasks)
+	m.currentPath = nil
+	m.currentListID = ""
+	m.cursor = 0
+	m.dirty = false
+	m.applyListSettings()
+
+	if !msg.google {
+		m.googleTasks = nil
+		m.loading = false
+		ensureLocalWatcher()
+		m.statusMsg = "Switched to local storage"
+		return nil
+	}
+
+	UseGoogleTasks.Store(true)
+	m.googleTasks = GoogleTasksClientVar
+	m.statusMsg = "Switched to Google Tasks" + accountLabel(ActiveAccount())
+	m.loading = true
+	return tea.Batch(m.spinner.Tick, m.fetchGoogleCmd)
+}
@@ -0,0 +1,177 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// moveTargets returns the task lists the selected task can be moved to
+func (m *model) moveTargets() []Task {
+	var lists []Task
+	for _, list := range m.allTasks() {
+		if list.Kind != "tasks#taskList" || list.Deleted {
+			continue
+		}
+		if len(m.currentPath) > 0 && list.Id == m.currentPath[0].Id {
+			continue
+		}
+		lists = append(lists, list)
+	}
+	return lists
+}
+
+// renderMoveTargets numbers the lists for the move prompt
+func (m *model) renderMoveTargets() string {
+	var s strings.Builder
+	for i, list := range m.moveTargets() {
+		s.WriteString(fmt.Sprintf("  %d. %s\n", i+1, list.Title))
+	}
+	return s.String()
+}
+
+// resolveMoveTarget picks a destination list by its number in the prompt
+// or by fuzzy matching its title
+func (m *model) resolveMoveTarget(input string) (Task, bool) {
+	input = strings.TrimSpace(input)
+	lists := m.moveTargets()
+	if n, err := strconv.Atoi(input); err == nil {
+		if n >= 1 && n <= len(lists) {
+			return lists[n-1], true
+		}
+		return Task{}, false
+	}
+
+	best, bestScore := -1, 0
+	for i, list := range lists {
+		if score, ok := fuzzyScore(list.Title, input); ok && (best < 0 || score > bestScore) {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		return Task{}, false
+	}
+	return lists[best], true
+}
+
+// taskMovedMsg reports how a move between Google lists, made in the
+// background, went
+type taskMovedMsg struct {
+	id        string // The task moved
+	listID    string // The destination list
+	created   Task   // The copy in the destination, with its new IDs
+	err       error  // Why the copy failed, leaving everything as it was
+	deleteErr error  // Why the original couldn't be removed after copying
+}
+
+// moveTask moves a task and its subtasks to the top level of another list.
+// Google Tasks can't move between lists, so the tree is recreated in the
+// destination first and the original only deleted once that succeeded.
+// That happens in the background and finishMove applies the outcome.
+func (m *model) moveTask(id, listID string) {
+	task := m.findTask(id)
+	dest := m.findTask(listID)
+	if task == nil || dest == nil || len(m.currentPath) == 0 {
+		return
+	}
+	moved := *task
+	moved.Parent = ""
+
+	if m.googleTasks == nil {
+		m.placeMoved(id, listID, moved)
+		return
+	}
+
+	client, fromListID := m.googleTasks, m.currentPath[0].Id
+	moved = cloneTasks([]Task{moved})[0]
+	m.statusMsg = "Moving to " + dest.Title + "..."
+	goSync(func() {
+		msg := taskMovedMsg{id: id, listID: listID}
+		msg.created, msg.err = recreateTree(client, listID, "", moved)
+		if msg.err == nil {
+			msg.deleteErr = client.deleteTaskIn(fromListID, id)
+		}
+		sendToUI(msg)
+	})
+}
+
+// finishMove applies a move once Google has the copy
+func (m *model) finishMove(msg taskMovedMsg) {
+	if msg.err != nil {
+		m.statusMsg = fmt.Sprintf("Move failed, nothing was changed: %v", msg.err)
+		return
+	}
+	if msg.deleteErr != nil {
+		// Better a duplicate than a lost task
+		if dest := m.findTask(msg.listID); dest != nil {
+			dest.Tasks = append(dest.Tasks, msg.created)
+			m.statusMsg = fmt.Sprintf("Copied to %s, but the original couldn't be removed: %v", dest.Title, msg.deleteErr)
+		}
+		m.saveMove()
+		return
+	}
+	m.placeMoved(msg.id, msg.listID, msg.created)
+}
+
+// placeMoved takes a task out of the tree and adds moved, its copy, to the
+// top level of the destination list
+func (m *model) placeMoved(id, listID string, moved Task) {
+	m.tasks = removeTaskByID(m.tasks, id)
+	m.completedTasks = removeTaskByID(m.completedTasks, id)
+	// Removing shifts the slices, so look the destination up afterwards
+	if dest := m.findTask(listID); dest != nil {
+		dest.Tasks = append(dest.Tasks, moved)
+		m.statusMsg = "Moved to " + dest.Title
+	}
+	m.saveMove()
+}
+
+// recreateTree creates a task and its subtasks in a Google list, pointing
+// each subtask at its new parent and keeping siblings in order. On failure
+// everything it created so far is deleted again, so the destination is left
+// as it was.
+func recreateTree(client *GoogleTasksClient, listID, parentID string, task Task) (Task, error) {
+	var created []string
+	var create func(parentID, previousID string, task Task) (Task, error)
+	create = func(parentID, previousID string, task Task) (Task, error) {
+		task.Parent = parentID
+		children := task.Tasks
+		newTask, err := client.createTaskAfter(task, listID, previousID)
+		if err != nil {
+			return Task{}, err
+		}
+		created = append(created, newTask.Id)
+
+		newTask.Tasks = make([]Task, 0, len(children))
+		previousID = ""
+		for _, child := range children {
+			if child.Deleted {
+				continue
+			}
+			newChild, err := create(newTask.Id, previousID, child)
+			if err != nil {
+				return Task{}, err
+			}
+			newTask.Tasks = append(newTask.Tasks, newChild)
+			previousID = newChild.Id
+		}
+		return newTask, nil
+	}
+
+	newTask, err := create(parentID, "", task)
+	if err != nil && len(created) > 0 {
+		// Deleting the top task takes its subtasks with it
+		if rollbackErr := client.deleteTaskIn(listID, created[0]); rollbackErr != nil {
+			return Task{}, fmt.Errorf("%v (and cleaning up the partial copy failed: %v)", err, rollbackErr)
+		}
+	}
+	return newTask, err
+}
+
+// saveMove keeps the cursor in range and saves after a move
+func (m *model) saveMove() {
+	if count := m.visibleCount(); m.cursor >= count {
+		m.cursor = max(count-1, 0)
+	}
+	m.saveTasks()
+}
@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// nextAction is the most important open task of one list
+type nextAction struct {
+	List Task
+	Task Task
+}
+
+// morePressing reports whether a should be done before b: higher priority
+// first, then the earlier due date, with undated tasks last
+func morePressing(a, b Task) bool {
+	if ra, rb := priorityRank(a.Priority), priorityRank(b.Priority); ra != rb {
+		return ra < rb
+	}
+	if a.DueDate.IsZero() != b.DueDate.IsZero() {
+		return !a.DueDate.IsZero()
+	}
+	return a.DueDate.Before(b.DueDate)
+}
+
+// nextActions picks the most pressing unfinished, unblocked task at any depth
+// of each top-level list. Lists with nothing to do are left out. Every
+// top-level task counts as a list, as local mode doesn't mark them.
+func nextActions(lists []Task, blocked func(Task) bool) []nextAction {
+	var actions []nextAction
+	for _, list := range lists {
+		if list.Deleted {
+			continue
+		}
+
+		var best *Task
+		var walk func(tasks []Task)
+		walk = func(tasks []Task) {
+			for i := range tasks {
+				task := &tasks[i]
+				if task.Deleted || task.Completed {
+					continue
+				}
+				if !blocked(*task) && (best == nil || morePressing(*task, *best)) {
+					best = task
+				}
+				walk(task.Tasks)
+			}
+		}
+		walk(list.Tasks)
+
+		if best != nil {
+			actions = append(actions, nextAction{List: list, Task: *best})
+		}
+	}
+	return actions
+}
+
+// openNextActions shows the next action of every list
+func (m *model) openNextActions() {
+	m.showNext = true
+	m.nextCursor = 0
+}
+
+// nextActionsKey handles keys while the next actions view is open
+func (m *model) nextActionsKey(key string) {
+	actions := nextActions(m.allTasks(), m.isBlocked)
+	switch key {
+	case "A", "esc":
+		m.showNext = false
+	case "down", "j":
+		if m.nextCursor < len(actions)-1 {
+			m.nextCursor++
+		}
+	case "up", "k":
+		if m.nextCursor > 0 {
+			m.nextCursor--
+		}
+	case "enter":
+		if m.nextCursor < len(actions) {
+			m.showNext = false
+			m.jumpTo(actions[m.nextCursor].Task.Id)
+		}
+	}
+}
+
+// renderNextActions lists each list's next action with its due date
+func (m *model) renderNextActions() string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	actions := nextActions(m.allTasks(), m.isBlocked)
+	now, wt := time.Now(), currentWorkingTime()
+
+	var s strings.Builder
+	s.WriteString(lipgloss.NewStyle().Bold(true).Render("Next actions") + "\n\n")
+	if len(actions) == 0 {
+		s.WriteString(dim.Render("  Nothing left to do") + "\n")
+	}
+	for i, action := range actions {
+		cursor := " "
+		if i == m.nextCursor {
+			cursor = ">"
+		}
+		line := fmt.Sprintf("%s %s %s%s", cursor, dim.Render(action.List.Title+":"), priorityMarker(action.Task.Priority), action.Task.Title)
+		if !action.Task.DueDate.IsZero() {
+			due := "due " + formatDate(action.Task.DueDate)
+			if isOverdue(action.Task, now, wt) {
+				due = overdueStyle.Render(due)
+			} else {
+				due = dim.Render(due)
+			}
+			line += "  " + due
+		}
+		s.WriteString(line + "\n")
+	}
+
+	s.WriteString("\n" + dim.Render("j/k: Select  Enter: Go to task  A/esc: Close"))
+	return s.String()
+}
@@ -0,0 +1,122 @@
+package internal
+
+import (
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// overdueCheckInterval is how often the UI looks for newly overdue tasks
+const overdueCheckInterval = time.Minute
+
+// overdueCheckMsg fires on every overdue check tick
+type overdueCheckMsg time.Time
+
+// completeTaskMsg asks the UI to complete a task, e.g. from a notification
+type completeTaskMsg struct {
+	id string
+}
+
+func overdueTick() tea.Cmd {
+	return tea.Tick(overdueCheckInterval, func(t time.Time) tea.Msg {
+		return overdueCheckMsg(t)
+	})
+}
+
+// overdueNotificationsEnabled reports whether OverdueNotifications is on
+func overdueNotificationsEnabled() bool {
+	config := GetGlobalConfig()
+	return config != nil && config.OverdueNotifications
+}
+
+// notifyOverdue returns a command per task that became overdue since the
+// last check. Each task is only announced once per session, and outside
+// working time announcements wait until work starts again.
+func (m *model) notifyOverdue(now time.Time) tea.Cmd {
+	wt := currentWorkingTime()
+	if !wt.isWorkingTime(now) {
+		return nil
+	}
+	var cmds []tea.Cmd
+	var walk func(tasks []Task)
+	walk = func(tasks []Task) {
+		for _, task := range tasks {
+			if task.Deleted {
+				continue
+			}
+			if task.Kind != "tasks#taskList" && isOverdue(task, now, wt) && !m.notified[task.Id] {
+				m.notified[task.Id] = true
+				cmds = append(cmds, overdueNotification(task))
+			}
+			walk(task.Tasks)
+		}
+	}
+	walk(m.allTasks())
+	return tea.Batch(cmds...)
+}
+
+// overdueNotification shows a desktop notification for the task. Where the
+// notifier supports actions it offers a Done button and waits for the
+// answer, completing the task when it is clicked; elsewhere the
+// notification is informational only.
+func overdueNotification(task Task) tea.Cmd {
+	return func() tea.Msg {
+		if clicked, ok := sendActionNotification("Task overdue", task.Title); ok {
+			if clicked {
+				return completeTaskMsg{id: task.Id}
+			}
+			return nil
+		}
+		sendPlainNotification("Task overdue", task.Title)
+		return nil
+	}
+}
+
+// sendActionNotification shows a notification with a Done action and blocks
+// until it is dismissed. ok is false if no notifier with actions is available.
+func sendActionNotification(title, body string) (clicked, ok bool) {
+	var cmd *exec.Cmd
+	var doneAnswer string
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd":
+		cmd = exec.Command("notify-send", "--app-name=godo", "--action=done=Done", "--wait", title, body)
+		doneAnswer = "done"
+	case "darwin":
+		// alerter prints the chosen action, terminal-notifier has no actions anymore
+		if _, err := exec.LookPath("alerter"); err != nil {
+			return false, false
+		}
+		cmd = exec.Command("alerter", "-title", title, "-message", body, "-actions", "Done")
+		doneAnswer = "Done"
+	default:
+		return false, false
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		// Older notify-send rejects --action, so fall back to a plain notification
+		return false, false
+	}
+	return strings.TrimSpace(string(out)) == doneAnswer, true
+}
+
+// sendPlainNotification shows a notification without actions, ignoring errors
+func sendPlainNotification(title, body string) {
+	switch runtime.GOOS {
+	case "darwin":
+		script := "display notification " + appleScriptString(body) + " with title " + appleScriptString(title)
+		exec.Command("osascript", "-e", script).Run()
+	case "windows":
+		return
+	default:
+		exec.Command("notify-send", "--app-name=godo", title, body).Run()
+	}
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
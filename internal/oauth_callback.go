@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// oauthCallbackPort is the port the OAuth redirect comes back to, from
+// OAuthCallbackPort. Zero picks a free port for each sign-in, which only
+// works with "Desktop app" clients, as Google lets those redirect to any
+// port on localhost; "Web application" clients need the registered one.
+func oauthCallbackPort() int {
+	config := GetGlobalConfig()
+	if config == nil || config.OAuthCallbackPort < 0 {
+		return 8080
+	}
+	return config.OAuthCallbackPort
+}
+
+// oauthRedirectURL is the redirect URI for a callback server on port
+func oauthRedirectURL(port int) string {
+	return fmt.Sprintf("http://localhost:%d/callback", port)
+}
+
+// oauthCallback is a local server waiting for the OAuth redirect
+type oauthCallback struct {
+	server   *http.Server
+	listener net.Listener
+	codes    chan string
+	errs     chan error
+}
+
+// listenForCallback binds the callback port right away, so a port already
+// in use is reported before the browser is opened
+func listenForCallback(port int) (*oauthCallback, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("can't listen for the Google sign-in on port %d: %v\n"+
+			"Free the port, or set OAuthCallbackPort in %s to another port registered as a redirect URI, "+
+			"or to 0 to pick a free one with a \"Desktop app\" client", port, err, configFilePath)
+	}
+
+	cb := &oauthCallback{
+		listener: listener,
+		codes:    make(chan string, 1),
+		errs:     make(chan error, 1),
+	}
+	// A mux of its own, so signing in again doesn't register the handler twice
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		if reason := r.URL.Query().Get("error"); reason != "" {
+			fmt.Fprintf(w, "Authorization failed: %s. You can close this window.", reason)
+			cb.fail(fmt.Errorf("authorization failed: %s", reason))
+			return
+		}
+		select {
+		case cb.codes <- r.URL.Query().Get("code"):
+		default:
+			// A code was already received
+		}
+		fmt.Fprintf(w, "Authorization successful! You can close this window.")
+	})
+	cb.server = &http.Server{Handler: mux}
+	go func() {
+		if err := cb.server.Serve(listener); err != http.ErrServerClosed {
+			cb.fail(fmt.Errorf("callback server error: %v", err))
+		}
+	}()
+	return cb, nil
+}
+
+// fail reports an error to wait unless one is already waiting
+func (cb *oauthCallback) fail(err error) {
+	select {
+	case cb.errs <- err:
+	default:
+	}
+}
+
+// redirectURL is the redirect URI for the port actually bound
+func (cb *oauthCallback) redirectURL() string {
+	return oauthRedirectURL(cb.listener.Addr().(*net.TCPAddr).Port)
+}
+
+// wait blocks until the redirect brings a code or the server fails, then
+// shuts the server down once the browser has had its answer
+func (cb *oauthCallback) wait() (string, error) {
+	defer func() {
+		go func() {
+			time.Sleep(time.Second)
+			cb.server.Shutdown(context.Background())
+		}()
+	}()
+	select {
+	case code := <-cb.codes:
+		return code, nil
+	case err := <-cb.errs:
+		return "", err
+	}
+}
@@ -0,0 +1,211 @@
+package internal
+
+import (
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// paletteCommand is an action reachable from the ':' command palette
+type paletteCommand struct {
+	Name string // What the user types, e.g. "set-due"
+	Desc string // Shown next to the name while matching
+	Key  string // Key binding the command replays through Update, if any
+	// Run handles commands that take arguments or have no key binding
+	Run func(m *model, args string) tea.Cmd
+}
+
+// paletteCommands is the registry the palette matches against
+var paletteCommands = []paletteCommand{
+	{Name: "new", Desc: "Create a task", Key: "n"},
+	{Name: "new-form", Desc: "Create a task with notes and a due date", Key: "N"},
+	{Name: "rename", Desc: "Rename the selected task", Key: "r"},
+	{Name: "describe", Desc: "Edit the description", Key: "i"},
+	{Name: "notes", Desc: "Edit the notes", Key: "o"},
+	{Name: "check", Desc: "Tick or untick a checklist item in the notes", Key: "x"},
+	{Name: "set-due", Desc: "Set the due date", Key: "t"},
+	{Name: "set-time", Desc: "Set the due time", Key: "T"},
+	{Name: "estimate", Desc: "Set the effort estimate", Key: "e"},
+	{Name: "snooze", Desc: "Push the due date forward", Key: "w"},
+	{Name: "block", Desc: "Set a blocking task", Key: "b"},
+	{Name: "toggle", Desc: "Toggle completion", Key: " "},
+	{Name: "progress", Desc: "Cycle not started, in progress and done", Key: "P"},
+	{Name: "delete", Desc: "Delete the selected task", Key: "d"},
+	{Name: "tree", Desc: "Toggle the inline tree view", Key: "v"},
+	{Name: "expand-all", Desc: "Expand every task in the tree view", Key: "+"},
+	{Name: "collapse-all", Desc: "Collapse every task in the tree view", Key: "-"},
+	{Name: "focus", Desc: "Toggle focus mode", Key: "f"},
+	{Name: "flag", Desc: "Cycle the color flag of the selected task", Key: "F"},
+	{Name: "pin", Desc: "Pin or unpin the list or task", Key: "p"},
+	{Name: "move-up", Desc: "Move the list or task up", Key: "K"},
+	{Name: "move-down", Desc: "Move the list or task down", Key: "J"},
+	{Name: "timer", Desc: "Start or stop the timer", Key: "s"},
+	{Name: "copy", Desc: "Copy the task to the clipboard", Key: "Y"},
+	{Name: "legend", Desc: "Show or hide the color legend", Key: "?"},
+	{Name: "hide-completed", Desc: "Hide or show completed tasks", Key: "H"},
+	{Name: "collapse-completed", Desc: "Collapse completed tasks into a count, or expand them", Key: "c"},
+	{Name: "add-link", Desc: "Attach a link to the task", Key: "L"},
+	{Name: "attach", Desc: "Attach a file or URL to the task", Key: "a"},
+	{Name: "open-link", Desc: "Open one of the task's links or files", Key: "O"},
+	{Name: "jump", Desc: "Jump to a task by ID or number", Key: "g"},
+	{Name: "move", Desc: "Move the selected task to another list", Key: "m"},
+	{Name: "dedupe", Desc: "Remove duplicate tasks, keeping the oldest", Key: "D"},
+	{Name: "review", Desc: "Review overdue, undated and stale tasks one by one", Key: "W"},
+	{Name: "switch-list", Desc: "Open a top-level list by name", Run: switchList},
+	{Name: "switch-mode", Desc: "Switch between local storage and Google Tasks", Key: "G"},
+	{Name: "calendar", Desc: "Show the month calendar of due tasks", Key: "C"},
+	{Name: "next", Desc: "Show the next action of every list", Key: "A"},
+	{Name: "trash", Desc: "Show deleted tasks to restore them", Key: "u"},
+	{Name: "lists", Desc: "Find a list by name, recently used first", Key: "ctrl+p"},
+	{Name: "next-due", Desc: "Jump to the next task overdue or due within a day", Key: "]"},
+	{Name: "prev-due", Desc: "Jump to the previous task overdue or due within a day", Key: "["},
+	{Name: "link", Desc: "Mark a blocker, then link the task it blocks", Key: "B"},
+	{Name: "clear-completed", Desc: "Clear completed tasks from Google", Key: "X"},
+	{Name: "errors", Desc: "Show the error log", Key: "E"},
+	{Name: "stats", Desc: "Show or hide Google API call counts", Key: "I"},
+	{Name: "sync", Desc: "Sync with Google Tasks now", Key: "R"},
+	{Name: "config", Desc: "Edit the config file in $EDITOR and reload it", Key: ","},
+	{Name: "quit", Desc: "Quit godo, letting running syncs finish", Key: "q"},
+	{Name: "force-quit", Desc: "Quit godo without waiting for syncs", Key: "Q"},
+}
+
+// registerCommand adds a command to the palette
+func registerCommand(cmd paletteCommand) {
+	paletteCommands = append(paletteCommands, cmd)
+}
+
+// parsePalette splits palette input into the command name and its arguments
+func parsePalette(input string) (string, string) {
+	name, args, _ := strings.Cut(strings.TrimSpace(input), " ")
+	return name, strings.TrimSpace(args)
+}
+
+// matchCommands returns the commands matching name, best first. An exact
+// name wins outright, otherwise commands are ranked by fuzzy score.
+func matchCommands(name string) []paletteCommand {
+	type scored struct {
+		cmd   paletteCommand
+		score int
+	}
+
+	var matches []scored
+	for _, cmd := range paletteCommands {
+		if cmd.Name == name {
+			return []paletteCommand{cmd}
+		}
+		if score, ok := fuzzyScore(cmd.Name, name); ok {
+			matches = append(matches, scored{cmd, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	cmds := make([]paletteCommand, len(matches))
+	for i, match := range matches {
+		cmds[i] = match.cmd
+	}
+	return cmds
+}
+
+// fuzzyScore reports whether pattern's characters appear in order in target,
+// scoring consecutive runs and matches at word starts higher
+func fuzzyScore(target, pattern string) (int, bool) {
+	target = strings.ToLower(target)
+	pattern = strings.ToLower(pattern)
+
+	score, ti, prev := 0, 0, -2
+	for _, r := range pattern {
+		found := false
+		for ; ti < len(target); ti++ {
+			if rune(target[ti]) != r {
+				continue
+			}
+			score++
+			if ti == prev+1 {
+				score += 2
+			}
+			if ti == 0 || target[ti-1] == '-' || target[ti-1] == ' ' {
+				score += 3
+			}
+			prev = ti
+			ti++
+			found = true
+			break
+		}
+		if !found {
+			return 0, false
+		}
+	}
+	// Prefer shorter names when the match is otherwise equal
+	return score*100 - len(target), true
+}
+
+// runCommand executes the palette input, replaying key bindings through Update
+// so commands behave exactly like their keys
+func (m model) runCommand(input string) (tea.Model, tea.Cmd) {
+	name, args := parsePalette(input)
+	matches := matchCommands(name)
+	if name == "" || len(matches) == 0 {
+		m.statusMsg = "Unknown command: " + name
+		return m, nil
+	}
+
+	cmd := matches[0]
+	if cmd.Run != nil {
+		return m, cmd.Run(&m, args)
+	}
+
+	updated, teaCmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(cmd.Key)})
+	// Commands that open an input can take its value as an argument
+	if um, ok := updated.(model); ok && args != "" && um.inputActive {
+		um.input.SetValue(args)
+		um.input.CursorEnd()
+		return um, teaCmd
+	}
+	return updated, teaCmd
+}
+
+// paletteSuggestions renders the commands matching the current palette input
+func (m *model) paletteSuggestions(limit int) string {
+	name, _ := parsePalette(m.input.Value())
+
+	var s strings.Builder
+	for i, cmd := range matchCommands(name) {
+		if i == limit {
+			break
+		}
+		prefix := "  "
+		if i == 0 {
+			prefix = "> "
+		}
+		s.WriteString(prefix + cmd.Name + " - " + cmd.Desc + "\n")
+	}
+	return s.String()
+}
+
+// switchList opens the top-level list whose title best matches args
+func switchList(m *model, args string) tea.Cmd {
+	if args == "" {
+		m.statusMsg = "Usage: switch-list <name>"
+		return nil
+	}
+
+	best, bestScore := -1, 0
+	for i, task := range m.tasks {
+		if score, ok := fuzzyScore(task.Title, args); ok && (best < 0 || score > bestScore) {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		m.statusMsg = "No list matches " + args
+		return nil
+	}
+
+	m.currentPath = []Task{m.tasks[best]}
+	m.currentListID = m.tasks[best].Id
+	m.cursor = 0
+	m.applyListSettings()
+	return tea.ClearScreen
+}
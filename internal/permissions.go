@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"errors"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+)
+
+// PermissionError is a 403 from Google that retrying can't fix, with what
+// the user has to do about it
+type PermissionError struct {
+	Op       string // What we were trying to do, e.g. "list tasks"
+	Guidance string
+	Reauth   bool // Re-authenticating with `godo auth` fixes it
+	Err      error
+}
+
+// Error stays on one line, unlike Google's own error text, so it fits the status line
+func (e *PermissionError) Error() string {
+	return e.Op + " failed: access denied by Google. " + e.Guidance
+}
+
+func (e *PermissionError) Unwrap() error {
+	return e.Err
+}
+
+// Guidance for the 403 reasons godo knows how to explain
+const (
+	apiDisabledGuidance = "Enable the Google Tasks API in your Cloud project: https://console.cloud.google.com/apis/library/tasks.googleapis.com"
+	scopeGuidance       = "Run `godo auth` to re-authenticate and grant access to Google Tasks"
+)
+
+// googleErrorReasons collects the reason codes of a Google API error, from
+// the legacy error items and from the raw body, which carries the newer ones
+func googleErrorReasons(apiErr *googleapi.Error) string {
+	var reasons []string
+	for _, item := range apiErr.Errors {
+		reasons = append(reasons, item.Reason)
+	}
+	return strings.Join(reasons, " ") + " " + apiErr.Message + " " + apiErr.Body
+}
+
+// isRateLimitError reports whether a 403 is Google's older way of saying 429
+func isRateLimitError(apiErr *googleapi.Error) bool {
+	reasons := googleErrorReasons(apiErr)
+	return apiErr.Code == 403 && (strings.Contains(reasons, "rateLimitExceeded") || strings.Contains(reasons, "userRateLimitExceeded"))
+}
+
+// permissionProblem explains a missing-scope or disabled-API error, or
+// returns nil for any other error
+func permissionProblem(err error) *PermissionError {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != 403 {
+		return nil
+	}
+
+	reasons := googleErrorReasons(apiErr)
+	switch {
+	case strings.Contains(reasons, "accessNotConfigured"), strings.Contains(reasons, "SERVICE_DISABLED"):
+		return &PermissionError{Guidance: apiDisabledGuidance, Err: err}
+	case strings.Contains(reasons, "insufficientPermissions"), strings.Contains(reasons, "ACCESS_TOKEN_SCOPE_INSUFFICIENT"),
+		strings.Contains(reasons, "insufficient authentication scopes"):
+		return &PermissionError{Guidance: scopeGuidance, Reauth: true, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package internal
+
+import (
+	"sort"
+	"time"
+)
+
+// pinnedFirst returns the tasks with pinned ones moved to the top. The sort
+// is stable, so both groups keep the order the sort mode gave them.
+func pinnedFirst(tasks []Task) []Task {
+	sorted := make([]Task, len(tasks))
+	copy(sorted, tasks)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Pinned && !sorted[j].Pinned
+	})
+	return sorted
+}
+
+// togglePinnedTask pins or unpins the task under the cursor. Pinning only
+// changes where the task is shown, nothing else about it.
+func (m *model) togglePinnedTask() {
+	task := m.selectedTask()
+	if task == nil {
+		return
+	}
+	if task.Completed {
+		m.statusMsg = "Only active tasks can be pinned"
+		return
+	}
+
+	task.Pinned = !task.Pinned
+	task.Updated = time.Now()
+	id := task.Id
+	m.syncToGoogle(*task)
+	m.saveTasks()
+
+	// Keep the cursor on the task that just moved
+	active, _ := m.getCurrentTasks()
+	if i := indexOfTask(active, id); i >= 0 {
+		m.cursor = i
+	}
+}
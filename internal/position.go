@@ -0,0 +1,96 @@
+package internal
+
+import "fmt"
+
+// advancedMode reports whether AdvancedMode is on, showing raw sync fields
+// like Position and allowing < and > to fix the order Google keeps
+func advancedMode() bool {
+	config := GetGlobalConfig()
+	return config != nil && config.AdvancedMode
+}
+
+// taskPositionedMsg carries the Position Google gave a repositioned task
+type taskPositionedMsg struct {
+	id       string
+	position string
+}
+
+// repositionTask moves the selected task to the top or bottom of its level,
+// telling Google explicitly which task it now follows. Unlike J and K this
+// ignores sorting and pinning, to repair orders that went wrong in a sync.
+// The move is sent to Google in the background, after any queued reorders,
+// and finishReposition records the Position it comes back with.
+func (m *model) repositionTask(top bool) {
+	if !advancedMode() {
+		m.statusMsg = "Turn on AdvancedMode in the config to reposition tasks"
+		return
+	}
+	if m.treeView {
+		m.statusMsg = "Leave tree view to reposition tasks"
+		return
+	}
+	parent := m.currentParent()
+	task := m.selectedTask()
+	if parent == nil || task == nil {
+		return
+	}
+	from := indexOfTask(parent.Tasks, task.Id)
+	if from < 0 {
+		return
+	}
+
+	moved := parent.Tasks[from]
+	rest := append(parent.Tasks[:from:from], parent.Tasks[from+1:]...)
+	previousID := ""
+	if top {
+		parent.Tasks = append([]Task{moved}, rest...)
+	} else {
+		for i := len(rest) - 1; i >= 0; i-- {
+			if !rest[i].Deleted {
+				previousID = rest[i].Id
+				break
+			}
+		}
+		parent.Tasks = append(rest, moved)
+	}
+
+	if m.googleTasks != nil {
+		parentID := ""
+		if len(m.currentPath) > 1 {
+			parentID = parent.Id
+		}
+		client, listID := m.googleTasks, m.currentPath[0].Id
+		queueReorder(func() {
+			position, err := client.moveTaskAfter(listID, moved.Id, parentID, previousID)
+			if err != nil {
+				reportSyncError(fmt.Errorf("error moving task: %v", err))
+				return
+			}
+			sendToUI(taskPositionedMsg{id: moved.Id, position: position})
+		})
+	}
+
+	// Keep the cursor on the task, wherever the sort shows it
+	for i, visible := range m.visibleTasks() {
+		if visible.Id == moved.Id {
+			m.cursor = i
+		}
+	}
+	m.saveTasks()
+	where := "bottom"
+	if top {
+		where = "top"
+	}
+	m.statusMsg = fmt.Sprintf("Moved %s to the %s", moved.Title, where)
+}
+
+// finishReposition records the Position Google gave a repositioned task
+func (m *model) finishReposition(msg taskPositionedMsg) {
+	task := m.findTask(msg.id)
+	if task == nil {
+		return
+	}
+	task.Position = msg.position
+	m.saveTasks()
+	m.statusMsg = fmt.Sprintf("%s is at position %s", task.Title, msg.position)
+}
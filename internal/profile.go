@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultProfile is the profile stored in plain tasks.json
+const DefaultProfile = "default"
+
+// activeProfile selects which tasks file local mode reads and writes
+var activeProfile = DefaultProfile
+
+// SetProfile selects the task profile, falling back to the Profile config
+// key when name is empty. Names are limited to letters, digits, - and _ as
+// they end up in a file name.
+func SetProfile(name string) error {
+	if name == "" {
+		if config := GetGlobalConfig(); config != nil {
+			name = config.Profile
+		}
+	}
+	if name == "" {
+		name = DefaultProfile
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return fmt.Errorf("invalid profile name %q, use letters, digits, - and _", name)
+		}
+	}
+	activeProfile = name
+	return nil
+}
+
+// ActiveProfile returns the selected task profile
+func ActiveProfile() string {
+	return activeProfile
+}
+
+// profileFileName returns the name of the active profile's copy of a JSON
+// file, e.g. tasks.json or tasks-work.json
+func profileFileName(base string) string {
+	if activeProfile == DefaultProfile {
+		return base + ".json"
+	}
+	return base + "-" + activeProfile + ".json"
+}
+
+// tasksFileName returns the tasks file of the active profile. CalDAV mode
+// keeps its copy of the server's tasks apart from the local tasks.
+func tasksFileName() string {
+	if UseCalDAV {
+		return profileFileName("caldav")
+	}
+	return profileFileName("tasks")
+}
+
+// tasksFilePath returns the full path of the active profile's tasks file,
+// creating the storage directory if needed
+func tasksFilePath() (string, error) {
+	return storageFile(tasksFileName())
+}
+
+// ListProfiles returns the profiles with a tasks file in the storage
+// directory, sorted, with the default profile first
+func ListProfiles() ([]string, error) {
+	path, err := storageFile("")
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading storage directory: %v", err)
+	}
+
+	var profiles []string
+	hasDefault := false
+	for _, entry := range entries {
+		name := entry.Name()
+		switch {
+		case name == "tasks.json":
+			hasDefault = true
+		case strings.HasPrefix(name, "tasks-") && filepath.Ext(name) == ".json":
+			profiles = append(profiles, strings.TrimSuffix(strings.TrimPrefix(name, "tasks-"), ".json"))
+		}
+	}
+	sort.Strings(profiles)
+	if hasDefault {
+		profiles = append([]string{DefaultProfile}, profiles...)
+	}
+	return profiles, nil
+}
@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Priority levels understood by the quick-add parser and the UI
+const (
+	PriorityHigh   = "high"
+	PriorityMedium = "medium"
+	PriorityLow    = "low"
+)
+
+// quickAddPriorities maps the inline "!" markers to priority levels
+var quickAddPriorities = map[string]string{
+	"!high":   PriorityHigh,
+	"!med":    PriorityMedium,
+	"!medium": PriorityMedium,
+	"!low":    PriorityLow,
+}
+
+// ParseQuickAdd turns a quick-add line like "Buy milk tomorrow #shopping !high"
+// into a Task with the tags, priority and due date pulled out of the title.
+// Tokens that don't clearly match one of the markers are left in the title.
+func ParseQuickAdd(input string) Task {
+	return parseQuickAddAt(input, time.Now())
+}
+
+// parseQuickAddAt is ParseQuickAdd with an explicit reference time for relative dates
+func parseQuickAddAt(input string, now time.Time) Task {
+	var task Task
+	var words []string
+
+	for _, token := range strings.Fields(input) {
+		if tag, ok := parseQuickAddTag(token); ok {
+			task.Tags = append(task.Tags, tag)
+			continue
+		}
+		if priority, ok := quickAddPriorities[strings.ToLower(token)]; ok {
+			task.Priority = priority
+			continue
+		}
+		words = append(words, token)
+	}
+
+	// Only a trailing date is treated as the due date, so "Call Tomorrowland"
+	// or "Release 2024-05-01 notes" keep their wording
+	if len(words) > 1 {
+		if due, ok := parseQuickAddDate(words[len(words)-1], now); ok {
+			task.DueDate = due
+			words = words[:len(words)-1]
+		}
+	}
+
+	task.Title = strings.Join(words, " ")
+	return task
+}
+
+// parseQuickAddTag returns the tag name for tokens like "#shopping".
+// Tags must start with a letter so things like "#1" stay in the title.
+func parseQuickAddTag(token string) (string, bool) {
+	if len(token) < 2 || token[0] != '#' {
+		return "", false
+	}
+	tag := token[1:]
+	for i, r := range tag {
+		if i == 0 && !unicode.IsLetter(r) {
+			return "", false
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return "", false
+		}
+	}
+	return tag, true
+}
+
+// parseQuickAddDate recognises "today", "tomorrow" and YYYY-MM-DD dates
+func parseQuickAddDate(token string, now time.Time) (time.Time, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch strings.ToLower(token) {
+	case "today":
+		return today, true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true
+	}
+
+	if due, err := time.ParseInLocation("2006-01-02", token, now.Location()); err == nil {
+		return due, true
+	}
+	return time.Time{}, false
+}
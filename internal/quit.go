@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pendingSyncs counts background writes to Google that haven't finished
+var pendingSyncs atomic.Int32
+
+// goSync runs a write to Google in the background, tracked so quitting can
+// wait for it
+func goSync(sync func()) {
+	pendingSyncs.Add(1)
+	go func() {
+		defer pendingSyncs.Add(-1)
+		sync()
+	}()
+}
+
+// quitWait is how long q waits for background syncs, set with QuitWaitSeconds
+func quitWait() time.Duration {
+	seconds := 10
+	if config := GetGlobalConfig(); config != nil {
+		seconds = config.QuitWaitSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// quitCheckMsg polls whether the syncs holding up a quit are done
+type quitCheckMsg time.Time
+
+func quitCheckTick() tea.Cmd {
+	return tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg {
+		return quitCheckMsg(t)
+	})
+}
+
+// quit exits the program. q first waits up to QuitWaitSeconds for syncs
+// still running in the background; Q, ctrl+c or a second q quit at once.
+func (m *model) quit(key string) tea.Cmd {
+	if key != "q" || m.quitting || pendingSyncs.Load() == 0 || quitWait() <= 0 {
+		return tea.Quit
+	}
+	m.quitting = true
+	m.quitDeadline = time.Now().Add(quitWait())
+	m.statusMsg = finishingSyncStatus()
+	return quitCheckTick()
+}
+
+// checkQuit quits once the background syncs are done or the wait is over
+func (m *model) checkQuit(now time.Time) tea.Cmd {
+	if !m.quitting {
+		return nil
+	}
+	pending := pendingSyncs.Load()
+	if pending == 0 {
+		return tea.Quit
+	}
+	if now.After(m.quitDeadline) {
+		logError("Quit with %d sync(s) to Google unfinished", pending)
+		return tea.Quit
+	}
+	m.statusMsg = finishingSyncStatus()
+	return quitCheckTick()
+}
+
+// finishingSyncStatus tells the user why quitting is taking a moment
+func finishingSyncStatus() string {
+	return fmt.Sprintf("Finishing sync… (%d left, q or Q to quit now)", pendingSyncs.Load())
+}
@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// remoteNotifyDelay gathers a burst of remote changes into one notification
+const remoteNotifyDelay = 5 * time.Second
+
+// remoteNotifyMsg fires remoteNotifyDelay after the first unannounced change
+type remoteNotifyMsg struct{}
+
+// remoteNotificationsEnabled reports whether RemoteChangeNotifications is on
+func remoteNotificationsEnabled() bool {
+	config := GetGlobalConfig()
+	return config != nil && config.RemoteChangeNotifications
+}
+
+// taskContent is the part of a task another device can change, leaving out
+// fields like the etag that change with every write from here too
+type taskContent struct {
+	Title     string
+	Notes     string
+	Completed bool
+	DueDate   time.Time
+	Priority  string
+}
+
+// contentOf returns the comparable content of a task, with the due date in
+// UTC so the same moment always compares equal
+func contentOf(task Task) taskContent {
+	return taskContent{task.Title, task.Notes, task.Completed, task.DueDate.UTC(), task.Priority}
+}
+
+// countRemoteChanges counts the tasks in fresh that are new or whose content
+// differs from current. Task lists themselves aren't counted.
+func countRemoteChanges(current, fresh []Task) int {
+	known := make(map[string]taskContent)
+	var index func(tasks []Task)
+	index = func(tasks []Task) {
+		for _, task := range tasks {
+			known[task.Id] = contentOf(task)
+			index(task.Tasks)
+		}
+	}
+	index(current)
+
+	count := 0
+	var walk func(tasks []Task)
+	walk = func(tasks []Task) {
+		for _, task := range tasks {
+			if task.Kind != "tasks#taskList" && !task.Deleted {
+				if content, ok := known[task.Id]; !ok || content != contentOf(task) {
+					count++
+				}
+			}
+			walk(task.Tasks)
+		}
+	}
+	walk(fresh)
+	return count
+}
+
+// noteRemoteChanges counts the changes a fresh tree brings for the badge and,
+// when enabled, schedules a notification unless one is already on its way
+func (m *model) noteRemoteChanges(tasks []Task) tea.Cmd {
+	count := countRemoteChanges(m.allTasks(), tasks)
+	if count == 0 {
+		return nil
+	}
+	m.remoteChanges += count
+	if !remoteNotificationsEnabled() {
+		return nil
+	}
+	m.remoteUnannounced += count
+	if m.remoteNotifyScheduled {
+		return nil
+	}
+	m.remoteNotifyScheduled = true
+	return tea.Tick(remoteNotifyDelay, func(time.Time) tea.Msg {
+		return remoteNotifyMsg{}
+	})
+}
+
+// announceRemoteChanges shows one notification for the changes gathered
+// since the last one
+func (m *model) announceRemoteChanges() tea.Cmd {
+	count := m.remoteUnannounced
+	m.remoteUnannounced = 0
+	m.remoteNotifyScheduled = false
+	if count == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		sendPlainNotification("godo", remoteChangesText(count))
+		return nil
+	}
+}
+
+// remoteChangesHint is the badge for changes that arrived since the last
+// keypress, or empty
+func (m *model) remoteChangesHint() string {
+	if m.remoteChanges == 0 {
+		return ""
+	}
+	return "↻ " + remoteChangesText(m.remoteChanges)
+}
+
+func remoteChangesText(count int) string {
+	if count == 1 {
+		return "1 task updated from another device"
+	}
+	return fmt.Sprintf("%d tasks updated from another device", count)
+}
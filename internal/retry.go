@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	maxAPIAttempts = 5                      // Attempts before giving up on a call
+	baseAPIBackoff = 500 * time.Millisecond // Delay before the first retry
+)
+
+// APIError is returned once a Google Tasks call has failed for good
+type APIError struct {
+	Op        string // What we were trying to do, e.g. "create task"
+	Attempts  int    // How many times the call was made
+	Retryable bool   // Whether the last failure was transient
+	Err       error
+}
+
+func (e *APIError) Error() string {
+	if e.Attempts > 1 {
+		return fmt.Sprintf("%s failed after %d attempts: %v", e.Op, e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("%s failed: %v", e.Op, e.Err)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// withRetry runs a Tasks API call, retrying transient failures with
+// exponential backoff and jitter. Permanent failures return immediately.
+func withRetry(op string, call func() error) error {
+	for attempt := 1; ; attempt++ {
+		recordAPICall(op)
+		if attempt > 1 {
+			apiMetrics.retries.Add(1)
+		}
+		err := call()
+		if err == nil {
+			return nil
+		}
+
+		retryable := isRetryableError(err)
+		if !retryable || attempt == maxAPIAttempts {
+			apiMetrics.failures.Add(1)
+			apiErr := &APIError{Op: op, Attempts: attempt, Retryable: retryable, Err: err}
+			if perm := permissionProblem(err); perm != nil {
+				perm.Op, perm.Err = op, apiErr
+				return perm
+			}
+			return apiErr
+		}
+		time.Sleep(backoffDelay(attempt))
+	}
+}
+
+// backoffDelay doubles the delay each attempt and picks a random point in
+// its upper half so concurrent retries don't line up
+func backoffDelay(attempt int) time.Duration {
+	delay := baseAPIBackoff << (attempt - 1)
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// isRetryableError reports whether an error is worth retrying: network
+// failures, rate limiting and server errors. Not-found and auth errors aren't.
+func isRetryableError(err error) bool {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == 429 || apiErr.Code >= 500 || isRateLimitError(apiErr)
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,130 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// reviewItem is a task queued for review and why it was picked
+type reviewItem struct {
+	id     string
+	reason string
+}
+
+// reviewQueue collects the unfinished tasks worth a look: overdue ones
+// first, then those without a due date, then the stale ones
+func reviewQueue(tasks []Task, now time.Time) []reviewItem {
+	var overdue, undated, stale []reviewItem
+	wt := currentWorkingTime()
+	var walk func(tasks []Task)
+	walk = func(tasks []Task) {
+		for _, task := range tasks {
+			if task.Deleted {
+				continue
+			}
+			if task.Kind != "tasks#taskList" && !task.Completed {
+				switch {
+				case isOverdue(task, now, wt):
+					overdue = append(overdue, reviewItem{task.Id, "overdue"})
+				case task.DueDate.IsZero():
+					undated = append(undated, reviewItem{task.Id, "no due date"})
+				case isStale(task, now):
+					stale = append(stale, reviewItem{task.Id, "untouched for " + formatAge(now.Sub(task.Updated))})
+				}
+			}
+			walk(task.Tasks)
+		}
+	}
+	walk(tasks)
+	return append(append(overdue, undated...), stale...)
+}
+
+// formatAge formats a long duration in days
+func formatAge(d time.Duration) string {
+	return fmt.Sprintf("%d days", int(d.Hours()/24))
+}
+
+// startReview builds the queue and shows its first task
+func (m *model) startReview() {
+	m.reviewItems = reviewQueue(m.allTasks(), time.Now())
+	m.reviewIndex = -1
+	m.reviewing = true
+	m.nextReviewItem()
+}
+
+// nextReviewItem moves to the next queued task that still needs review,
+// ending the review after the last one
+func (m *model) nextReviewItem() {
+	for m.reviewIndex++; m.reviewIndex < len(m.reviewItems); m.reviewIndex++ {
+		item := m.reviewItems[m.reviewIndex]
+		if task := m.findTask(item.id); task != nil && !task.Completed && !task.Deleted {
+			m.jumpTo(item.id)
+			return
+		}
+	}
+	m.reviewing = false
+	m.statusMsg = fmt.Sprintf("Review finished, %d task(s) looked at", len(m.reviewItems))
+}
+
+// currentReviewTask returns the task under review, or nil once it is gone,
+// e.g. after being deleted
+func (m *model) currentReviewTask() *Task {
+	if m.reviewIndex < 0 || m.reviewIndex >= len(m.reviewItems) {
+		return nil
+	}
+	return m.findTask(m.reviewItems[m.reviewIndex].id)
+}
+
+// reviewKey handles a key during review. Rescheduling and deleting go
+// through the regular handlers, so it reports those as not handled.
+func (m *model) reviewKey(key string) (bool, tea.Cmd) {
+	task := m.currentReviewTask()
+	if task == nil || task.Deleted {
+		// The task was deleted, any key moves on
+		m.nextReviewItem()
+		return true, nil
+	}
+
+	switch key {
+	case "enter", "s", "n":
+		m.nextReviewItem()
+		return true, nil
+	case " ", "c":
+		m.toggleCompletion(task.Id)
+		m.nextReviewItem()
+		return true, completionBell()
+	case "t", "T", "d":
+		return false, nil
+	case "esc", "q":
+		m.reviewing = false
+		m.statusMsg = "Review stopped"
+		return true, nil
+	}
+	return true, nil
+}
+
+// renderReview shows the task under review and what can be done with it
+func (m *model) renderReview() string {
+	task := m.currentReviewTask()
+	if task == nil || task.Deleted {
+		return "Task deleted, press enter to continue the review\n"
+	}
+	item := m.reviewItems[m.reviewIndex]
+
+	var s strings.Builder
+	s.WriteString(fmt.Sprintf("Review %d/%d (%s)\n\n", m.reviewIndex+1, len(m.reviewItems), item.reason))
+	s.WriteString(lipgloss.NewStyle().Bold(true).Foreground(m.accentColor()).Render(task.Title) + "\n")
+	if !task.DueDate.IsZero() {
+		s.WriteString("Due " + formatDate(task.DueDate) + "\n")
+	}
+	if task.Notes != "" {
+		s.WriteString("\n" + task.Notes + "\n")
+	}
+	s.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(
+		"t: Reschedule  space: Complete  d: Delete  enter/s: Skip  esc: Stop") + "\n")
+	return s.String()
+}
@@ -0,0 +1,202 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// taskServer exposes the task tree over a small JSON API
+type taskServer struct {
+	mu    sync.Mutex // Serializes load-modify-save cycles
+	store Store
+}
+
+// Serve starts the HTTP API on addr and blocks until ctx is cancelled
+func Serve(ctx context.Context, addr string) error {
+	s := &taskServer{store: DefaultStore()}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tasks", s.listTasks)
+	mux.HandleFunc("POST /tasks", s.createTask)
+	mux.HandleFunc("PATCH /tasks/{id}", s.updateTask)
+	mux.HandleFunc("DELETE /tasks/{id}", s.deleteTask)
+
+	server := &http.Server{Addr: addr, Handler: mux}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+	fmt.Printf("Serving tasks on http://%s\n", addr)
+
+	select {
+	case err := <-errCh:
+		if err != http.ErrServerClosed {
+			return fmt.Errorf("server error: %v", err)
+		}
+		return nil
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("error shutting down server: %v", err)
+		}
+		return nil
+	}
+}
+
+// storeFor returns the store a request operates on. In Google mode the
+// optional list query parameter picks the list new tasks go to.
+func (s *taskServer) storeFor(r *http.Request) Store {
+	if UseGoogleTasks.Load() {
+		return NewGoogleStore(GoogleTasksClientVar, r.URL.Query().Get("list"))
+	}
+	return s.store
+}
+
+func (s *taskServer) listTasks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tasks, err := s.storeFor(r).List()
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+func (s *taskServer) createTask(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var task Task
+	if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Errorf("invalid task: %v", err))
+		return
+	}
+	if task.Title == "" {
+		writeJSONError(w, http.StatusBadRequest, fmt.Errorf("task title is required"))
+		return
+	}
+
+	created, err := s.storeFor(r).Add(task)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *taskServer) updateTask(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	store := s.storeFor(r)
+	tasks, err := store.List()
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	task := findTask(tasks, id)
+	if task == nil {
+		writeJSONError(w, http.StatusNotFound, fmt.Errorf("%w: %s", ErrTaskNotFound, id))
+		return
+	}
+
+	// Decoding onto the existing task only overwrites the fields in the body
+	wasCompleted := task.Completed
+	if err := json.NewDecoder(r.Body).Decode(task); err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Errorf("invalid task: %v", err))
+		return
+	}
+	task.Id = id
+
+	// Completion goes through the same helpers as the TUI and godo done, so
+	// the date is set and subtasks and parents follow
+	completing := task.Completed && !wasCompleted
+	var changed []Task
+	switch {
+	case completing:
+		// store.Complete does it once the other fields are saved
+		task.Completed = false
+	case !task.Completed && wasCompleted:
+		now := time.Now()
+		setCompleted(task, false, now)
+		changed = append(cascadeCompletion(task, now), propagateCompletion(tasks, id, now)...)
+	}
+	if task.Completed {
+		task.Status = "completed"
+	} else {
+		task.Status = "needsAction"
+	}
+
+	if err := store.Update(*task); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	for _, other := range changed {
+		if err := store.Update(other); err != nil {
+			writeStoreError(w, err)
+			return
+		}
+	}
+	if completing {
+		if _, err := store.Complete(id); err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		if tasks, err = store.List(); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if task = findTask(tasks, id); task == nil {
+			writeJSONError(w, http.StatusNotFound, fmt.Errorf("%w: %s", ErrTaskNotFound, id))
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, task)
+}
+
+func (s *taskServer) deleteTask(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.storeFor(r).Delete(r.PathValue("id")); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeStoreError reports a Store error, mapping unknown IDs to 404 and
+// failed Google calls to 502
+func writeStoreError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrTaskNotFound):
+		writeJSONError(w, http.StatusNotFound, err)
+	case UseGoogleTasks.Load():
+		writeJSONError(w, http.StatusBadGateway, err)
+	default:
+		writeJSONError(w, http.StatusInternalServerError, err)
+	}
+}
+
+// writeJSON encodes v as the response body with the given status code
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeJSONError reports err as a JSON object with the given status code
+func writeJSONError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUpdateTaskCompletes(t *testing.T) {
+	defer SetGlobalConfig(GetGlobalConfig())
+	SetGlobalConfig(&GodoConfig{StoragePath: t.TempDir(), CascadeCompletion: true, ReactivateSubtasks: true})
+
+	store := NewLocalStore()
+	if err := store.Save([]Task{{
+		Id: "list", Title: "List", Kind: "tasks#task", Status: "needsAction",
+		Tasks: []Task{{
+			Id: "parent", Parent: "list", Title: "Parent", Kind: "tasks#task", Status: "needsAction",
+			Tasks: []Task{{Id: "child", Parent: "parent", Title: "Child", Kind: "tasks#task", Status: "needsAction"}},
+		}},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	s := &taskServer{store: store}
+
+	patch := func(body string) Task {
+		t.Helper()
+		r := httptest.NewRequest(http.MethodPatch, "/tasks/parent", strings.NewReader(body))
+		r.SetPathValue("id", "parent")
+		w := httptest.NewRecorder()
+		s.updateTask(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("PATCH %s: status %d, %s", body, w.Code, w.Body)
+		}
+		var task Task
+		if err := json.NewDecoder(w.Body).Decode(&task); err != nil {
+			t.Fatal(err)
+		}
+		return task
+	}
+	stored := func(id string) Task {
+		t.Helper()
+		tasks, err := store.List()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return *findTask(tasks, id)
+	}
+
+	task := patch(`{"completed": true, "title": "Parent, done"}`)
+	if !task.Completed || task.Status != "completed" || task.CompletedDate.IsZero() || task.Title != "Parent, done" {
+		t.Errorf("got completed %v, status %q, date %v, title %q", task.Completed, task.Status, task.CompletedDate, task.Title)
+	}
+	if child := stored("child"); !child.Completed || child.CompletedDate.IsZero() {
+		t.Errorf("the child wasn't completed with its parent: completed %v, date %v", child.Completed, child.CompletedDate)
+	}
+	if list := stored("list"); list.Completed {
+		t.Error("completing the only task completed its list")
+	}
+
+	task = patch(`{"completed": false}`)
+	if task.Completed || task.Status != "needsAction" || !task.CompletedDate.IsZero() {
+		t.Errorf("got completed %v, status %q, date %v after reopening", task.Completed, task.Status, task.CompletedDate)
+	}
+	if child := stored("child"); child.Completed {
+		t.Error("the child stayed completed after reopening its parent with ReactivateSubtasks")
+	}
+}
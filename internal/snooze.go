@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// snoozePreset is one choice in the snooze menu
+type snoozePreset struct {
+	Key   string
+	Label string
+	Apply func(base time.Time) time.Time
+}
+
+var snoozePresets = []snoozePreset{
+	{Key: "1", Label: "1 hour", Apply: func(base time.Time) time.Time {
+		return base.Add(time.Hour)
+	}},
+	{Key: "2", Label: "Tomorrow", Apply: func(base time.Time) time.Time {
+		return base.AddDate(0, 0, 1)
+	}},
+	{Key: "3", Label: "Next week", Apply: func(base time.Time) time.Time {
+		return base.AddDate(0, 0, 7)
+	}},
+}
+
+// snoozeBase is the time a snooze is measured from: the due date if it is
+// still ahead, otherwise now, so overdue and undated tasks move into the future
+func snoozeBase(task Task, now time.Time) time.Time {
+	if task.DueDate.After(now) {
+		return task.DueDate
+	}
+	return now
+}
+
+// snooze pushes the selected task's due date forward by the preset bound to key
+func (m *model) snooze(key string) {
+	task := m.selectedTask()
+	if task == nil {
+		return
+	}
+	for _, preset := range snoozePresets {
+		if preset.Key != key {
+			continue
+		}
+		now := time.Now()
+		task.DueDate = preset.Apply(snoozeBase(*task, now))
+		task.Updated = now
+		m.syncToGoogle(*task)
+		m.saveTasks()
+		m.statusMsg = "Snoozed until " + formatDate(task.DueDate)
+		return
+	}
+}
+
+// renderSnoozeMenu lists the snooze presets
+func renderSnoozeMenu() string {
+	var s strings.Builder
+	s.WriteString("Snooze until:\n")
+	for _, preset := range snoozePresets {
+		s.WriteString(fmt.Sprintf("  %s: %s\n", preset.Key, preset.Label))
+	}
+	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("  esc: Cancel"))
+	return s.String() + "\n\n"
+}
@@ -0,0 +1,20 @@
+package internal
+
+import "time"
+
+// staleMarker flags active tasks nobody has touched in StaleAfterDays
+const staleMarker = "💤 "
+
+// isStale reports whether an active task was last updated longer ago than
+// StaleAfterDays. Tasks from before godo tracked updates have no Updated time
+// and are never stale, rather than all being stale at once.
+func isStale(task Task, now time.Time) bool {
+	config := GetGlobalConfig()
+	if config == nil || config.StaleAfterDays <= 0 {
+		return false
+	}
+	if task.Completed || task.Kind == "tasks#taskList" || task.Updated.IsZero() {
+		return false
+	}
+	return now.Sub(task.Updated) > time.Duration(config.StaleAfterDays)*24*time.Hour
+}
@@ -1,47 +1,60 @@
 package internal
 
 import (
-	"encoding/json"
 	"os"
 	"path/filepath"
 	"fmt"
+	"time"
 )
 
-// SaveTasks saves the tasks to a JSON file in the configured storage path
-func SaveTasks(tasks []Task) error {
+// storageFile returns the path of a file in the configured storage directory,
+// creating the directory if needed
+func storageFile(name string) (string, error) {
 	config := GetGlobalConfig()
 	if config == nil {
-		return fmt.Errorf("global config not initialized")
+		return "", fmt.Errorf("global config not initialized")
 	}
 
 	storagePath := os.ExpandEnv(config.StoragePath)
 	if err := os.MkdirAll(storagePath, 0755); err != nil {
-		return fmt.Errorf("failed to create storage directory: %v", err)
+		return "", fmt.Errorf("failed to create storage directory: %v", err)
 	}
+	return filepath.Join(storagePath, name), nil
+}
 
-	tasksFile := filepath.Join(storagePath, "tasks.json")
-	data, err := json.MarshalIndent(tasks, "", "  ")
+// SaveTasks saves the tasks to the active profile's JSON file in the
+// configured storage path
+func SaveTasks(tasks []Task) error {
+	tasksFile, err := tasksFilePath()
+	if err != nil {
+		return err
+	}
+
+	data, err := encodeTaskFile(tasks)
 	if err != nil {
 		return fmt.Errorf("failed to marshal tasks: %v", err)
 	}
 
+	recordOwnWrite(data)
 	if err := os.WriteFile(tasksFile, data, 0644); err != nil {
 		return fmt.Errorf("failed to write tasks file: %v", err)
 	}
 
+	// In CalDAV mode the file is a copy; the server gets the changes
+	if UseCalDAV && CalDAVClientVar != nil {
+		CalDAVClientVar.queuePush(tasks)
+	}
 	return nil
 }
 
-// LoadTasks loads tasks from the JSON file in the configured storage path
+// LoadTasks loads tasks from the active profile's JSON file in the
+// configured storage path
 func LoadTasks() ([]Task, error) {
-	config := GetGlobalConfig()
-	if config == nil {
-		return nil, fmt.Errorf("global config not initialized")
+	tasksFile, err := tasksFilePath()
+	if err != nil {
+		return nil, err
 	}
 
-	storagePath := os.ExpandEnv(config.StoragePath)
-	tasksFile := filepath.Join(storagePath, "tasks.json")
-
 	if _, err := os.Stat(tasksFile); os.IsNotExist(err) {
 		return []Task{}, nil
 	}
@@ -51,10 +64,48 @@ func LoadTasks() ([]Task, error) {
 		return nil, fmt.Errorf("failed to read tasks file: %v", err)
 	}
 
-	var tasks []Task
-	if err := json.Unmarshal(data, &tasks); err != nil {
+	tasks, err := decodeTaskFile(data)
+	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal tasks: %v", err)
 	}
 
-	return tasks, nil
+	return pruneDeletedTasks(tasks), nil
+}
+
+// backfillDefaults fills in fields that older or hand-written task files left
+// empty, so every loaded task has a status, kind and creation time
+func backfillDefaults(tasks []Task, now time.Time) []Task {
+	for i := range tasks {
+		task := &tasks[i]
+		if task.Status == "" {
+			if task.Completed {
+				task.Status = "completed"
+			} else {
+				task.Status = "needsAction"
+			}
+		}
+		if task.Kind == "" {
+			task.Kind = "tasks#task"
+		}
+
+		// Created and CreatedAt mean the same thing, so take whichever is set
+		if task.CreatedAt.IsZero() {
+			task.CreatedAt = task.Created
+		}
+		if task.CreatedAt.IsZero() {
+			task.CreatedAt = task.Updated
+		}
+		if task.CreatedAt.IsZero() {
+			task.CreatedAt = now
+		}
+		if task.Created.IsZero() {
+			task.Created = task.CreatedAt
+		}
+		if task.Updated.IsZero() {
+			task.Updated = task.CreatedAt
+		}
+
+		task.Tasks = backfillDefaults(task.Tasks, now)
+	}
+	return tasks
 }
@@ -0,0 +1,378 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrTaskNotFound is returned by Store methods given an unknown task ID
+var ErrTaskNotFound = errors.New("task not found")
+
+// Store is the task storage API shared by the local JSON file and Google
+// Tasks, so programs can manage tasks without going through the TUI
+type Store interface {
+	// List returns the whole task tree, task lists at the top level
+	List() ([]Task, error)
+	// Add creates a task under task.Parent, or at the top level if empty,
+	// along with any subtasks in task.Tasks, and returns it with its ID and
+	// defaults filled in
+	Add(task Task) (Task, error)
+	// Update replaces the stored fields of the task with the same ID
+	Update(task Task) error
+	// Delete removes a task and its subtasks
+	Delete(id string) error
+	// Complete marks a task done and returns every task that changed
+	Complete(id string) ([]Task, error)
+	// Save stores a whole task tree edited in memory, as the TUI keeps it
+	Save(tasks []Task) error
+}
+
+// newTaskDefaults fills in the fields every newly created task needs
+func newTaskDefaults(task *Task, now time.Time) {
+	task.CreatedAt = now
+	task.Created = now
+	task.Updated = now
+	if task.Kind == "" {
+		task.Kind = "tasks#task"
+	}
+	if task.Status == "" {
+		task.Status = "needsAction"
+	}
+}
+
+// assignSubtaskIDs gives the subtasks of a new local task IDs, pointing each
+// at its parent
+func assignSubtaskIDs(task *Task) {
+	for i := range task.Tasks {
+		task.Tasks[i].Id = generateID()
+		task.Tasks[i].Parent = task.Id
+		assignSubtaskIDs(&task.Tasks[i])
+	}
+}
+
+// LocalStore keeps tasks in the active profile's tasks file under the
+// configured storage path
+type LocalStore struct {
+	mu sync.Mutex // Serializes load-modify-save cycles
+}
+
+// NewLocalStore returns a store backed by the local tasks file
+func NewLocalStore() *LocalStore {
+	return &LocalStore{}
+}
+
+func (s *LocalStore) List() ([]Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return LoadTasks()
+}
+
+func (s *LocalStore) Add(task Task) (Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tasks, err := LoadTasks()
+	if err != nil {
+		return Task{}, err
+	}
+
+	newTaskDefaults(&task, time.Now())
+	task.Id = generateID()
+	assignSubtaskIDs(&task)
+	if task.Parent != "" {
+		parent := findTask(tasks, task.Parent)
+		if parent == nil {
+			return Task{}, fmt.Errorf("parent %w: %s", ErrTaskNotFound, task.Parent)
+		}
+		// Task lists aren't parents, only containers
+		if parent.Kind == "tasks#taskList" {
+			task.Parent = ""
+		}
+		parent.Tasks = append(parent.Tasks, task)
+	} else {
+		tasks = append(tasks, task)
+	}
+
+	if err := SaveTasks(tasks); err != nil {
+		return Task{}, err
+	}
+	return task, nil
+}
+
+func (s *LocalStore) Update(task Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tasks, err := LoadTasks()
+	if err != nil {
+		return err
+	}
+
+	existing := findTask(tasks, task.Id)
+	if existing == nil {
+		return fmt.Errorf("%w: %s", ErrTaskNotFound, task.Id)
+	}
+	if task.Tasks == nil {
+		// Leave subtasks alone unless the caller replaced them
+		task.Tasks = existing.Tasks
+	}
+	task.Updated = time.Now()
+	*existing = task
+
+	return SaveTasks(tasks)
+}
+
+func (s *LocalStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tasks, err := LoadTasks()
+	if err != nil {
+		return err
+	}
+	if findTask(tasks, id) == nil {
+		return fmt.Errorf("%w: %s", ErrTaskNotFound, id)
+	}
+	return SaveTasks(removeTaskByID(tasks, id))
+}
+
+func (s *LocalStore) Complete(id string) ([]Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tasks, err := LoadTasks()
+	if err != nil {
+		return nil, err
+	}
+	changed, err := CompleteTask(tasks, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := SaveTasks(tasks); err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
+
+func (s *LocalStore) Save(tasks []Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return SaveTasks(tasks)
+}
+
+// GoogleStore keeps tasks in Google Tasks. New tasks go to ListID, or the
+// first task list when it is empty.
+type GoogleStore struct {
+	Client *GoogleTasksClient
+	ListID string
+}
+
+// NewGoogleStore returns a store backed by an authenticated Google Tasks client
+func NewGoogleStore(client *GoogleTasksClient, listID string) *GoogleStore {
+	return &GoogleStore{Client: client, ListID: listID}
+}
+
+func (s *GoogleStore) List() ([]Task, error) {
+	return s.Client.LoadTasks()
+}
+
+func (s *GoogleStore) Add(task Task) (Task, error) {
+	newTaskDefaults(&task, time.Now())
+	created, err := s.Client.CreateTask(task, s.ListID)
+	if err != nil || len(task.Tasks) == 0 {
+		return created, err
+	}
+	return created, s.Client.PushTasks(s.ListID, created.Id, task.Tasks)
+}
+
+func (s *GoogleStore) Update(task Task) error {
+	listID, err := s.listOf(task.Id)
+	if err != nil {
+		return err
+	}
+	task.Updated = time.Now()
+	return s.Client.UpdateTask(task, listID)
+}
+
+func (s *GoogleStore) Delete(id string) error {
+	listID, err := s.listOf(id)
+	if err != nil {
+		return err
+	}
+	return s.Client.DeleteTask(id, listID)
+}
+
+// Save keeps tasks as the copy shown before the next fetch. Changes reach
+// Google task by task through Add, Update and Delete.
+func (s *GoogleStore) Save(tasks []Task) error {
+	return saveGoogleCopy(tasks)
+}
+
+// listOf returns the ID of the task list holding a task, as Google only
+// finds tasks within their own list. The cached tasks usually know, so
+// fetching is the fallback.
+func (s *GoogleStore) listOf(id string) (string, error) {
+	if listID := listIDOf(CachedTasks(), id); listID != "" {
+		return listID, nil
+	}
+	tasks, err := s.List()
+	if err != nil {
+		return "", err
+	}
+	listID := listIDOf(tasks, id)
+	if listID == "" {
+		return "", fmt.Errorf("%w: %s", ErrTaskNotFound, id)
+	}
+	return listID, nil
+}
+
+func (s *GoogleStore) Complete(id string) ([]Task, error) {
+	tasks, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	changed, err := CompleteTask(tasks, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range changed {
+		if err := s.Client.UpdateTask(task, listIDOf(tasks, task.Id)); err != nil {
+			return nil, err
+		}
+	}
+	return changed, nil
+}
+
+// CalDAVStore keeps tasks on a CalDAV server. Each change loads the tasks,
+// applies the change as LocalStore would and pushes what changed.
+type CalDAVStore struct {
+	Client *CalDAVClient
+	mu     sync.Mutex
+}
+
+// NewCalDAVStore returns a store backed by a CalDAV client
+func NewCalDAVStore(client *CalDAVClient) *CalDAVStore {
+	return &CalDAVStore{Client: client}
+}
+
+// List fetches the tasks from the server and keeps a copy in the CalDAV
+// tasks file, falling back to that copy when the server can't be reached
+func (s *CalDAVStore) List() ([]Task, error) {
+	tasks, err := s.Client.LoadTasks()
+	if err != nil {
+		logError("Error loading tasks from CalDAV, using the saved copy: %v", err)
+		return ImportFromLocal()
+	}
+	if err := SaveToLocal(tasks); err != nil {
+		logError("Error saving CalDAV tasks: %v", err)
+	}
+	return tasks, nil
+}
+
+func (s *CalDAVStore) Add(task Task) (Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tasks, err := s.Client.LoadTasks()
+	if err != nil {
+		return Task{}, err
+	}
+	newTaskDefaults(&task, time.Now())
+	task.Id = generateID()
+	assignSubtaskIDs(&task)
+
+	// Tasks need a calendar, so loose ones go to the first
+	parent := findTask(tasks, task.Parent)
+	if task.Parent == "" && len(tasks) > 0 {
+		parent = &tasks[0]
+	}
+	if parent == nil {
+		return Task{}, fmt.Errorf("parent %w: %s", ErrTaskNotFound, task.Parent)
+	}
+	if parent.Kind == "tasks#taskList" {
+		task.Parent = ""
+	}
+	parent.Tasks = append(parent.Tasks, task)
+	return task, s.Client.Push(tasks)
+}
+
+func (s *CalDAVStore) Update(task Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tasks, err := s.Client.LoadTasks()
+	if err != nil {
+		return err
+	}
+	existing := findTask(tasks, task.Id)
+	if existing == nil {
+		return fmt.Errorf("%w: %s", ErrTaskNotFound, task.Id)
+	}
+	if task.Tasks == nil {
+		task.Tasks = existing.Tasks
+	}
+	task.Updated = time.Now()
+	*existing = task
+	return s.Client.Push(tasks)
+}
+
+func (s *CalDAVStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tasks, err := s.Client.LoadTasks()
+	if err != nil {
+		return err
+	}
+	if findTask(tasks, id) == nil {
+		return fmt.Errorf("%w: %s", ErrTaskNotFound, id)
+	}
+	return s.Client.Push(removeTaskByID(tasks, id))
+}
+
+func (s *CalDAVStore) Complete(id string) ([]Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tasks, err := s.Client.LoadTasks()
+	if err != nil {
+		return nil, err
+	}
+	changed, err := CompleteTask(tasks, id)
+	if err != nil {
+		return nil, err
+	}
+	return changed, s.Client.Push(tasks)
+}
+
+// Save writes the copy in the CalDAV tasks file and pushes the changes to
+// the server in the background
+func (s *CalDAVStore) Save(tasks []Task) error {
+	return SaveTasks(tasks)
+}
+
+// SaveTasksNow saves a whole task tree as DefaultStore().Save does, except
+// that in CalDAV mode it pushes to the server before returning, for commands
+// that exit straight after. The tree must come from DefaultStore().List.
+func SaveTasksNow(tasks []Task) error {
+	if UseCalDAV && CalDAVClientVar != nil {
+		if err := SaveToLocal(tasks); err != nil {
+			return err
+		}
+		return CalDAVClientVar.Push(tasks)
+	}
+	return DefaultStore().Save(tasks)
+}
+
+// DefaultStore returns the store for the storage mode godo was started in
+func DefaultStore() Store {
+	if UseCalDAV {
+		return NewCalDAVStore(CalDAVClientVar)
+	}
+	if UseGoogleTasks.Load() {
+		return NewGoogleStore(GoogleTasksClientVar, "")
+	}
+	return NewLocalStore()
+}
@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// subtaskProgress counts the completed and total subtasks of a task, direct
+// children only unless SubtaskCountRecursive is set
+func subtaskProgress(task Task) (done, total int) {
+	config := GetGlobalConfig()
+	recursive := config != nil && config.SubtaskCountRecursive
+
+	var count func(tasks []Task)
+	count = func(tasks []Task) {
+		for _, sub := range tasks {
+			if sub.Deleted {
+				continue
+			}
+			total++
+			if sub.Completed {
+				done++
+			}
+			if recursive {
+				count(sub.Tasks)
+			}
+		}
+	}
+	count(task.Tasks)
+	return done, total
+}
+
+// subtaskBadge returns the sublist marker with progress, e.g. " ▶ 2/5", or
+// nothing for tasks without subtasks
+func subtaskBadge(task Task) string {
+	done, total := subtaskProgress(task)
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" ▶ %d/%d", done, total)
+}
+
+// listBadge returns the counts shown next to a task list in the overview,
+// e.g. " — 7 active (2 in progress), 3 done", from the tasks directly in
+// the list
+func listBadge(list Task) string {
+	active, started, done := 0, 0, 0
+	for _, task := range list.Tasks {
+		if task.Deleted {
+			continue
+		}
+		if task.Completed {
+			done++
+		} else {
+			active++
+			if task.InProgress {
+				started++
+			}
+		}
+	}
+	if active+done == 0 {
+		return " — empty"
+	}
+	if started > 0 {
+		return fmt.Sprintf(" — %d active (%d in progress), %d done", active, started, done)
+	}
+	return fmt.Sprintf(" — %d active, %d done", active, done)
+}
+
+// fitTitle shortens title so it and suffix fit in width columns. A width of
+// zero or less means the terminal size isn't known yet, so nothing is cut.
+func fitTitle(title, suffix string, width int) string {
+	if width <= 0 || lipgloss.Width(title+suffix) <= width {
+		return title + suffix
+	}
+	return ansi.Truncate(title, max(width-lipgloss.Width(suffix), 1), "…") + suffix
+}
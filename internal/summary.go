@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"fmt"
+	"time"
+)
+
+// Summary counts the tasks that matter today, for status bars and scripts
+type Summary struct {
+	DueToday   int `json:"dueToday"`
+	Overdue    int `json:"overdue"`
+	Active     int `json:"active"`
+	InProgress int `json:"inProgress"`
+}
+
+// Summarize counts unfinished tasks at every level of the tree. Task list
+// containers are skipped so only real tasks are counted. Tasks due on a day
+// off count as due on the next working day.
+func Summarize(tasks []Task, now time.Time) Summary {
+	var summary Summary
+	today := dateOf(now)
+	wt := currentWorkingTime()
+
+	var walk func(tasks []Task)
+	walk = func(tasks []Task) {
+		for _, task := range tasks {
+			if task.Deleted {
+				continue
+			}
+			if !task.Completed && task.Kind != "tasks#taskList" {
+				summary.Active++
+				if task.InProgress {
+					summary.InProgress++
+				}
+				if !task.DueDate.IsZero() {
+					switch due := dateOf(wt.effectiveDue(task.DueDate)); {
+					case due.Equal(today):
+						summary.DueToday++
+					case due.Before(today):
+						summary.Overdue++
+					}
+				}
+			}
+			walk(task.Tasks)
+		}
+	}
+	walk(tasks)
+
+	return summary
+}
+
+// String formats the summary as a single line, e.g. "3 due today, 1 overdue,
+// 12 active, 2 in progress"
+func (s Summary) String() string {
+	return fmt.Sprintf("%d due today, %d overdue, %d active, %d in progress", s.DueToday, s.Overdue, s.Active, s.InProgress)
+}
+
+// dateOf drops the time of day, keeping the calendar date the time was recorded in
+func dateOf(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,108 @@
+package internal
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Fields of the new task form, in tab order
+const (
+	formTitle = iota
+	formNotes
+	formDue
+	formFieldCount
+)
+
+var formLabels = [formFieldCount]string{"Title", "Notes", "Due"}
+
+// taskForm collects the title, notes and due date of a new task at once
+type taskForm struct {
+	inputs [formFieldCount]textinput.Model
+	focus  int
+}
+
+func newTaskForm() taskForm {
+	var f taskForm
+	placeholders := [formFieldCount]string{
+		"Buy milk #errands !high",
+		"Optional",
+		"YYYY-MM-DD HH:mm, YYYY-MM-DD, or empty",
+	}
+	for i := range f.inputs {
+		f.inputs[i] = textinput.New()
+		f.inputs[i].Placeholder = placeholders[i]
+	}
+	f.inputs[formTitle].Focus()
+	return f
+}
+
+// move shifts focus by delta fields, wrapping around
+func (f *taskForm) move(delta int) {
+	f.inputs[f.focus].Blur()
+	f.focus = (f.focus + delta + formFieldCount) % formFieldCount
+	f.inputs[f.focus].Focus()
+}
+
+// task builds the task described by the form
+func (f *taskForm) task() (Task, error) {
+	task := ParseQuickAdd(f.inputs[formTitle].Value())
+	task.Notes = strings.TrimSpace(f.inputs[formNotes].Value())
+	if due := strings.TrimSpace(f.inputs[formDue].Value()); due != "" {
+		dueDate, err := parseDueDate(due, time.Time{})
+		if err != nil {
+			return Task{}, err
+		}
+		task.DueDate = dueDate
+	}
+	return task, nil
+}
+
+// updateForm handles keys while the new task form is open. Enter moves to
+// the next field and submits from the last one; ctrl+s submits from anywhere.
+func (m *model) updateForm(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.formActive = false
+		return nil
+	case "tab", "down":
+		m.form.move(1)
+		return nil
+	case "shift+tab", "up":
+		m.form.move(-1)
+		return nil
+	case "enter", "ctrl+s":
+		if msg.String() == "enter" && m.form.focus < formFieldCount-1 {
+			m.form.move(1)
+			return nil
+		}
+		if strings.TrimSpace(m.form.inputs[formTitle].Value()) == "" {
+			m.statusMsg = "A task needs a title"
+			return nil
+		}
+		task, err := m.form.task()
+		if err != nil {
+			m.statusMsg = err.Error()
+			return nil
+		}
+		m.formActive = false
+		m.createTask(task)
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.form.inputs[m.form.focus], cmd = m.form.inputs[m.form.focus].Update(msg)
+	return cmd
+}
+
+// renderForm draws the stacked form inputs
+func (m *model) renderForm() string {
+	var s strings.Builder
+	s.WriteString("New task (tab: next field, enter on Due or ctrl+s: save, esc: cancel)\n\n")
+	for i, input := range m.form.inputs {
+		s.WriteString(formLabels[i] + ": " + input.View() + "\n")
+	}
+	return s.String() + "\n"
+}
@@ -0,0 +1,123 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// numberOrder returns a copy of the tasks with Order set from their slice
+// position at every level, so the file records the arrangement explicitly
+func numberOrder(tasks []Task) []Task {
+	numbered := make([]Task, len(tasks))
+	for i, task := range tasks {
+		task.Order = i
+		task.Tasks = numberOrder(task.Tasks)
+		numbered[i] = task
+	}
+	return numbered
+}
+
+// sortByOrder arranges tasks at every level by their saved Order. The sort is
+// stable, so tasks that share an Order keep the order they were read in.
+func sortByOrder(tasks []Task) []Task {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].Order < tasks[j].Order
+	})
+	for i := range tasks {
+		tasks[i].Tasks = sortByOrder(tasks[i].Tasks)
+	}
+	return tasks
+}
+
+// moveTaskInList swaps the task under the cursor with its active neighbour
+// in direction (-1 up, 1 down) and saves the new order. Google gets the
+// move in the background.
+func (m *model) moveTaskInList(direction int) {
+	if m.treeView {
+		m.statusMsg = "Leave tree view to reorder tasks"
+		return
+	}
+	if m.sortMode != SortManual {
+		m.statusMsg = "Switch to manual sort (S) to reorder tasks"
+		return
+	}
+	parent := m.currentParent()
+	if parent == nil {
+		return
+	}
+
+	active, _ := m.getCurrentTasks()
+	target := m.cursor + direction
+	if m.cursor >= len(active) || target < 0 || target >= len(active) {
+		return
+	}
+	if active[m.cursor].Pinned != active[target].Pinned {
+		m.statusMsg = "Pinned tasks stay above the others"
+		return
+	}
+	from := indexOfTask(parent.Tasks, active[m.cursor].Id)
+	to := indexOfTask(parent.Tasks, active[target].Id)
+	if from < 0 || to < 0 {
+		return
+	}
+	parent.Tasks[from], parent.Tasks[to] = parent.Tasks[to], parent.Tasks[from]
+
+	if m.googleTasks != nil {
+		parentID := ""
+		if len(m.currentPath) > 1 {
+			parentID = parent.Id
+		}
+		previousID := ""
+		for i := to - 1; i >= 0; i-- {
+			if !parent.Tasks[i].Deleted {
+				previousID = parent.Tasks[i].Id
+				break
+			}
+		}
+		client, listID, taskID := m.googleTasks, m.currentPath[0].Id, parent.Tasks[to].Id
+		queueReorder(func() {
+			if _, err := client.moveTaskAfter(listID, taskID, parentID, previousID); err != nil {
+				reportSyncError(fmt.Errorf("error moving task: %v", err))
+			}
+		})
+	}
+
+	m.cursor = target
+	m.saveTasks()
+}
+
+var (
+	reorderMu      sync.Mutex
+	reorderQueue   []func() // Moves waiting to be sent to Google
+	reorderRunning bool     // runReorders is working through the queue
+)
+
+// queueReorder sends a move to Google in the background, after the moves
+// queued before it, as each one places a task relative to the others
+func queueReorder(move func()) {
+	reorderMu.Lock()
+	reorderQueue = append(reorderQueue, move)
+	start := !reorderRunning
+	reorderRunning = true
+	reorderMu.Unlock()
+	if start {
+		goSync(runReorders)
+	}
+}
+
+func runReorders() {
+	for {
+		reorderMu.Lock()
+		if len(reorderQueue) == 0 {
+			reorderRunning = false
+			reorderMu.Unlock()
+			return
+		}
+		move := reorderQueue[0]
+		reorderQueue = reorderQueue[1:]
+		reorderMu.Unlock()
+
+		move()
+	}
+}
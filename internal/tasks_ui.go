@@ -1,42 +1,54 @@
 package internal
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/lipgloss"
 	"golang.org/x/term"
 )
 
 // Task represents a task or subtask
 type Task struct {
-	Id            string    `json:"id"`
-	Title         string    `json:"title"`
-	Description   string    `json:"description"`
-	Notes         string    `json:"notes"`
-	Status        string    `json:"status"`
-	Completed     bool      `json:"completed"`
-	CreatedAt     time.Time `json:"createdAt"`
-	DueDate       time.Time `json:"dueDate"`
-	CompletedDate time.Time `json:"completedDate"`
-	Parent        string    `json:"parent"`
-	Position      string    `json:"position"`
-	Kind          string    `json:"kind"`
-	SelfLink      string    `json:"selfLink"`
-	Etag          string    `json:"etag"`
-	Updated       time.Time `json:"updated"`
-	Created       time.Time `json:"created"`
-	Deleted       bool      `json:"deleted"`
-	Tasks         []Task    `json:"tasks"`
-	Links         []struct {
-		Type string `json:"type"`
-		Desc string `json:"description"`
-		Link string `json:"link"`
-	} `json:"links"`
+	Id            string        `json:"id"`
+	Title         string        `json:"title"`
+	Description   string        `json:"description"`
+	Notes         string        `json:"notes"`
+	Status        string        `json:"status"`
+	Priority      string        `json:"priority"`
+	Tags          []string      `json:"tags"`
+	BlockedBy     []string      `json:"blockedBy"`
+	Completed     bool          `json:"completed"`
+	CreatedAt     time.Time     `json:"createdAt"`
+	DueDate       time.Time     `json:"dueDate"`
+	CompletedDate time.Time     `json:"completedDate"`
+	Parent        string        `json:"parent"`
+	Position      string        `json:"position"`
+	Kind          string        `json:"kind"`
+	SelfLink      string        `json:"selfLink"`
+	Etag          string        `json:"etag"`
+	Updated       time.Time     `json:"updated"`
+	Created       time.Time     `json:"created"`
+	Deleted       bool          `json:"deleted"`
+	DeletedAt     time.Time     `json:"deletedAt"`
+	TimeSpent     time.Duration `json:"timeSpent"`
+	TimerStarted  time.Time     `json:"timerStarted"`
+	Tasks         []Task        `json:"tasks"`
+	Links         []TaskLink    `json:"links"`
+	Color         string        `json:"color"`
+	Order         int           `json:"order"`
+	Estimate      time.Duration `json:"estimate"`
+	Pinned        bool          `json:"pinned"`
+	AutoCompleted bool          `json:"autoCompleted"`
+	InProgress    bool          `json:"inProgress"`
 }
 
 // Model represents the state of our Bubble Tea program
@@ -52,10 +64,60 @@ type model struct {
 	editingField   string // Field currently being edited: "title", "description", "notes", "due_date"
 	width          int     // Terminal width
 	height         int     // Terminal height
-	updateChan     chan []Task
-	refreshChan    chan struct{} // Channel for UI refresh signals
 	googleTasks    *GoogleTasksClient // Add Google Tasks client
 	currentListID  string            // Current Google Tasks list ID
+	statusMsg      string            // One-off message shown under the task list
+	treeView       bool              // Show subtasks inline instead of drilling in
+	expanded       map[string]bool   // Task IDs expanded in the tree view
+	focusMode      bool              // Show only the selected task
+	loading        bool              // A Google fetch is in flight
+	spinner        spinner.Model     // Shown in the status area while loading
+	dirty          bool              // Changes not yet written to disk
+	saveScheduled  bool              // A write is due saveDelay after the first unsaved change
+	quitting       bool              // q was pressed and is waiting for syncs to finish
+	quitDeadline   time.Time         // When quitting stops waiting for syncs
+	hideCompleted  bool              // Leave completed tasks out of the view
+	expandedCompleted bool           // List completed tasks instead of a count, toggled with c
+	details        viewport.Model    // Scrollable details panel
+	detailsTaskID  string            // Task the details panel was scrolled on
+	detailsFocus   bool              // Keys scroll the details panel instead of the list
+	sortMode       string            // How the current list is sorted
+	accent         string            // Cursor color of the current list
+	timerTicking   bool              // A timerTick is scheduled
+	snoozing       bool              // The snooze menu is open
+	form           taskForm          // Multi-field new task form
+	formActive     bool              // The new task form is open
+	showLegend     bool              // Show the color legend under the list
+	notified       map[string]bool   // Overdue tasks already announced
+	moveTarget     string            // Destination list ID awaiting confirmation
+	reviewing      bool              // Review mode is walking through reviewItems
+	reviewItems    []reviewItem      // Tasks queued for review
+	reviewIndex    int               // Position in reviewItems
+	showStats      bool              // Show the Google API call counters
+	showErrorLog   bool              // Show the error log instead of the list
+	errorLogView   viewport.Model    // Scroll position of the error log
+	drafts         map[string]string // Text edits left with esc, by action and task ID
+	calendarMode   bool              // Show the month calendar instead of the list
+	calendarDate   time.Time         // Day focused in the calendar
+	calendarDay    bool              // Picking one of the focused day's tasks
+	calendarCursor int               // Selected task of the focused day
+	datePicker     datePicker        // Due date picker opened by t
+	datePicking    bool              // The due date picker is open
+	showNext       bool              // Show the next action of every list instead of the list
+	nextCursor     int               // Selected next action
+	linkFrom       string            // Task marked with B as a pending blocker
+	templates      map[string]TaskTemplate // Templates read when the U prompt opened
+	showTrash      bool              // Show the trash instead of the list
+	trash          []trashedTask     // Deleted tasks, newest first
+	trashCursor    int               // Selected trashed task
+	trashConfirm   bool              // X was pressed once to empty the trash
+	switcherCursor int               // Selected match in the list switcher
+	conflicts      []ConflictError   // Sync conflicts waiting to be resolved
+	showConflict   bool              // Show the first conflict instead of the list
+	remoteChanges  int               // Tasks changed elsewhere since the last keypress
+	remoteUnannounced int            // Remote changes waiting for the next notification
+	remoteNotifyScheduled bool       // A remoteNotifyMsg is on its way
+	windowTitle    string            // Terminal title last set, with TerminalTitle on
 }
 
 // NewModel initializes the Bubble Tea model with tasks
@@ -64,17 +126,6 @@ func NewModel(tasks []Task, client *GoogleTasksClient) model {
 	ti.Placeholder = "Enter task title..."
 	ti.Focus()
 
-	// Get the first task list ID
-	taskLists, err := client.service.Tasklists.List().Do()
-	var currentListID string
-	if err == nil && len(taskLists.Items) > 0 {
-		currentListID = taskLists.Items[0].Id
-	}
-
-	// Initialize channels
-	updateChan := make(chan []Task, 10)
-	refreshChan := make(chan struct{}, 1)
-
 	// Split initial tasks
 	active, completed := splitTasks(tasks)
 
@@ -82,29 +133,65 @@ func NewModel(tasks []Task, client *GoogleTasksClient) model {
 		tasks:          active,
 		completedTasks: completed,
 		input:         ti,
-		updateChan:    updateChan,
-		refreshChan:   refreshChan,
 		googleTasks:   client,
-		currentListID: currentListID,
+		expanded:      make(map[string]bool),
+		notified:      make(map[string]bool),
+		loading:       client != nil,
+		spinner:       newLoadingSpinner(),
+		details:       viewport.New(0, 0),
 	}
-
-	// Start update handler
-	go m.handleUpdates()
+	if config := GetGlobalConfig(); config == nil || !config.CollapseCompleted {
+		m.expandedCompleted = true
+	}
+	m.applyListSettings()
+	m.timerTicking = m.runningTimer() != nil
 
 	return m
 }
 
-// getCurrentTasks returns the current level's tasks based on currentPath
+// getCurrentTasks returns the current level's visible tasks based on currentPath
 func (m *model) getCurrentTasks() ([]Task, []Task) {
+	active, completed := m.levelTasks()
+	if len(m.currentPath) == 0 {
+		active = orderLists(active, m.sortMode)
+	} else {
+		active = pinnedFirst(sortTasks(active, m.sortMode))
+	}
+	if m.hideCompleted || !m.expandedCompleted {
+		return active, nil
+	}
+	return active, sortCompleted(completed)
+}
+
+// hiddenCompletedCount returns how many completed tasks the filter, or
+// collapsing them, is hiding at this level
+func (m *model) hiddenCompletedCount() int {
+	if !m.hideCompleted && m.expandedCompleted {
+		return 0
+	}
+	_, completed := m.levelTasks()
+	return len(completed)
+}
+
+// levelTasks returns all tasks at the current level, including hidden ones
+func (m *model) levelTasks() ([]Task, []Task) {
 	if len(m.currentPath) == 0 {
-		return m.tasks, m.completedTasks
+		active, _ := splitTasks(m.tasks)
+		_, completed := splitTasks(m.completedTasks)
+		return active, completed
 	}
 
-	parentTask := &m.currentPath[len(m.currentPath)-1]
+	parentTask := m.currentParent()
+	if parentTask == nil {
+		parentTask = &m.currentPath[len(m.currentPath)-1]
+	}
 	active := make([]Task, 0)
 	completed := make([]Task, 0)
 
 	for _, task := range parentTask.Tasks {
+		if task.Deleted {
+			continue
+		}
 		if task.Completed {
 			completed = append(completed, task)
 		} else {
@@ -115,136 +202,449 @@ func (m *model) getCurrentTasks() ([]Task, []Task) {
 	return active, completed
 }
 
+// currentParent returns the live task in m.tasks that the current path points at
+func (m *model) currentParent() *Task {
+	if len(m.currentPath) == 0 {
+		return nil
+	}
+
+	currentTask := &m.tasks
+	var taskPtr *Task
+	for i, pathTask := range m.currentPath {
+		taskPtr = nil
+		for j := range *currentTask {
+			if (*currentTask)[j].Id == pathTask.Id {
+				taskPtr = &(*currentTask)[j]
+				break
+			}
+		}
+		if taskPtr == nil {
+			return nil
+		}
+		if i < len(m.currentPath)-1 {
+			currentTask = &taskPtr.Tasks
+		}
+	}
+	return taskPtr
+}
+
+// selectedTask returns the live task under the cursor so edits persist in m.tasks
+func (m *model) selectedTask() *Task {
+	if m.treeView {
+		rows := m.treeRows()
+		if m.cursor >= len(rows) {
+			return nil
+		}
+		return m.findTask(rows[m.cursor].task.Id)
+	}
+
+	active, completed := m.getCurrentTasks()
+	var id string
+	if m.cursor < len(active) {
+		id = active[m.cursor].Id
+	} else if m.cursor-len(active) < len(completed) {
+		id = completed[m.cursor-len(active)].Id
+	} else {
+		return nil
+	}
+	return m.findTask(id)
+}
+
+// findTask looks a task up by ID across both the active and completed trees
+func (m *model) findTask(id string) *Task {
+	if task := findTask(m.tasks, id); task != nil {
+		return task
+	}
+	return findTask(m.completedTasks, id)
+}
+
+// toggleCompletion flips a task between active and completed. Top-level tasks
+// move between m.tasks and m.completedTasks, nested ones just change state.
+// Subtasks follow the parent as described on cascadeCompletion.
+func (m *model) toggleCompletion(id string) {
+	var task *Task
+	if i := indexOfTask(m.tasks, id); i >= 0 {
+		moved := m.tasks[i]
+		m.tasks = removeTask(m.tasks, moved)
+		m.completedTasks = append(m.completedTasks, moved)
+		task = &m.completedTasks[len(m.completedTasks)-1]
+	} else if i := indexOfTask(m.completedTasks, id); i >= 0 {
+		moved := m.completedTasks[i]
+		m.completedTasks = removeTask(m.completedTasks, moved)
+		m.tasks = append(m.tasks, moved)
+		task = &m.tasks[len(m.tasks)-1]
+	} else if task = m.findTask(id); task == nil {
+		return
+	}
+
+	now := time.Now()
+	setCompleted(task, !task.Completed, now)
+	changed := append([]Task{*task}, cascadeCompletion(task, now)...)
+	all := m.allTasks()
+	if parents := propagateCompletion(all, id, now); len(parents) > 0 {
+		changed = append(changed, parents...)
+		m.refileRoots(all)
+	}
+
+	for _, t := range changed {
+		m.syncToGoogle(t)
+	}
+	m.saveTasks()
+}
+
+// createTask fills in the bookkeeping fields of a new task, creates it in
+// Google Tasks when connected and adds it to the current level
+func (m *model) createTask(newTask Task) {
+	now := time.Now()
+	newTask.CreatedAt = now
+	newTask.Created = now
+	newTask.Updated = now
+	newTask.Status = "needsAction"
+	newTask.Kind = "tasks#task"
+
+	// Set parent ID if we're in a sublist
+	if len(m.currentPath) > 0 {
+		currentTask := m.currentPath[len(m.currentPath)-1]
+		// Only set parent if we're not at the root
+		if currentTask.Kind != "tasks#taskList" {
+			newTask.Parent = currentTask.Id
+		}
+	}
+
+	createdTask := newTask
+	if m.googleTasks != nil {
+		// Create task in Google Tasks first
+		listID := m.currentListID
+		if listID == "" {
+			// If currentListID is empty, try to get it again
+			var err error
+			listID, err = m.googleTasks.firstListID()
+			if err != nil {
+				m.statusMsg = fmt.Sprintf("Sync failed: %v", err)
+				return
+			}
+			m.currentListID = listID
+		}
+
+		logDebug("Creating task in list %s with parent %s", listID, newTask.Parent)
+		var err error
+		if len(newTask.Tasks) > 0 {
+			// Tasks from templates come with subtasks
+			createdTask, err = recreateTree(m.googleTasks, listID, newTask.Parent, newTask)
+		} else {
+			createdTask, err = m.googleTasks.CreateTask(newTask, listID)
+		}
+		if err != nil {
+			m.statusMsg = fmt.Sprintf("Sync failed: %v", err)
+			return
+		}
+	} else {
+		// Local mode only needs unique IDs
+		createdTask.Id = generateID()
+		assignSubtaskIDs(&createdTask)
+	}
+
+	// Just add the task to wherever we currently are
+	if len(m.currentPath) == 0 {
+		m.tasks = append(m.tasks, createdTask)
+	} else if parentTask := m.currentParent(); parentTask != nil {
+		// Add to the live parent so nested levels persist
+		parentTask.Tasks = append(parentTask.Tasks, createdTask)
+	}
+	active, _ := m.getCurrentTasks()
+	if i := indexOfTask(active, createdTask.Id); i >= 0 {
+		m.cursor = i
+	}
+
+	m.saveTasks()
+}
+
+// deleteTask removes a task from wherever it lives in the tree and keeps
+// the cursor within the remaining rows
+func (m *model) deleteTask(id string) {
+	task := m.findTask(id)
+	if task == nil {
+		return
+	}
+	if err := m.trashTask(id, time.Now()); err != nil {
+		logError("Error moving task to trash: %v", err)
+	}
+	deleted := *task
+	deleted.Status = "deleted"
+	m.syncToGoogle(deleted)
+
+	if UseGoogleTasks.Load() {
+		// Keep a tombstone so another device's copy can't bring it back
+		now := time.Now()
+		task.Deleted = true
+		task.DeletedAt = now
+		if err := recordTombstone(id, now); err != nil {
+			logError("Error recording tombstone: %v", err)
+		}
+	} else {
+		m.tasks = removeTaskByID(m.tasks, id)
+		m.completedTasks = removeTaskByID(m.completedTasks, id)
+	}
+
+	if count := m.visibleCount(); m.cursor >= count {
+		m.cursor = count - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+
+	// Save tasks after deletion
+	m.saveTasks()
+}
+
+// isBlocked reports whether the task still waits on unfinished blockers
+func (m *model) isBlocked(task Task) bool {
+	return isBlocked(task, m.findTask)
+}
+
 func (m *model) updateTerminalSize() {
 	width, height, _ := term.GetSize(int(os.Stdout.Fd()))
 	m.width = width
 	m.height = height
 }
 
-// Init starts the program
-func (m model) Init() tea.Cmd {
-	return m.waitForRefresh
+// syncErrorMsg reports a background Google sync failure without leaving the UI
+type syncErrorMsg struct {
+	err error
 }
 
-// waitForRefresh waits for refresh signals
-func (m model) waitForRefresh() tea.Msg {
-	return <-m.refreshChan
+// uiProgram is the running program, used to deliver messages from background
+// goroutines. It is nil outside the UI.
+var uiProgram atomic.Pointer[tea.Program]
+
+// sendToUI hands msg to the Update loop of the running program. Background
+// goroutines never touch the model directly; everything they learn arrives
+// this way. It reports false outside the UI.
+func sendToUI(msg tea.Msg) bool {
+	if p := uiProgram.Load(); p != nil {
+		p.Send(msg)
+		return true
+	}
+	return false
 }
 
-// handleUpdates processes task updates in the background
-func (m *model) handleUpdates() {
-	for tasks := range m.updateChan {
-		m.tasks = tasks
-		active, completed := splitTasks(tasks)
-		m.tasks = active
-		m.completedTasks = completed
-		tea.Println("Tasks updated from Google")
-		
-		// Send refresh signal
-		select {
-		case m.refreshChan <- struct{}{}:
-		default:
-			// Channel is full, skip refresh
-		}
+// reportSyncError logs a failed sync and shows it in the status line
+func reportSyncError(err error) {
+	logError("Error syncing with Google Tasks: %v", err)
+	sendToUI(syncErrorMsg{err: err})
+}
+
+// tasksUpdatedMsg carries a fresh task tree from outside the Update loop
+type tasksUpdatedMsg []Task
+
+// Init starts the program
+func (m model) Init() tea.Cmd {
+	cmds := []tea.Cmd{autosaveTick()}
+	if m.timerTicking {
+		// A timer was left running in a previous session
+		cmds = append(cmds, timerTick())
+	}
+	if m.loading {
+		// Show the cached tasks while the fresh ones are fetched
+		cmds = append(cmds, m.spinner.Tick, m.fetchGoogleCmd)
 	}
+	if overdueNotificationsEnabled() {
+		// Check right away, then on every tick
+		cmds = append(cmds, func() tea.Msg { return overdueCheckMsg(time.Now()) })
+	}
+	return tea.Batch(cmds...)
 }
 
-// Update handles keypresses and updates the state of the UI
+// Update handles a message, then schedules a write if it changed the tasks
+// and retitles the terminal if the due counts changed
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	updated, cmd := m.update(msg)
+	next, ok := updated.(model)
+	if !ok {
+		return updated, cmd
+	}
+	if save := next.scheduleSave(); save != nil {
+		cmd = tea.Batch(cmd, save)
+	}
+	if title := next.updateWindowTitle(); title != nil {
+		cmd = tea.Batch(cmd, title)
+	}
+	return next, cmd
+}
+
+// update handles keypresses and updates the state of the UI
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		// Clear leftovers from a larger layout before the next frame
+		return m, tea.ClearScreen
+
+	case spinner.TickMsg:
+		if !m.loading {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case googleFetchDoneMsg:
+		m.loading = false
+		if msg.tasks != nil {
+			m.replaceTasks(msg.tasks)
+		}
+		if msg.err != nil {
+			logError("Error syncing with Google Tasks: %v", msg.err)
+			m.statusMsg = fmt.Sprintf("Sync failed: %v", msg.err)
+			return m, nil
+		}
+		if m.currentListID == "" {
+			m.currentListID = msg.listID
+		}
+		m.statusMsg = "Synced with Google Tasks"
+		return m, nil
+
+	case timerTickMsg:
+		if m.runningTimer() == nil {
+			m.timerTicking = false
+			return m, nil
+		}
+		return m, timerTick()
+
+	case quitCheckMsg:
+		return m, m.checkQuit(time.Time(msg))
+
+	case saveFlushMsg:
+		m.saveScheduled = false
+		m.autosave()
+		return m, nil
+
+	case autosaveMsg:
+		m.autosave()
+		return m, autosaveTick()
+
+	case overdueCheckMsg:
+		return m, tea.Batch(m.notifyOverdue(time.Time(msg)), overdueTick())
+
+	case completeTaskMsg:
+		if task := m.findTask(msg.id); task != nil && !task.Completed {
+			title := task.Title
+			m.toggleCompletion(msg.id)
+			m.statusMsg = "Completed " + title
+			return m, completionBell()
+		}
+		return m, nil
+
+	case modeSwitchedMsg:
+		return m, m.finishModeSwitch(msg)
+
+	case syncErrorMsg:
+		var conflict *ConflictError
+		if errors.As(msg.err, &conflict) {
+			m.addConflict(*conflict)
+			return m, nil
+		}
+		m.statusMsg = fmt.Sprintf("Sync failed: %v", msg.err)
+		return m, nil
+
+	case tasksUpdatedMsg:
+		cmd := m.noteRemoteChanges(msg)
+		m.replaceTasks(msg)
+		return m, cmd
+
+	case remoteNotifyMsg:
+		return m, m.announceRemoteChanges()
+
+	case taskMovedMsg:
+		m.finishMove(msg)
+		return m, nil
+
+	case taskRestoredMsg:
+		m.finishRestore(msg)
+		return m, nil
+
+	case taskPositionedMsg:
+		m.finishReposition(msg)
 		return m, nil
 
-	case struct{}: // Refresh message
-		return m, m.waitForRefresh
-	
+	case configEditedMsg:
+		m.reloadConfig(msg)
+		return m, tea.ClearScreen
+
 	case tea.KeyMsg:
+		m.statusMsg = ""
+		m.remoteChanges = 0
+
+		if m.formActive {
+			return m, m.updateForm(msg)
+		}
+
+		if m.datePicking {
+			m.updateDatePicker(msg)
+			return m, nil
+		}
+
+		// Tab switches from typing a due date to the picker
+		if m.inputActive && m.inputAction == "due_date" && msg.String() == "tab" {
+			m.inputActive = false
+			m.input.Blur()
+			m.openDueDateInput(true)
+			if task := m.selectedTask(); task != nil {
+				if due, err := parseDueDate(m.input.Value(), task.DueDate); err == nil {
+					m.datePicker.value = due
+				}
+			}
+			return m, nil
+		}
+
+		// The list switcher moves its selection with the arrow keys
+		if m.inputActive && m.inputAction == "switch_list" && m.switcherKey(msg.String()) {
+			return m, nil
+		}
+
 		// If input is active, handle all text input
 		if m.inputActive {
 			switch msg.String() {
 			case "esc":
-				m.inputActive = false
-				m.input.Blur()
+				// With EscSavesInput, esc saves text edits like enter
+				if m.draftKey() != "" && escSavesInput() {
+					return m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+				}
+				m.cancelInput()
+				return m, nil
+			case "ctrl+r":
+				m.restoreDraft()
 				return m, nil
 			case "enter":
+				m.clearDraft()
 				// Save the input based on action type
-				active, completed := m.getCurrentTasks()
 				switch m.inputAction {
 				case "description", "notes":
-					if len(m.currentPath) == 0 {
-						if m.cursor < len(active) {
-							if m.inputAction == "description" {
-								active[m.cursor].Description = m.input.Value()
-								active[m.cursor].Updated = time.Now()
-								m.syncToGoogle(active[m.cursor])
-							} else {
-								active[m.cursor].Notes = m.input.Value()
-								active[m.cursor].Updated = time.Now()
-								m.syncToGoogle(active[m.cursor])
-							}
+					if task := m.selectedTask(); task != nil {
+						if m.inputAction == "description" {
+							task.Description = m.input.Value()
 						} else {
-							completedIdx := m.cursor - len(active)
-							if m.inputAction == "description" {
-								completed[completedIdx].Description = m.input.Value()
-								completed[completedIdx].Updated = time.Now()
-								m.syncToGoogle(completed[completedIdx])
-							} else {
-								completed[completedIdx].Notes = m.input.Value()
-								completed[completedIdx].Updated = time.Now()
-								m.syncToGoogle(completed[completedIdx])
-							}
-						}
-					} else {
-						parentTask := &m.currentPath[len(m.currentPath)-1]
-						if m.cursor < len(parentTask.Tasks) {
-							if m.inputAction == "description" {
-								parentTask.Tasks[m.cursor].Description = m.input.Value()
-								parentTask.Tasks[m.cursor].Updated = time.Now()
-								m.syncToGoogle(parentTask.Tasks[m.cursor])
-							} else {
-								parentTask.Tasks[m.cursor].Notes = m.input.Value()
-								parentTask.Tasks[m.cursor].Updated = time.Now()
-								m.syncToGoogle(parentTask.Tasks[m.cursor])
-							}
+							task.Notes = m.input.Value()
 						}
+						task.Updated = time.Now()
+						m.syncToGoogle(*task)
 					}
-					if err := SaveTasks(m.tasks); err != nil {
-						fmt.Printf("Error saving tasks: %v\n", err)
-					}
+					m.saveTasks()
 				case "rename":
-					if len(m.currentPath) == 0 {
-						if m.cursor < len(active) {
-							active[m.cursor].Title = m.input.Value()
-							active[m.cursor].Updated = time.Now()
-							if active[m.cursor].Status == "" {
-								active[m.cursor].Status = "needsAction"
-							}
-							m.syncToGoogle(active[m.cursor])
-						} else {
-							completedIdx := m.cursor - len(active)
-							completed[completedIdx].Title = m.input.Value()
-							completed[completedIdx].Updated = time.Now()
-							if completed[completedIdx].Status == "" {
-								completed[completedIdx].Status = "completed"
-							}
-							m.syncToGoogle(completed[completedIdx])
-						}
-					} else {
-						parentTask := &m.currentPath[len(m.currentPath)-1]
-						if m.cursor < len(parentTask.Tasks) {
-							parentTask.Tasks[m.cursor].Title = m.input.Value()
-							parentTask.Tasks[m.cursor].Updated = time.Now()
-							if parentTask.Tasks[m.cursor].Status == "" {
-								parentTask.Tasks[m.cursor].Status = "needsAction"
+					if task := m.selectedTask(); task != nil {
+						task.Title = m.input.Value()
+						task.Updated = time.Now()
+						if task.Status == "" {
+							if task.Completed {
+								task.Status = "completed"
+							} else {
+								task.Status = "needsAction"
 							}
-							m.syncToGoogle(parentTask.Tasks[m.cursor])
 						}
+						m.syncToGoogle(*task)
 					}
-					if err := SaveTasks(m.tasks); err != nil {
-						fmt.Printf("Error saving tasks: %v\n", err)
-					}
+					m.saveTasks()
 				case "due_date":
 					dateStr := m.input.Value()
 					if dateStr == "" {
@@ -253,247 +653,476 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						return m, nil
 					}
 
-					// Try parsing with different formats
-					var dueDate time.Time
-					var err error
-					formats := []string{
-						"2006-01-02 15:04",
-						"2006-01-02",
-						"01/02/2006",
-						"02-01-2006",
-					}
+					task := m.selectedTask()
+					if task == nil {
+						break
+					}
+					dueDate, err := parseDueDate(dateStr, task.DueDate)
+					if err != nil {
+						m.statusMsg = err.Error()
+						return m, nil
+					}
+
+					task.DueDate = dueDate
+					task.Updated = time.Now()
+					m.saveTasks()
+					m.syncToGoogle(*task)
+				case "due_time":
+					task := m.selectedTask()
+					if task == nil || task.DueDate.IsZero() {
+						break
+					}
+					dueDate, err := parseDueTime(m.input.Value(), task.DueDate)
+					if err != nil {
+						m.statusMsg = err.Error()
+						return m, nil
+					}
+
+					task.DueDate = dueDate
+					task.Updated = time.Now()
+					m.saveTasks()
+					m.syncToGoogle(*task)
+				case "new_task":
+					// Pull tags, priority and due date out of the typed title
+					m.createTask(ParseQuickAdd(m.input.Value()))
+
+					m.inputActive = false
+					m.input.Blur()
+					return m, nil
+				case "blocked_by":
+					if task := m.selectedTask(); task != nil {
+						blockerID := strings.TrimSpace(m.input.Value())
+						if blockerID == "" {
+							task.BlockedBy = nil
+						} else if err := addBlocker(task, blockerID, m.findTask); err != nil {
+							m.statusMsg = "Can't add dependency: " + err.Error()
+							break
+						}
+						task.Updated = time.Now()
+						m.syncToGoogle(*task)
+						m.saveTasks()
+					}
+				case "estimate":
+					m.setEstimate(m.input.Value())
+				case "checklist":
+					m.toggleChecklist(m.input.Value())
+				case "template":
+					m.createFromTemplate(m.templates, m.input.Value())
+				case "add_link":
+					m.addLink(m.input.Value())
+				case "attach":
+					m.attach(m.input.Value())
+				case "open_link":
+					m.openLink(m.input.Value())
+				case "jump":
+					m.inputActive = false
+					m.input.Blur()
+					m.jumpTo(m.input.Value())
+					return m, tea.ClearScreen
+				case "palette":
+					m.inputActive = false
+					m.input.Blur()
+					return m.runCommand(m.input.Value())
+				case "switch_list":
+					m.switchToSelectedList()
+				case "move":
+					list, ok := m.resolveMoveTarget(m.input.Value())
+					if !ok {
+						m.statusMsg = "No list matches " + m.input.Value()
+						break
+					}
+					m.moveTarget = list.Id
+					m.inputAction = "move_confirm"
+					m.input.Placeholder = "Type 'yes' to move to " + list.Title
+					m.input.SetValue("")
+					return m, nil
+				case "move_confirm":
+					if m.input.Value() == "yes" {
+						if task := m.selectedTask(); task != nil {
+							m.moveTask(task.Id, m.moveTarget)
+						}
+					}
+				case "complete":
+					if answer := strings.ToLower(strings.TrimSpace(m.input.Value())); answer == "y" || answer == "yes" {
+						if task := m.selectedTask(); task != nil && !task.Completed {
+							m.toggleCompletion(task.Id)
+							m.inputActive = false
+							m.input.Blur()
+							return m, completionBell()
+						}
+					}
+				case "dedupe":
+					if m.input.Value() == "yes" {
+						m.removeDuplicates()
+					}
+				case "clear_completed":
+					if m.input.Value() == "yes" {
+						m.clearCompleted()
+					}
+				case "delete":
+					if m.input.Value() == "yes" {
+						if task := m.selectedTask(); task != nil {
+							m.deleteTask(task.Id)
+						}
+					}
+					m.inputActive = false
+					m.input.Blur()
+					return m, nil
+				}
+				m.inputActive = false
+				m.input.Blur()
+				return m, nil
+			default:
+				var cmd tea.Cmd
+				m.input, cmd = m.input.Update(msg)
+				return m, cmd
+			}
+		}
+
+		// The snooze menu takes a single key, then closes
+		if m.snoozing {
+			m.snoozing = false
+			m.snooze(msg.String())
+			return m, nil
+		}
+
+		// Review mode takes over the keys it knows, passing the rest on
+		if m.reviewing {
+			if handled, cmd := m.reviewKey(msg.String()); handled {
+				return m, cmd
+			}
+		}
+
+		// The calendar takes every key until it is closed
+		if m.calendarMode {
+			if key := msg.String(); key == "q" || key == "Q" || key == "ctrl+c" {
+				return m, m.quit(key)
+			}
+			m.calendarKey(msg.String())
+			return m, nil
+		}
+
+		// So do the next actions view, the trash and sync conflicts
+		if m.showConflict {
+			if key := msg.String(); key == "q" || key == "Q" || key == "ctrl+c" {
+				return m, m.quit(key)
+			}
+			m.conflictKey(msg.String())
+			return m, nil
+		}
+		if m.showTrash {
+			if key := msg.String(); key == "q" || key == "Q" || key == "ctrl+c" {
+				return m, m.quit(key)
+			}
+			m.trashKey(msg.String())
+			return m, nil
+		}
+		if m.showNext {
+			if key := msg.String(); key == "q" || key == "Q" || key == "ctrl+c" {
+				return m, m.quit(key)
+			}
+			m.nextActionsKey(msg.String())
+			return m, nil
+		}
+
+		// The error log takes every key until it is closed
+		if m.showErrorLog {
+			if key := msg.String(); key == "q" || key == "Q" || key == "ctrl+c" {
+				return m, m.quit(key)
+			}
+			m.errorLogKey(msg.String())
+			return m, nil
+		}
+
+		// While the details panel has focus, keys scroll it instead of the list
+		if m.detailsFocus {
+			switch msg.String() {
+			case "tab", "esc":
+				m.detailsFocus = false
+			case "q", "Q", "ctrl+c":
+				return m, m.quit(msg.String())
+			default:
+				m.scrollDetails(msg.String())
+			}
+			return m, nil
+		}
+
+		// Handle navigation and shortcuts when input is not active
+		switch msg.String() {
+		case "L":
+			if task := m.selectedTask(); task != nil {
+				m.inputActive = true
+				m.inputAction = "add_link"
+				m.input.Placeholder = "https://example.com Optional description"
+				m.input.SetValue("")
+				m.input.Focus()
+			}
+
+		case "a":
+			if task := m.selectedTask(); task != nil {
+				m.inputActive = true
+				m.inputAction = "attach"
+				m.input.Placeholder = "~/notes/plan.md Optional description"
+				m.input.SetValue("")
+				m.input.Focus()
+			}
+
+		case "O":
+			if task := m.selectedTask(); task != nil {
+				switch len(task.Links) {
+				case 0:
+					m.statusMsg = "This task has no links, add one with L or a"
+				case 1:
+					m.openLink("1")
+				default:
+					m.inputActive = true
+					m.inputAction = "open_link"
+					m.input.Placeholder = fmt.Sprintf("Link number (1-%d)", len(task.Links))
+					m.input.SetValue("")
+					m.input.Focus()
+				}
+			}
+
+		case "D":
+			dups := FindDuplicates(m.allTasks())
+			if len(dups) == 0 {
+				m.statusMsg = "No duplicate tasks found"
+				return m, nil
+			}
+			m.inputActive = true
+			m.inputAction = "dedupe"
+			m.input.Placeholder = fmt.Sprintf("Type 'yes' to remove %d duplicate(s)", len(dups))
+			m.input.SetValue("")
+			m.input.Focus()
+			return m, nil
+
+		case "X":
+			m.promptClearCompleted()
+			return m, nil
+
+		case "F":
+			if task := m.selectedTask(); task != nil {
+				task.Color = nextColorFlag(task.Color)
+				task.Updated = time.Now()
+				m.syncToGoogle(*task)
+				m.saveTasks()
+			}
+			return m, nil
+
+		case "W":
+			m.startReview()
+			if !m.reviewing {
+				m.statusMsg = "Nothing to review"
+			}
+			return m, nil
+
+		case "I":
+			m.showStats = !m.showStats
+			return m, nil
+
+		case "E":
+			m.openErrorLog()
+			return m, nil
+
+		case "C":
+			m.openCalendar()
+			return m, nil
+
+		case "A":
+			m.openNextActions()
+			return m, nil
+
+		case "u":
+			m.openTrash()
+			return m, nil
+
+		case "]":
+			m.jumpToDue(1)
+			return m, nil
+
+		case "<", ">":
+			m.repositionTask(msg.String() == "<")
+			return m, nil
+
+		case "[":
+			m.jumpToDue(-1)
+			return m, nil
+
+		case "!":
+			if len(m.conflicts) == 0 {
+				m.statusMsg = "No sync conflicts"
+				return m, nil
+			}
+			m.showConflict = true
+			return m, nil
+
+		case "ctrl+p":
+			m.openListSwitcher()
+			return m, nil
+
+		case "G":
+			return m, m.switchMode()
+
+		case "m":
+			if m.selectedTask() == nil || len(m.currentPath) == 0 {
+				return m, nil
+			}
+			if len(m.moveTargets()) == 0 {
+				m.statusMsg = "There is no other list to move to"
+				return m, nil
+			}
+			m.inputActive = true
+			m.inputAction = "move"
+			m.input.Placeholder = "List number or name"
+			m.input.SetValue("")
+			m.input.Focus()
+
+		case "g":
+			m.inputActive = true
+			m.inputAction = "jump"
+			m.input.Placeholder = "Task ID or number"
+			m.input.SetValue("")
+			m.input.Focus()
+
+		case ":":
+			m.inputActive = true
+			m.inputAction = "palette"
+			m.input.Placeholder = "Type a command..."
+			m.input.SetValue("")
+			m.input.Focus()
+
+		case "tab":
+			if _, width := m.panelWidths(); width > 0 {
+				m.detailsFocus = true
+			}
+
+		case "down", "j":
+			if m.cursor < m.visibleCount()-1 {
+				m.cursor++
+				return m, tea.ClearScreen
+			}
+
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+				return m, tea.ClearScreen
+			}
+
+		case "H":
+			m.hideCompleted = !m.hideCompleted
+			showCompleted := !m.hideCompleted
+			m.saveListSetting(func(s *listSettings) { s.ShowCompleted = &showCompleted })
+			if count := m.visibleCount(); m.cursor >= count {
+				m.cursor = max(count-1, 0)
+			}
+
+		case "c":
+			m.expandedCompleted = !m.expandedCompleted
+			if count := m.visibleCount(); m.cursor >= count {
+				m.cursor = max(count-1, 0)
+			}
+
+		case "p":
+			if len(m.currentPath) == 0 {
+				m.togglePinnedList()
+			} else {
+				m.togglePinnedTask()
+			}
+
+		case "K":
+			if len(m.currentPath) == 0 {
+				m.moveList(-1)
+			} else {
+				m.moveTaskInList(-1)
+			}
 
-					for _, format := range formats {
-						dueDate, err = time.Parse(format, dateStr)
-						if err == nil {
-							break
-						}
-					}
+		case "J":
+			if len(m.currentPath) == 0 {
+				m.moveList(1)
+			} else {
+				m.moveTaskInList(1)
+			}
 
-					if err != nil {
-						tea.Printf("Invalid date format. Please use one of:\nYYYY-MM-DD HH:mm\nYYYY-MM-DD\nMM/DD/YYYY\nDD-MM-YYYY")
-						return m, nil
-					}
+		case "s":
+			return m, m.toggleTimer()
 
-					var task *Task
-					if len(m.currentPath) == 0 {
-						active, completed := m.getCurrentTasks()
-						if m.cursor < len(active) {
-							task = &active[m.cursor]
-						} else {
-							completedIdx := m.cursor - len(active)
-							task = &completed[completedIdx]
-						}
-					} else {
-						parentTask := &m.currentPath[len(m.currentPath)-1]
-						if m.cursor < len(parentTask.Tasks) {
-							task = &parentTask.Tasks[m.cursor]
-						}
-					}
+		case "R":
+			return m, m.manualSync()
 
-					if task != nil {
-						task.DueDate = dueDate
-						task.Updated = time.Now()
-						if err := SaveTasks(m.tasks); err != nil {
-							fmt.Printf("Error saving tasks: %v\n", err)
-						}
-						m.syncToGoogle(*task)
-					}
-				case "new_task":
-					now := time.Now()
-					newTask := Task{
-						Title:     m.input.Value(),
-						CreatedAt: now,
-						Created:   now,
-						Updated:   now,
-						Status:    "needsAction",
-						Kind:      "tasks#task",
-						Notes:     "",
-					}
+		case "?":
+			m.showLegend = !m.showLegend
+			return m, nil
 
-					// Create task in Google Tasks first
-					listID := m.currentListID
-					if listID == "" {
-						// If currentListID is empty, try to get it again
-						taskLists, err := m.googleTasks.service.Tasklists.List().Do()
-						if err != nil {
-							fmt.Printf("Error getting task lists: %v\n", err)
-							return m, nil
-						}
-						if len(taskLists.Items) > 0 {
-							listID = taskLists.Items[0].Id
-							m.currentListID = listID
-						} else {
-							fmt.Printf("Error: No task lists found\n")
-							return m, nil
-						}
-					}
+		case "N":
+			m.form = newTaskForm()
+			m.formActive = true
+			return m, textinput.Blink
 
-					// Set parent ID if we're in a sublist
-					if len(m.currentPath) > 0 {
-						currentTask := m.currentPath[len(m.currentPath)-1]
-						// Only set parent if we're not at the root
-						if currentTask.Kind != "tasks#taskList" {
-							newTask.Parent = currentTask.Id
-						}
-					}
+		case "w":
+			if m.selectedTask() != nil {
+				m.snoozing = true
+			}
 
-					fmt.Printf("Debug: Creating task in list %s with parent %s\n", listID, newTask.Parent)
-					createdTask, err := m.googleTasks.CreateTask(newTask, listID)
-					if err != nil {
-						fmt.Printf("Error creating task in Google Tasks: %v\n", err)
-						return m, nil
-					}
+		case "Y":
+			if task := m.selectedTask(); task != nil {
+				if err := copyToClipboard(taskClipboardText(*task)); err != nil {
+					m.statusMsg = "Couldn't copy: " + err.Error()
+				} else {
+					m.statusMsg = "Copied " + task.Title
+				}
+			}
 
-					// Just add the task to wherever we currently are
-					if len(m.currentPath) == 0 {
-						m.tasks = append(m.tasks, createdTask)
-						m.cursor = len(m.tasks) - 1
-					} else {
-						// Add to current view
-						parentTask := m.currentPath[len(m.currentPath)-1]
-						parentTask.Tasks = append(parentTask.Tasks, createdTask)
-						m.cursor = len(parentTask.Tasks) - 1
-						m.currentPath[len(m.currentPath)-1] = parentTask
-
-						// Also update the task in the main task tree
-						for i := range m.tasks {
-							if m.tasks[i].Id == parentTask.Id {
-								m.tasks[i] = parentTask
-								break
-							}
-						}
-					}
+		case "S":
+			m.cycleSortMode()
+			m.cursor = 0
 
-					if err := SaveTasks(m.tasks); err != nil {
-						fmt.Printf("Error saving tasks: %v\n", err)
-					}
+		case "f":
+			m.focusMode = !m.focusMode
+			return m, tea.ClearScreen
 
-					m.inputActive = false
-					m.input.Blur()
-					return m, nil
-				case "delete":
-					if m.input.Value() == "yes" {
-						active, completed := m.getCurrentTasks()
-						// Only allow deletion if there are tasks to delete
-						if len(active) == 0 && len(completed) == 0 {
-							return m, nil
-						}
+		case "esc":
+			if m.linkFrom != "" {
+				m.linkFrom = ""
+				m.statusMsg = "Link cancelled"
+				return m, nil
+			}
+			if m.focusMode {
+				m.focusMode = false
+				return m, tea.ClearScreen
+			}
 
-						if len(m.currentPath) == 0 {
-							// Delete from main task list
-							if m.cursor < len(active) {
-								// Delete active task
-								task := active[m.cursor]
-								task.Status = "deleted"
-								m.syncToGoogle(task)
-								m.tasks = removeTask(m.tasks, task)
-								if m.cursor >= len(active)-1 {
-									m.cursor = len(active) - 2
-									if m.cursor < 0 {
-										m.cursor = 0
-									}
-								}
-							} else if m.cursor < len(active)+len(completed) {
-								// Delete completed task
-								completedIdx := m.cursor - len(active)
-								task := completed[completedIdx]
-								task.Status = "deleted"
-								m.syncToGoogle(task)
-								m.completedTasks = removeTask(m.completedTasks, task)
-								if m.cursor >= len(active)+len(completed)-1 {
-									m.cursor = len(active) + len(completed) - 2
-									if m.cursor < 0 {
-										m.cursor = 0
-									}
-								}
-							} else {
-								// Cursor is out of bounds, don't delete anything
-								return m, nil
-							}
-						} else {
-							// Delete from subtask list
-							currentTask := &m.tasks
-							var taskPtr *Task
-							for i, pathTask := range m.currentPath {
-								for j := range *currentTask {
-									if (*currentTask)[j].Id == pathTask.Id {
-										if i == len(m.currentPath)-1 {
-											taskPtr = &(*currentTask)[j]
-										} else {
-											currentTask = &(*currentTask)[j].Tasks
-										}
-										break
-									}
-								}
-							}
+		case "v":
+			// Toggle between drilling into subtasks and the inline tree
+			m.treeView = !m.treeView
+			m.cursor = 0
+			return m, tea.ClearScreen
 
-							if taskPtr != nil {
-								if m.cursor < len(active) {
-									// Delete active subtask
-									task := active[m.cursor]
-									task.Status = "deleted"
-									m.syncToGoogle(task)
-									taskPtr.Tasks = removeTask(taskPtr.Tasks, task)
-									if m.cursor >= len(active)-1 {
-										m.cursor = len(active) - 2
-										if m.cursor < 0 {
-											m.cursor = 0
-										}
-									}
-								} else {
-									// Delete completed subtask
-									completedIdx := m.cursor - len(active)
-									task := completed[completedIdx]
-									task.Status = "deleted"
-									m.syncToGoogle(task)
-									taskPtr.Tasks = removeTask(taskPtr.Tasks, task)
-									if m.cursor >= len(active)+len(completed)-1 {
-										m.cursor = len(active) + len(completed) - 2
-										if m.cursor < 0 {
-											m.cursor = 0
-										}
-									}
-								}
-								// Update the current path with the modified parent
-								m.currentPath[len(m.currentPath)-1] = *taskPtr
-							}
-						}
-						// Save tasks after deletion
-						if err := SaveTasks(m.tasks); err != nil {
-							fmt.Printf("Error saving tasks: %v\n", err)
-						}
-					}
-					m.inputActive = false
-					m.input.Blur()
-					return m, nil
+		case "z":
+			if m.treeView {
+				if task := m.selectedTask(); task != nil {
+					m.setExpanded(!m.expanded[task.Id])
 				}
-				m.inputActive = false
-				m.input.Blur()
-				return m, nil
-			default:
-				var cmd tea.Cmd
-				m.input, cmd = m.input.Update(msg)
-				return m, cmd
 			}
-		}
+			return m, nil
 
-		// Handle navigation and shortcuts when input is not active
-		switch msg.String() {
-		case "down", "j":
-			active, completed := m.getCurrentTasks()
-			if m.cursor < len(active)+len(completed)-1 {
-				m.cursor++
-				return m, tea.ClearScreen
+		case "+", "-":
+			// Expand or collapse the whole tree, like folding code
+			if m.treeView {
+				m.setAllExpanded(msg.String() == "+")
 			}
+			return m, nil
 
-		case "up", "k":
-			if m.cursor > 0 {
-				m.cursor--
-				return m, tea.ClearScreen
+		case "enter":
+			if m.enterLeaf() {
+				return m, nil
 			}
+			fallthrough
 
-		case "right", "l", "enter":
+		case "right", "l":
+			if m.treeView {
+				m.setExpanded(true)
+				return m, nil
+			}
 			active, _ := m.getCurrentTasks()
 			if m.cursor < len(active) {
 				// Always allow entering a task to potentially create subtasks
@@ -503,23 +1132,30 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				m.currentPath = append(m.currentPath, active[m.cursor])
 				m.cursor = 0
+				m.applyListSettings()
 			}
 			return m, nil
 
 		case "left", "h":
+			if m.treeView {
+				m.setExpanded(false)
+				return m, nil
+			}
 			if len(m.currentPath) > 0 {
 				m.currentPath = m.currentPath[:len(m.currentPath)-1]
 				m.cursor = 0
 				if len(m.currentPath) == 0 {
 					// If returning to top level, reset currentListID to first list
-					taskLists, err := m.googleTasks.service.Tasklists.List().Do()
-					if err == nil && len(taskLists.Items) > 0 {
-						m.currentListID = taskLists.Items[0].Id
+					if m.googleTasks != nil {
+						if listID, err := m.googleTasks.firstListID(); err == nil {
+							m.currentListID = listID
+						}
 					}
 				} else {
 					// If still in a nested list, update currentListID to parent list
 					m.currentListID = m.currentPath[0].Id // Always use the top-level list ID
 				}
+				m.applyListSettings()
 			}
 			return m, nil
 
@@ -532,84 +1168,82 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case "r":
-			active, completed := m.getCurrentTasks()
-			if (m.cursor < len(active) && len(active) > 0) || 
-			   (m.cursor >= len(active) && m.cursor-len(active) < len(completed)) {
+			if task := m.selectedTask(); task != nil {
 				m.inputActive = true
 				m.inputAction = "rename"
 				m.input.Placeholder = ""  // Clear any previous placeholder
-				if m.cursor < len(active) {
-					m.input.SetValue(active[m.cursor].Title)
-				} else {
-					completedIdx := m.cursor - len(active)
-					m.input.SetValue(completed[completedIdx].Title)
-				}
+				m.input.SetValue(task.Title)
 				m.input.Focus()
 			}
 
 		case "i":
-			active, completed := m.getCurrentTasks()
-			if (m.cursor < len(active) && len(active) > 0) || 
-			   (m.cursor >= len(active) && m.cursor-len(active) < len(completed)) {
+			if task := m.selectedTask(); task != nil {
 				m.inputActive = true
 				m.inputAction = "description"
 				m.input.Placeholder = ""  // Clear any previous placeholder
-				if m.cursor < len(active) {
-					m.input.SetValue(active[m.cursor].Description)
-				} else {
-					completedIdx := m.cursor - len(active)
-					m.input.SetValue(completed[completedIdx].Description)
-				}
+				m.input.SetValue(task.Description)
 				m.input.Focus()
 				m.input.CursorEnd()
 			}
 
 		case "o":
-			active, completed := m.getCurrentTasks()
-			if (m.cursor < len(active) && len(active) > 0) || 
-			   (m.cursor >= len(active) && m.cursor-len(active) < len(completed)) {
+			if task := m.selectedTask(); task != nil {
 				m.inputActive = true
 				m.inputAction = "notes"
 				m.input.Placeholder = ""  // Clear any previous placeholder
-				if m.cursor < len(active) {
-					m.input.SetValue(active[m.cursor].Notes)
-				} else {
-					completedIdx := m.cursor - len(active)
-					m.input.SetValue(completed[completedIdx].Notes)
-				}
+				m.input.SetValue(task.Notes)
 				m.input.Focus()
 				m.input.CursorEnd()
 			}
 
 		case "t":
-			active, completed := m.getCurrentTasks()
-			var currentTask *Task
-			if m.cursor < len(active) {
-				currentTask = &active[m.cursor]
-			} else if m.cursor-len(active) < len(completed) {
-				currentTask = &completed[m.cursor-len(active)]
+			m.openDueDateInput(dueDateInput() == DueInputPicker)
+
+		case "T":
+			if currentTask := m.selectedTask(); currentTask != nil {
+				if currentTask.DueDate.IsZero() {
+					m.statusMsg = "Set a due date with 't' first"
+					return m, nil
+				}
+				m.inputActive = true
+				m.inputAction = "due_time"
+				m.input.Placeholder = "HH:mm, or empty for end of day"
+				m.input.SetValue(currentTask.DueDate.Format("15:04"))
+				m.input.Focus()
+				m.input.CursorEnd()
 			}
 
-			if currentTask != nil {
+		case "U":
+			m.openTemplatePrompt()
+
+		case "e":
+			if task := m.selectedTask(); task != nil {
 				m.inputActive = true
-				m.inputAction = "due_date"
-				m.input.Placeholder = "Format: YYYY-MM-DD HH:mm, YYYY-MM-DD, MM/DD/YYYY, or DD-MM-YYYY"
-				
-				// Show current due date if it exists
-				if !currentTask.DueDate.IsZero() {
-					m.input.SetValue(currentTask.DueDate.Format("2006-01-02 15:04"))
-					tea.Printf("Current due date: %s", currentTask.DueDate.Format("2006-01-02 15:04"))
-				} else {
-					m.input.SetValue("")
-					tea.Printf("No current due date. Enter in format: YYYY-MM-DD HH:mm, YYYY-MM-DD, MM/DD/YYYY, or DD-MM-YYYY")
+				m.inputAction = "estimate"
+				m.input.Placeholder = "2h, 30m, 1d (8h) or 1d4h, empty to clear"
+				m.input.SetValue("")
+				if task.Estimate > 0 {
+					m.input.SetValue(formatEstimate(task.Estimate))
 				}
 				m.input.Focus()
+				m.input.CursorEnd()
+			}
+
+		case "b":
+			if task := m.selectedTask(); task != nil {
+				m.inputActive = true
+				m.inputAction = "blocked_by"
+				m.input.Placeholder = "Blocker task ID (empty to clear all blockers)"
+				m.input.SetValue("")
+				m.input.Focus()
 			}
 
+		case "B":
+			m.linkTasks()
+
 		case "d":
-			active, completed := m.getCurrentTasks()
 			// Only allow deletion if there are tasks to delete
-			if len(active) == 0 && len(completed) == 0 {
+			if m.selectedTask() == nil {
 				return m, nil
 			}
 
@@ -622,77 +1256,43 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case " ":
-			active, completed := m.getCurrentTasks()
-			if len(m.currentPath) == 0 {
-				if m.cursor < len(active) {
-					// Mark task as completed
-					task := active[m.cursor]
-					task.Completed = true
-					task.Status = "completed"
-					m.syncToGoogle(task)
-					m.completedTasks = append(m.completedTasks, task)
-					m.tasks = removeTask(m.tasks, task)
-				} else {
-					// Move task back to active
-					completedIdx := m.cursor - len(active)
-					task := completed[completedIdx]
-					task.Completed = false
-					task.Status = "needsAction"
-					m.syncToGoogle(task)
-					m.tasks = append(m.tasks, task)
-					m.completedTasks = removeTask(m.completedTasks, task)
-				}
-				if err := SaveTasks(m.tasks); err != nil {
-					fmt.Printf("Error saving tasks: %v\n", err)
-				}
-			} else {
-				// Find and update the actual task in the main task list
-				currentTask := &m.tasks
-				var taskPtr *Task
-				for i, pathTask := range m.currentPath {
-					for j := range *currentTask {
-						if (*currentTask)[j].Id == pathTask.Id {
-							if i == len(m.currentPath)-1 {
-								taskPtr = &(*currentTask)[j]
-							} else {
-								currentTask = &(*currentTask)[j].Tasks
-							}
-							break
-						}
-					}
-				}
-				
-				if taskPtr != nil {
-					if m.cursor < len(active) {
-						// Mark subtask as completed
-						task := active[m.cursor]
-						task.Completed = true
-						task.Status = "completed"
-						m.syncToGoogle(task)
-						taskPtr.Tasks = removeTask(taskPtr.Tasks, task)
-						taskPtr.Tasks = append(taskPtr.Tasks, task)
-					} else {
-						// Move subtask back to active
-						completedIdx := m.cursor - len(active)
-						task := completed[completedIdx]
-						task.Completed = false
-						task.Status = "needsAction"
-						m.syncToGoogle(task)
-						taskPtr.Tasks = removeTask(taskPtr.Tasks, task)
-						taskPtr.Tasks = append(taskPtr.Tasks, task)
-					}
-					
-					// Update current path with latest task data
-					m.currentPath[len(m.currentPath)-1] = *taskPtr
-					if err := SaveTasks(m.tasks); err != nil {
-						fmt.Printf("Error saving tasks: %v\n", err)
-					}
+			task := m.selectedTask()
+			if task == nil {
+				return m, nil
+			}
+			if !task.Completed && m.isBlocked(*task) {
+				m.statusMsg = "This task is blocked by unfinished tasks"
+				return m, nil
+			}
+			completing := !task.Completed
+			if completing {
+				if config := GetGlobalConfig(); config != nil && config.ConfirmComplete {
+					m.inputActive = true
+					m.inputAction = "complete"
+					m.input.Placeholder = "Complete '" + task.Title + "'? (y/n)"
+					m.input.SetValue("")
+					m.input.Focus()
+					return m, nil
 				}
 			}
+			m.toggleCompletion(task.Id)
+			if completing {
+				return m, completionBell()
+			}
+			return m, nil
+
+		case "P":
+			return m, m.cycleStatus()
+
+		case "x":
+			m.openChecklist()
 			return m, nil
 
-		case "q":
-			return m, tea.Quit
+		case ",":
+			return m, m.editConfig()
+
+		case "q", "Q", "ctrl+c":
+			return m, m.quit(msg.String())
 		}
 	}
 
@@ -701,26 +1301,29 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View renders the UI
 func (m model) View() string {
-	var s strings.Builder
-
-	// Calculate panel widths based on terminal size
-	minMainWidth := 30  // Minimum width for main panel
-	minDetailsWidth := 30  // Minimum width for details panel
-	padding := 3  // Space between panels
+	if m.showErrorLog {
+		return m.renderErrorLog()
+	}
+	if m.calendarMode {
+		return m.renderCalendar()
+	}
+	if m.showNext {
+		return m.renderNextActions()
+	}
+	if m.showTrash {
+		return m.renderTrash()
+	}
+	if m.showConflict {
+		return m.renderConflict()
+	}
+	if m.focusMode && !m.inputActive {
+		return m.renderFocus()
+	}
 
-	// Adjust panel widths based on terminal size
-	mainPanelWidth := m.width * 2 / 3
-	detailsPanelWidth := m.width - mainPanelWidth - padding
+	var s strings.Builder
 
-	// If terminal is too narrow, switch to full width for main panel
-	if m.width < minMainWidth+minDetailsWidth+padding {
-		mainPanelWidth = m.width
-		detailsPanelWidth = 0
-	} else if detailsPanelWidth < minDetailsWidth {
-		// Ensure details panel has minimum width if shown
-		detailsPanelWidth = minDetailsWidth
-		mainPanelWidth = m.width - minDetailsWidth - padding
-	}
+	mainPanelWidth, detailsPanelWidth := m.panelWidths()
+	mainPanelHeight, detailsPanelHeight := m.panelHeights()
 
 	// Build main task list panel
 	var mainPanel strings.Builder
@@ -734,7 +1337,7 @@ func (m model) View() string {
 	}
 
 	active, completed := m.getCurrentTasks()
-	if len(active) == 0 && len(completed) == 0 && !m.inputActive {
+	if len(active) == 0 && len(completed) == 0 && m.hiddenCompletedCount() == 0 && !m.inputActive {
 		// Show hint message when no tasks exist
 		hint := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("241")).
@@ -742,192 +1345,168 @@ func (m model) View() string {
 		mainPanel.WriteString("\n" + hint + "\n")
 	}
 
-	if m.inputActive {
-		if m.inputAction == "due_date" {
-			var oldDate string
-			if m.cursor >= 0 && m.cursor < len(active) {
-				oldDate = active[m.cursor].DueDate.Format("2006-01-02 15:04")
+	if m.snoozing {
+		mainPanel.WriteString(renderSnoozeMenu())
+	}
+
+	if m.showStats {
+		mainPanel.WriteString(renderAPIStats() + "\n")
+	}
+
+	if m.formActive {
+		mainPanel.WriteString(m.renderForm())
+	} else if m.datePicking {
+		mainPanel.WriteString("Due date:\n" + m.datePicker.View(m.accentColor()))
+	} else if m.inputActive {
+		if m.inputAction == "due_date" || m.inputAction == "due_time" {
+			if task := m.selectedTask(); task != nil && !task.DueDate.IsZero() {
+				mainPanel.WriteString("Current due date: " + formatDate(task.DueDate) + "\n")
+			}
+			if m.inputAction == "due_time" {
+				mainPanel.WriteString("Enter due time (HH:mm), the date is kept: \n" + m.input.View() + "\n\n")
+			} else {
+				mainPanel.WriteString("Enter due date (YYYY-MM-DD HH:mm, YYYY-MM-DD, MM/DD/YYYY, or DD-MM-YYYY, tab: picker): \n" + m.input.View() + "\n\n")
 			}
-			if oldDate != "" {
-				mainPanel.WriteString("Current due date: " + oldDate + "\n")
+		} else if m.inputAction == "palette" {
+			mainPanel.WriteString(":" + m.input.View() + "\n\n" + m.paletteSuggestions(8) + "\n")
+		} else if m.inputAction == "switch_list" {
+			mainPanel.WriteString("Switch to list: " + m.input.View() + "\n\n" + m.renderListSwitcher(8) + "\n")
+		} else if m.inputAction == "add_link" {
+			mainPanel.WriteString("Add link (URL, then an optional description): " + m.input.View() + "\n\n")
+		} else if m.inputAction == "attach" {
+			mainPanel.WriteString("Attach file or URL (path, then an optional description): " + m.input.View() + "\n\n")
+		} else if m.inputAction == "open_link" {
+			mainPanel.WriteString("Open link number: " + m.input.View() + "\n\n")
+		} else if m.inputAction == "move" {
+			mainPanel.WriteString("Move to list:\n" + m.renderMoveTargets() + m.input.View() + "\n\n")
+		} else if m.inputAction == "move_confirm" {
+			mainPanel.WriteString("Confirm move: " + m.input.View() + "\n\n")
+		} else if m.inputAction == "complete" {
+			mainPanel.WriteString("Confirm completion: " + m.input.View() + "\n\n")
+		} else if m.inputAction == "dedupe" {
+			mainPanel.WriteString("Remove duplicate tasks, keeping the oldest of each: " + m.input.View() + "\n\n")
+		} else if m.inputAction == "clear_completed" {
+			mainPanel.WriteString("Clear completed tasks from Google, keeping a local copy: " + m.input.View() + "\n\n")
+		} else if m.inputAction == "template" {
+			mainPanel.WriteString("New task from template:\n" + renderTemplates(templateNames(m.templates)) + m.input.View() + "\n\n")
+		} else if m.inputAction == "estimate" {
+			mainPanel.WriteString("Estimate: " + m.input.View() + "\n\n")
+		} else if m.inputAction == "checklist" {
+			notes := ""
+			if task := m.selectedTask(); task != nil {
+				notes = task.Notes
 			}
-			mainPanel.WriteString("Enter due date (YYYY-MM-DD HH:mm, YYYY-MM-DD, MM/DD/YYYY, or DD-MM-YYYY): \n" + m.input.View() + "\n\n")
+			mainPanel.WriteString("Toggle checklist item:\n" + renderChecklist(notes) + m.input.View() + "\n\n")
+		} else if m.inputAction == "jump" {
+			mainPanel.WriteString("Jump to task: " + m.input.View() + "\n\n")
 		} else {
-			mainPanel.WriteString("Enter " + m.inputAction + ": " + m.input.View() + "\n\n")
+			mainPanel.WriteString("Enter " + m.inputAction + ": " + m.input.View() + m.draftHint() + "\n\n")
 		}
+	} else if m.reviewing {
+		mainPanel.WriteString(m.renderReview())
 	} else {
-		// Calculate available height for tasks
-		headerHeight := len(strings.Split(mainPanel.String(), "\n"))
-		footerHeight := 2 // For potential scroll indicators
-		availableHeight := m.height - headerHeight - footerHeight
-
-		// Calculate total tasks
-		totalTasks := len(active) + len(completed)
-
-		// Calculate visible window
-		startIdx := 0
-		if m.cursor >= availableHeight {
-			startIdx = m.cursor - (availableHeight / 2)
+		// Lines left for the rows once the heading and footer lines are drawn
+		listHeight := mainPanelHeight - strings.Count(mainPanel.String(), "\n") - 2
+		if m.hiddenCompletedCount() > 0 {
+			listHeight--
 		}
-		if startIdx < 0 {
-			startIdx = 0
+		levelEffort := m.levelEffort()
+		if levelEffort != "" {
+			listHeight--
 		}
-		endIdx := startIdx + availableHeight
-		if endIdx > totalTasks {
-			endIdx = totalTasks
-			// Adjust startIdx to show maximum possible tasks
-			if totalTasks > availableHeight {
-				startIdx = totalTasks - availableHeight
-			}
+		linkHint := m.linkHint()
+		if linkHint != "" {
+			listHeight--
+		}
+		conflictHint := m.conflictHint()
+		if conflictHint != "" {
+			listHeight--
+		}
+		remoteHint := m.remoteChangesHint()
+		if remoteHint != "" {
+			listHeight--
+		}
+		if m.showLegend {
+			listHeight--
+		}
+		if m.loading {
+			listHeight--
+		}
+		if m.statusMsg != "" {
+			listHeight--
 		}
 
-		// Show active tasks
+		now, wt := time.Now(), currentWorkingTime()
 		mainPanel.WriteString("Tasks:\n\n")
-		for i, task := range active {
-			if i >= startIdx && i < endIdx {
-				cursor := " "
-				if m.cursor == i {
-					cursor = ">"
-				}
-				taskTitle := task.Title
-				if len(task.Tasks) > 0 {
-					taskTitle += " ▶"
-				}
-				if m.cursor == i {
-					taskTitle = lipgloss.NewStyle().Foreground(lipgloss.Color("86")).Render(taskTitle)
-				}
-				mainPanel.WriteString(fmt.Sprintf("%s %s\n", cursor, taskTitle))
-			}
+		var rows string
+		if m.treeView {
+			treeRows := m.treeRows()
+			list := virtualList{count: len(treeRows), cursor: m.cursor, height: listHeight}
+			rows = list.render(func(i int) string {
+				return m.renderTreeRow(treeRows[i], m.cursor == i, now, wt)
+			})
+		} else {
+			rows = m.renderTaskList(active, completed, listHeight, now, wt)
 		}
+		mainPanel.WriteString(strings.TrimSuffix(rows, "\n"))
 
-		// Show completed tasks if any
-		if len(completed) > 0 {
-			completedStartIdx := len(active)
-			if completedStartIdx >= startIdx && completedStartIdx < endIdx {
-				mainPanel.WriteString("\nCompleted Tasks:\n\n")
-			}
-			for i, task := range completed {
-				globalIdx := len(active) + i
-				if globalIdx >= startIdx && globalIdx < endIdx {
-					cursor := " "
-					if m.cursor == globalIdx {
-						cursor = ">"
-					}
-					taskTitle := task.Title
-					if len(task.Tasks) > 0 {
-						taskTitle += " ▶"
-					}
-					style := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
-					if m.cursor == globalIdx {
-						style = style.Foreground(lipgloss.Color("86"))
-					}
-					mainPanel.WriteString(fmt.Sprintf("%s %s\n", cursor, style.Render(taskTitle)))
-				}
+		if hidden := m.hiddenCompletedCount(); hidden > 0 {
+			summary := fmt.Sprintf("%d completed hidden (H to show)", hidden)
+			if !m.hideCompleted {
+				summary = fmt.Sprintf("✓ %d completed tasks (press c to expand)", hidden)
 			}
+			mainPanel.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(summary))
 		}
-
-		// Add scroll indicators if needed
-		if startIdx > 0 {
-			mainPanel.WriteString("\n↑ More tasks above")
+		if levelEffort != "" {
+			mainPanel.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(levelEffort))
 		}
-		if endIdx < totalTasks {
-			mainPanel.WriteString("\n↓ More tasks below")
+		if linkHint != "" {
+			mainPanel.WriteString("\n" + lipgloss.NewStyle().Foreground(m.accentColor()).Render(linkHint))
 		}
-	}
-
-	// Build details panel if there's space
-	var detailsPanel strings.Builder
-	if detailsPanelWidth > 0 {
-		detailsPanel.WriteString("Task Details\n\n")
-
-		// Get the currently selected task
-		var selectedTask *Task
-		active, completed := m.getCurrentTasks()
-		if m.cursor < len(active) && len(active) > 0 {
-			selectedTask = &active[m.cursor]
-		} else if len(completed) > 0 && m.cursor-len(active) < len(completed) {
-			selectedTask = &completed[m.cursor-len(active)]
+		if conflictHint != "" {
+			mainPanel.WriteString("\n" + overdueStyle.Render(conflictHint))
 		}
-
-		if selectedTask != nil {
-			// Function to wrap text to fit panel width
-			wrapText := func(text string) string {
-				if text == "" {
-					return text
-				}
-				words := strings.Fields(text)
-				var lines []string
-				currentLine := words[0]
-				spaceLeft := detailsPanelWidth - 4 // Account for padding and borders
-				
-				for _, word := range words[1:] {
-					if len(currentLine)+1+len(word) <= spaceLeft {
-						currentLine += " " + word
-					} else {
-						lines = append(lines, currentLine)
-						currentLine = word
-					}
-				}
-				lines = append(lines, currentLine)
-				return strings.Join(lines, "\n")
-			}
-
-			// Show task details with text wrapping
-			detailsPanel.WriteString("Title: " + wrapText(selectedTask.Title) + "\n\n")
-
-			detailsPanel.WriteString("Description: \n")
-			if selectedTask.Description == "" {
-				detailsPanel.WriteString("(Press 'i' to add description)\n")
-			} else {
-				detailsPanel.WriteString(wrapText(selectedTask.Description) + "\n")
-			}
-			detailsPanel.WriteString("\n")
-
-			detailsPanel.WriteString("Notes: \n")
-			if selectedTask.Notes == "" {
-				detailsPanel.WriteString("(Press 'o' to add notes)\n")
-			} else {
-				detailsPanel.WriteString(wrapText(selectedTask.Notes) + "\n")
-			}
-			detailsPanel.WriteString("\n")
-
-			detailsPanel.WriteString("Created: " + selectedTask.CreatedAt.Format("2006-01-02 15:04") + "\n")
-			
-			detailsPanel.WriteString("Due Date: ")
-			if selectedTask.DueDate.IsZero() {
-				detailsPanel.WriteString("(Press 't' to set due date)\n")
-			} else {
-				detailsPanel.WriteString(selectedTask.DueDate.Format("2006-01-02 15:04") + "\n")
-			}
-
-			// Add keyboard shortcuts at the bottom if there's space
-			if m.height > 20 {
-				detailsPanel.WriteString("\n\nKeyboard Shortcuts:\n")
-				detailsPanel.WriteString("n: New task    d: Delete\n")
-				detailsPanel.WriteString("r: Rename      i: Edit description\n")
-				detailsPanel.WriteString("o: Edit notes  t: Set due date\n")
-				detailsPanel.WriteString("Enter: Toggle completion\n")
-				detailsPanel.WriteString("←/h: Back      →/l: Enter sublist\n")
-			}
-		} else {
-			detailsPanel.WriteString("No task selected")
+		if remoteHint != "" {
+			mainPanel.WriteString("\n" + lipgloss.NewStyle().Foreground(m.accentColor()).Render(remoteHint))
+		}
+		if m.showLegend {
+			mainPanel.WriteString("\n" + renderLegend())
 		}
 	}
 
+	if m.loading {
+		mainPanel.WriteString("\n" + m.spinner.View() + " Syncing with Google Tasks...")
+	}
+	if m.statusMsg != "" {
+		mainPanel.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render(m.statusMsg))
+	}
+
 	// Combine panels with border
 	mainPanelStr := lipgloss.NewStyle().
 		Width(mainPanelWidth).
+		MaxHeight(mainPanelHeight).
 		Render(mainPanel.String())
 
 	if detailsPanelWidth > 0 {
+		borderColor := lipgloss.Color("12")
+		if m.detailsFocus {
+			borderColor = m.accentColor()
+		}
+		// Width excludes the border, so take it off to stay inside the terminal
 		detailsPanelStr := lipgloss.NewStyle().
-			Width(detailsPanelWidth).
+			Width(detailsPanelWidth - 2).
 			Border(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color("12")).
+			BorderForeground(borderColor).
 			Padding(1).
-			Render(detailsPanel.String())
+			Render(m.detailsViewport(detailsPanelWidth, detailsPanelHeight).View())
 
-		s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, mainPanelStr, "  ", detailsPanelStr))
+		if m.detailsLayout() == LayoutStacked {
+			// Pad the list to its full height so the details panel stays put
+			mainPanelStr = lipgloss.NewStyle().Height(mainPanelHeight).Render(mainPanelStr)
+			s.WriteString(lipgloss.JoinVertical(lipgloss.Left, mainPanelStr, detailsPanelStr))
+		} else {
+			s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, mainPanelStr, "  ", detailsPanelStr))
+		}
 	} else {
 		s.WriteString(mainPanelStr)
 	}
@@ -935,6 +1514,118 @@ func (m model) View() string {
 	return s.String()
 }
 
+// renderTaskList renders the visible part of the flat task list, with the
+// completed tasks under their own heading
+func (m *model) renderTaskList(active, completed []Task, height int, now time.Time, wt workingTime) string {
+	// The completed heading takes a blank line, the heading and another blank line
+	headingLines := 0
+	if len(completed) > 0 {
+		headingLines = 3
+	}
+	cursorLine := m.cursor
+	if m.cursor >= len(active) {
+		cursorLine += headingLines
+	}
+
+	list := virtualList{
+		count:  len(active) + headingLines + len(completed),
+		cursor: cursorLine,
+		height: height,
+	}
+	return list.render(func(line int) string {
+		switch {
+		case line < len(active):
+			return m.renderTaskRow(active[line], m.cursor == line, now, wt)
+		case line == len(active)+1:
+			return "Completed Tasks:"
+		case line < len(active)+headingLines:
+			return ""
+		}
+		i := line - headingLines
+		return m.renderCompletedRow(completed[i-len(active)], m.cursor == i)
+	})
+}
+
+// renderTaskRow formats an active task as one line of the list
+func (m *model) renderTaskRow(task Task, selected bool, now time.Time, wt workingTime) string {
+	cursor := " "
+	if selected {
+		cursor = ">"
+	}
+	markers := ""
+	if !task.TimerStarted.IsZero() {
+		markers = "⏱ " + markers
+	}
+	if task.Pinned || (len(m.currentPath) == 0 && isPinned(task.Id)) {
+		markers = "📌 " + markers
+	}
+	if task.Id == m.linkFrom {
+		markers = linkMarker + markers
+	}
+	style := lipgloss.NewStyle()
+	if m.isBlocked(task) {
+		markers = "🔒 " + markers
+		style = blockedStyle
+	} else if isOverdue(task, now, wt) {
+		style = overdueStyle
+	} else if task.InProgress {
+		markers = inProgressMarker + markers
+		style = inProgressStyle
+	} else if isStale(task, now) {
+		markers = staleMarker + markers
+		style = staleStyle
+	}
+	if selected {
+		style = style.Foreground(m.accentColor())
+	}
+	badge := subtaskBadge(task)
+	if len(m.currentPath) == 0 {
+		// Lists show what they hold instead of progress
+		badge = listBadge(task)
+	}
+	prefix := fmt.Sprintf("%s %s%s", cursor, colorFlag(task.Color), priorityMarker(task.Priority))
+	taskTitle := markers + fitTitle(task.Title, badge, m.titleWidth(prefix+markers))
+	return prefix + style.Render(taskTitle)
+}
+
+// renderCompletedRow formats a completed task as one line of the list
+func (m *model) renderCompletedRow(task Task, selected bool) string {
+	cursor := " "
+	if selected {
+		cursor = ">"
+	}
+	style := completedStyle
+	if selected {
+		style = style.Foreground(m.accentColor())
+	}
+	prefix := fmt.Sprintf("%s %s", cursor, colorFlag(task.Color))
+	taskTitle := fitTitle(task.Title, subtaskBadge(task), m.titleWidth(prefix))
+	return prefix + style.Render(taskTitle)
+}
+
+// titleWidth returns the columns left for a title after prefix in the
+// task list panel, or 0 before the terminal size is known
+func (m *model) titleWidth(prefix string) int {
+	mainPanelWidth, _ := m.panelWidths()
+	if mainPanelWidth <= 0 {
+		return 0
+	}
+	return max(mainPanelWidth-lipgloss.Width(prefix), 1)
+}
+
+// priorityMarker returns a colored marker to prefix task titles with
+func priorityMarker(priority string) string {
+	switch priority {
+	case PriorityHigh:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("!!!") + " "
+	case PriorityMedium:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render("!!") + " "
+	case PriorityLow:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("39")).Render("!") + " "
+	}
+	return ""
+}
+
 // removeTask removes a task from a list of tasks
 func removeTask(tasks []Task, task Task) []Task {
 	for i, t := range tasks {
@@ -945,12 +1636,37 @@ func removeTask(tasks []Task, task Task) []Task {
 	return tasks
 }
 
+// indexOfTask returns the index of the task with the given ID in a flat list, or -1
+func indexOfTask(tasks []Task, id string) int {
+	for i := range tasks {
+		if tasks[i].Id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// removeTaskByID removes a task with the given ID anywhere in a task tree
+func removeTaskByID(tasks []Task, id string) []Task {
+	if i := indexOfTask(tasks, id); i >= 0 {
+		return append(tasks[:i], tasks[i+1:]...)
+	}
+	for i := range tasks {
+		tasks[i].Tasks = removeTaskByID(tasks[i].Tasks, id)
+	}
+	return tasks
+}
+
 // splitTasks splits tasks into active and completed tasks
 func splitTasks(tasks []Task) ([]Task, []Task) {
 	active := make([]Task, 0)
 	completed := make([]Task, 0)
 
 	for _, task := range tasks {
+		if task.Deleted {
+			// Tombstones stay in the tree for sync but are never shown
+			continue
+		}
 		if task.Completed {
 			completed = append(completed, task)
 		} else {
@@ -961,27 +1677,29 @@ func splitTasks(tasks []Task) ([]Task, []Task) {
 	return active, completed
 }
 
-// generateID creates a unique ID for tasks
-func generateID() string {
-	return fmt.Sprintf("%d", time.Now().UnixNano())
+// cloneTasks deep copies a task tree, so it can be read outside Update
+func cloneTasks(tasks []Task) []Task {
+	if tasks == nil {
+		return nil
+	}
+	clones := make([]Task, len(tasks))
+	for i, task := range tasks {
+		task.Tags = append([]string(nil), task.Tags...)
+		task.BlockedBy = append([]string(nil), task.BlockedBy...)
+		task.Links = append([]TaskLink(nil), task.Links...)
+		task.Tasks = cloneTasks(task.Tasks)
+		clones[i] = task
+	}
+	return clones
 }
 
-// UpdateTasks updates the task list and refreshes the UI
-func (m *model) UpdateTasks(tasks []Task) {
-	select {
-	case m.updateChan <- tasks:
-		// Task update sent successfully
-		if m.googleTasks != nil {
-			// Sync all tasks to Google
-			go func() {
-				err := ExportToGoogle(tasks)
-				if err != nil {
-					tea.Println("Error syncing with Google Tasks:", err)
-				}
-			}()
-		}
-	default:
-		fmt.Println("Warning: Update channel full, skipping update")
+// replaceTasks swaps in a task tree from background sync or the file
+// watcher. It only runs inside Update, which owns the task state.
+func (m *model) replaceTasks(tasks []Task) {
+	m.tasks, m.completedTasks = splitTasks(tasks)
+	m.dirty = true
+	if count := m.visibleCount(); m.cursor >= count && count > 0 {
+		m.cursor = count - 1
 	}
 }
 
@@ -991,40 +1709,75 @@ func (m *model) syncToGoogle(task Task) {
 		return
 	}
 
-	go func() {
+	// The goroutine works on copies, as Update keeps changing the originals.
+	// New tasks go to the open list; the store finds the list of the others.
+	client, listID := m.googleTasks, m.currentListID
+	task = cloneTasks([]Task{task})[0]
+	snapshot := cloneTasks(m.tasks)
+
+	goSync(func() {
+		store := NewGoogleStore(client, listID)
 		var err error
-		switch task.Status {
-		case "needsAction":
-			if task.Id == "" {
-				// New task
-				_, err = m.googleTasks.CreateTask(task, m.currentListID)
-			} else {
-				// Updated task
-				err = m.googleTasks.UpdateTask(task)
-			}
-		case "completed":
-			err = m.googleTasks.UpdateTask(task)
-		case "deleted":
-			err = m.googleTasks.DeleteTask(task.Id)
+		switch {
+		case task.Status == "deleted":
+			err = store.Delete(task.Id)
+		case task.Id == "":
+			// New task
+			_, err = store.Add(task)
+		default:
+			err = store.Update(task)
 		}
 
 		if err != nil {
-			tea.Println("Error syncing with Google Tasks:", err)
+			reportSyncError(err)
 		}
 
 		// After individual task sync, sync all tasks to ensure consistency
-		if err := ExportToGoogle(m.tasks); err != nil {
-			tea.Println("Error syncing all tasks with Google:", err)
+		if err := ExportToGoogle(snapshot); err != nil {
+			reportSyncError(err)
 		}
-	}()
+	})
 }
 
 // RunTaskUI starts the Bubble Tea program
 func RunTaskUI(tasks []Task, client *GoogleTasksClient) {
+	if err := loadListSettings(); err != nil {
+		logError("Error loading list settings: %v", err)
+	}
+	if err := loadListOrder(); err != nil {
+		logError("Error loading list order: %v", err)
+	}
+	if err := loadRecentLists(); err != nil {
+		logError("Error loading recent lists: %v", err)
+	}
+
 	m := NewModel(tasks, client)
+
+	if !UseGoogleTasks.Load() {
+		ensureLocalWatcher()
+	}
+
 	p := tea.NewProgram(m)
-	if err := p.Start(); err != nil {
-		fmt.Printf("Error running program: %v\n", err)
+	uiProgram.Store(p)
+	quitOnHangup(p)
+
+	if terminalTitleEnabled() {
+		saveTerminalTitle()
+	}
+	final, err := p.Run()
+	uiProgram.Store(nil)
+	if terminalTitleEnabled() {
+		restoreTerminalTitle()
+	}
+	if err != nil {
+		logError("Error running program: %v", err)
 		os.Exit(1)
 	}
+
+	// Flush whatever is still pending, however the program was asked to quit
+	if fm, ok := final.(model); ok && fm.dirty {
+		if err := DefaultStore().Save(fm.allTasks()); err != nil {
+			logError("Error saving tasks: %v", err)
+		}
+	}
 }
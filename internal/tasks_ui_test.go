@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestConcurrentSyncAndSave feeds the running UI fresh trees from several
+// sync goroutines while it takes keypresses and saves. Run it with -race:
+// only Update may touch the model, so the detector must stay quiet.
+func TestConcurrentSyncAndSave(t *testing.T) {
+	defer SetGlobalConfig(GetGlobalConfig())
+	SetGlobalConfig(&GodoConfig{StoragePath: t.TempDir()})
+
+	tasks := []Task{{
+		Id: "list", Title: "List", Kind: "tasks#task", Status: "needsAction",
+		Tasks: []Task{{Id: "task", Parent: "list", Title: "Task", Kind: "tasks#task", Status: "needsAction"}},
+	}}
+	p := tea.NewProgram(NewModel(tasks, nil), tea.WithInput(nil), tea.WithOutput(io.Discard), tea.WithoutRenderer())
+	uiProgram.Store(p)
+	defer uiProgram.Store(nil)
+
+	done := make(chan tea.Model)
+	go func() {
+		final, err := p.Run()
+		if err != nil {
+			t.Errorf("running the UI: %v", err)
+		}
+		done <- final
+	}()
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 50 {
+				fresh := cloneTasks(tasks)
+				fresh[0].Tasks[0].Title = fmt.Sprintf("Task %d.%d", i, j)
+				notifyUIOfChanges(fresh)
+				p.Send(saveFlushMsg{})
+			}
+		}()
+	}
+	for _, key := range []string{"l", "j", "k", "h"} {
+		p.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	}
+	wg.Wait()
+	p.Quit()
+
+	final, ok := (<-done).(model)
+	if !ok {
+		t.Fatal("the UI didn't return its model")
+	}
+	final.autosave()
+
+	saved, err := LoadTasks()
+	if err != nil {
+		t.Fatalf("loading the saved tasks: %v", err)
+	}
+	if findTask(saved, "task") == nil {
+		t.Errorf("saved tasks lost the task: %+v", saved)
+	}
+}
@@ -0,0 +1,232 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TaskTemplate describes a task, with its subtasks, that is created over and
+// over. Title and notes may contain placeholders such as {date}.
+type TaskTemplate struct {
+	Title    string         `json:"title"`
+	Notes    string         `json:"notes"`
+	Due      string         `json:"due"` // Offset from now, e.g. "2d", "1w" or "4h"
+	Priority string         `json:"priority"`
+	Tags     []string       `json:"tags"`
+	Tasks    []TaskTemplate `json:"tasks"`
+}
+
+// templatesPath returns templates.json next to the config file
+func templatesPath() string {
+	if configFilePath != "" {
+		return filepath.Join(filepath.Dir(configFilePath), "templates.json")
+	}
+	return filepath.Join(os.Getenv("HOME"), ".config", "godo", "templates.json")
+}
+
+// loadTemplates reads the named templates. A missing file means no templates.
+func loadTemplates() (map[string]TaskTemplate, error) {
+	data, err := os.ReadFile(templatesPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]TaskTemplate{}, nil
+		}
+		return nil, fmt.Errorf("error reading templates: %v", err)
+	}
+	var templates map[string]TaskTemplate
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", templatesPath(), err)
+	}
+	return templates, nil
+}
+
+// templateNames returns the template names in alphabetical order
+func templateNames(templates map[string]TaskTemplate) []string {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewTaskFromTemplate builds a new task tree from the named template, with
+// placeholders filled in and due dates counted from now. IDs are left for
+// whoever stores the task.
+func NewTaskFromTemplate(name string, now time.Time) (Task, error) {
+	templates, err := loadTemplates()
+	if err != nil {
+		return Task{}, err
+	}
+	for _, candidate := range templateNames(templates) {
+		if strings.EqualFold(candidate, name) {
+			return templates[candidate].instantiate(now)
+		}
+	}
+	return Task{}, fmt.Errorf("no template named %q in %s", name, templatesPath())
+}
+
+// instantiate turns the template into a task tree
+func (t TaskTemplate) instantiate(now time.Time) (Task, error) {
+	task := Task{
+		Title:    expandPlaceholders(t.Title, now),
+		Notes:    expandPlaceholders(t.Notes, now),
+		Priority: t.Priority,
+		Tags:     t.Tags,
+	}
+	if t.Due != "" {
+		due, err := parseDueOffset(t.Due, now)
+		if err != nil {
+			return Task{}, err
+		}
+		task.DueDate = due
+	}
+	newTaskDefaults(&task, now)
+
+	for _, sub := range t.Tasks {
+		child, err := sub.instantiate(now)
+		if err != nil {
+			return Task{}, err
+		}
+		task.Tasks = append(task.Tasks, child)
+	}
+	return task, nil
+}
+
+// expandPlaceholders fills in {date}, {time}, {weekday}, {month} and {year}
+func expandPlaceholders(s string, now time.Time) string {
+	return strings.NewReplacer(
+		"{date}", now.Format("2006-01-02"),
+		"{time}", now.Format("15:04"),
+		"{weekday}", now.Weekday().String(),
+		"{month}", now.Month().String(),
+		"{year}", strconv.Itoa(now.Year()),
+	).Replace(s)
+}
+
+// parseDueOffset reads a due date offset like "2d", "1w", "4h" or "1d4h".
+// Whole days and weeks give a date, as quick add does; hours and minutes
+// count from now.
+func parseDueOffset(offset string, now time.Time) (time.Time, error) {
+	rest := strings.ToLower(strings.TrimSpace(offset))
+	invalid := fmt.Errorf("invalid due offset %q, use e.g. 2d, 1w or 4h", offset)
+
+	days := 0
+	for _, unit := range []struct {
+		suffix string
+		days   int
+	}{{"w", 7}, {"d", 1}} {
+		if i := strings.Index(rest, unit.suffix); i >= 0 {
+			n, err := strconv.Atoi(rest[:i])
+			if err != nil || n < 0 {
+				return time.Time{}, invalid
+			}
+			days += n * unit.days
+			rest = rest[i+1:]
+		}
+	}
+
+	if rest == "" {
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		return today.AddDate(0, 0, days), nil
+	}
+	d, err := time.ParseDuration(rest)
+	if err != nil || d < 0 {
+		return time.Time{}, invalid
+	}
+	return now.AddDate(0, 0, days).Add(d), nil
+}
+
+// renderTemplates numbers the templates for the template prompt
+func renderTemplates(names []string) string {
+	var s strings.Builder
+	for i, name := range names {
+		s.WriteString(fmt.Sprintf("  %d. %s\n", i+1, name))
+	}
+	return s.String()
+}
+
+// resolveTemplate picks a template by its number in the prompt or by
+// fuzzy matching its name
+func resolveTemplate(names []string, input string) (string, bool) {
+	input = strings.TrimSpace(input)
+	if n, err := strconv.Atoi(input); err == nil {
+		if n >= 1 && n <= len(names) {
+			return names[n-1], true
+		}
+		return "", false
+	}
+
+	best, bestScore := -1, 0
+	for i, name := range names {
+		if score, ok := fuzzyScore(name, input); ok && (best < 0 || score > bestScore) {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		return "", false
+	}
+	return names[best], true
+}
+
+// createFromTemplate adds a task built from the template input names at
+// the current level
+func (m *model) createFromTemplate(templates map[string]TaskTemplate, input string) {
+	name, ok := resolveTemplate(templateNames(templates), input)
+	if !ok {
+		m.statusMsg = "No template matches " + input
+		return
+	}
+	task, err := templates[name].instantiate(time.Now())
+	if err != nil {
+		m.statusMsg = err.Error()
+		return
+	}
+	m.createTask(task)
+}
+
+func init() {
+	registerCommand(paletteCommand{Name: "template", Desc: "Create a task from a template", Run: templateCommand})
+}
+
+func templateCommand(m *model, args string) tea.Cmd {
+	if args == "" {
+		m.openTemplatePrompt()
+		return nil
+	}
+	templates, err := loadTemplates()
+	if err != nil {
+		m.statusMsg = err.Error()
+		return nil
+	}
+	m.createFromTemplate(templates, args)
+	return nil
+}
+
+// openTemplatePrompt asks which template to create a task from. The
+// templates are read once here, as the prompt lists them on every render.
+func (m *model) openTemplatePrompt() {
+	templates, err := loadTemplates()
+	if err != nil {
+		m.statusMsg = err.Error()
+		return
+	}
+	if len(templates) == 0 {
+		m.statusMsg = "No templates yet, add them to " + templatesPath()
+		return
+	}
+	m.templates = templates
+	m.inputActive = true
+	m.inputAction = "template"
+	m.input.Placeholder = "Template number or name"
+	m.input.SetValue("")
+	m.input.Focus()
+}
@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// timerTickMsg refreshes the elapsed time shown for a running timer
+type timerTickMsg time.Time
+
+func timerTick() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return timerTickMsg(t)
+	})
+}
+
+// timeSpent returns the time tracked on a task, including a running timer
+func timeSpent(task Task, now time.Time) time.Duration {
+	if task.TimerStarted.IsZero() {
+		return task.TimeSpent
+	}
+	return task.TimeSpent + now.Sub(task.TimerStarted)
+}
+
+// stopTimer adds a running timer's elapsed time to the task's total
+func stopTimer(task *Task, now time.Time) {
+	if task.TimerStarted.IsZero() {
+		return
+	}
+	task.TimeSpent += now.Sub(task.TimerStarted)
+	task.TimerStarted = time.Time{}
+	task.Updated = now
+}
+
+// runningTimer returns the task whose timer is running, if any
+func (m *model) runningTimer() *Task {
+	var running *Task
+	var walk func(tasks []Task)
+	walk = func(tasks []Task) {
+		for i := range tasks {
+			if running != nil {
+				return
+			}
+			if !tasks[i].TimerStarted.IsZero() {
+				running = &tasks[i]
+				return
+			}
+			walk(tasks[i].Tasks)
+		}
+	}
+	walk(m.tasks)
+	walk(m.completedTasks)
+	return running
+}
+
+// toggleTimer starts or stops the timer on the selected task. Only one timer
+// runs at a time, so starting one stops any other.
+func (m *model) toggleTimer() tea.Cmd {
+	task := m.selectedTask()
+	if task == nil {
+		return nil
+	}
+	now := time.Now()
+
+	if !task.TimerStarted.IsZero() {
+		stopTimer(task, now)
+		m.statusMsg = fmt.Sprintf("Stopped timer on %s", task.Title)
+		m.syncToGoogle(*task)
+		m.saveTimers()
+		return nil
+	}
+
+	if running := m.runningTimer(); running != nil {
+		stopTimer(running, now)
+		m.syncToGoogle(*running)
+	}
+	task.TimerStarted = now
+	task.Updated = now
+	m.syncToGoogle(*task)
+	m.saveTimers()
+
+	if m.timerTicking {
+		return nil
+	}
+	m.timerTicking = true
+	return timerTick()
+}
+
+func (m *model) saveTimers() {
+	m.saveTasks()
+}
+
+// formatTimeSpent renders a duration to the second, e.g. "1h2m3s"
+func formatTimeSpent(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TitleMatch is an open task whose title matches a query
+type TitleMatch struct {
+	Task Task
+	List string // Title of the list the task is in
+}
+
+// MatchTasksByTitle finds the open tasks whose title matches query,
+// ignoring case, across all lists. Exact matches win over prefix matches,
+// which win over substring matches, so "milk" finds "Milk" even when "Buy
+// milk" exists too.
+func MatchTasksByTitle(tasks []Task, query string) []TitleMatch {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+
+	var tiers [3][]TitleMatch
+	var walk func(tasks []Task, list string)
+	walk = func(tasks []Task, list string) {
+		for _, task := range tasks {
+			if task.Deleted {
+				continue
+			}
+			if task.Kind == "tasks#taskList" {
+				walk(task.Tasks, task.Title)
+				continue
+			}
+			if !task.Completed {
+				title := strings.ToLower(task.Title)
+				match := TitleMatch{Task: task, List: list}
+				switch {
+				case title == query:
+					tiers[0] = append(tiers[0], match)
+				case strings.HasPrefix(title, query):
+					tiers[1] = append(tiers[1], match)
+				case strings.Contains(title, query):
+					tiers[2] = append(tiers[2], match)
+				}
+			}
+			walk(task.Tasks, list)
+		}
+	}
+	walk(tasks, "")
+
+	for _, tier := range tiers {
+		if len(tier) > 0 {
+			return tier
+		}
+	}
+	return nil
+}
+
+// AmbiguousMatchError lists the tasks a title query could mean
+type AmbiguousMatchError struct {
+	Query   string
+	Matches []TitleMatch
+}
+
+func (e *AmbiguousMatchError) Error() string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "%d open tasks match %q, use one of their IDs:", len(e.Matches), e.Query)
+	for _, match := range e.Matches {
+		fmt.Fprintf(&s, "\n  %s  %s", match.Task.Id, match.Task.Title)
+		if match.List != "" {
+			fmt.Fprintf(&s, " (%s)", match.List)
+		}
+	}
+	return s.String()
+}
+
+// FindTaskByTitle returns the one open task matching query, or an error
+// naming the candidates when there are none or several
+func FindTaskByTitle(tasks []Task, query string) (Task, error) {
+	matches := MatchTasksByTitle(tasks, query)
+	switch len(matches) {
+	case 0:
+		return Task{}, fmt.Errorf("no open task matches %q", query)
+	case 1:
+		return matches[0].Task, nil
+	}
+	return Task{}, &AmbiguousMatchError{Query: query, Matches: matches}
+}
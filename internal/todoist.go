@@ -0,0 +1,308 @@
+package internal
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// todoistID accepts both the numeric IDs of older exports and the string IDs of newer ones
+type todoistID string
+
+func (id *todoistID) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*id = ""
+		return nil
+	}
+	*id = todoistID(strings.Trim(string(data), `"`))
+	return nil
+}
+
+// todoistExport is the subset of a Todoist JSON backup godo understands
+type todoistExport struct {
+	Projects []struct {
+		ID   todoistID `json:"id"`
+		Name string    `json:"name"`
+	} `json:"projects"`
+	Items []todoistItem `json:"items"`
+}
+
+type todoistItem struct {
+	ID          todoistID   `json:"id"`
+	ProjectID   todoistID   `json:"project_id"`
+	ParentID    todoistID   `json:"parent_id"`
+	SectionID   todoistID   `json:"section_id"`
+	Content     string      `json:"content"`
+	Description string      `json:"description"`
+	Priority    int         `json:"priority"`
+	Checked     bool        `json:"checked"`
+	Labels      []string    `json:"labels"`
+	ChildOrder  int         `json:"child_order"`
+	AddedAt     string      `json:"added_at"`
+	CompletedAt string      `json:"completed_at"`
+	Due         *todoistDue `json:"due"`
+}
+
+type todoistDue struct {
+	Date   string `json:"date"`
+	String string `json:"string"`
+}
+
+// TodoistOrphanList holds imported items whose project isn't in the export
+const TodoistOrphanList = "Imported"
+
+// ImportTodoist reads a Todoist export (JSON backup or CSV project template)
+// and returns one task list per project with the item hierarchy preserved.
+// It also returns how many items referred to a parent or project missing
+// from the export; those are kept at the top of their project, or in the
+// TodoistOrphanList list.
+func ImportTodoist(path string) ([]Task, int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read Todoist export: %v", err)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		lists, err := importTodoistCSV(name, string(data))
+		return lists, 0, err
+	}
+	return importTodoistJSON(data)
+}
+
+func importTodoistJSON(data []byte) ([]Task, int, error) {
+	var export todoistExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse Todoist export: %v", err)
+	}
+
+	sort.SliceStable(export.Items, func(i, j int) bool {
+		return export.Items[i].ChildOrder < export.Items[j].ChildOrder
+	})
+
+	projects := make(map[todoistID]bool)
+	for _, project := range export.Projects {
+		projects[project.ID] = true
+	}
+	items := make(map[todoistID]bool)
+	for _, item := range export.Items {
+		items[item.ID] = true
+	}
+
+	// Group children under their parent item and top-level items under their
+	// project. Items whose parent is missing go to the top of their project,
+	// and top-level items of a missing project to the orphan list.
+	children := make(map[todoistID][]todoistItem)
+	roots := make(map[todoistID][]todoistItem)
+	var orphans []todoistItem
+	orphaned := 0
+	for _, item := range export.Items {
+		switch {
+		case item.ParentID != "" && items[item.ParentID]:
+			children[item.ParentID] = append(children[item.ParentID], item)
+		case !projects[item.ProjectID]:
+			orphans = append(orphans, item)
+			orphaned++
+		default:
+			if item.ParentID != "" {
+				orphaned++
+			}
+			roots[item.ProjectID] = append(roots[item.ProjectID], item)
+		}
+	}
+
+	var build func(items []todoistItem) []Task
+	build = func(items []todoistItem) []Task {
+		tasks := make([]Task, 0, len(items))
+		for _, item := range items {
+			task := todoistItemToTask(item)
+			task.Tasks = build(children[item.ID])
+			tasks = append(tasks, task)
+		}
+		return tasks
+	}
+
+	var lists []Task
+	for _, project := range export.Projects {
+		lists = append(lists, newImportedList(project.Name, build(roots[project.ID])))
+	}
+	if len(orphans) > 0 {
+		lists = append(lists, newImportedList(TodoistOrphanList, build(orphans)))
+	}
+	return lists, orphaned, nil
+}
+
+// todoistItemToTask maps a Todoist item, keeping anything without a Task field in Notes
+func todoistItemToTask(item todoistItem) Task {
+	now := time.Now()
+	task := Task{
+		Id:        generateID(),
+		Title:     item.Content,
+		Notes:     item.Description,
+		Priority:  todoistPriority(item.Priority),
+		Tags:      item.Labels,
+		Completed: item.Checked,
+		Status:    "needsAction",
+		Kind:      "tasks#task",
+		CreatedAt: now,
+		Created:   now,
+		Updated:   now,
+	}
+
+	if added, err := time.Parse(time.RFC3339, item.AddedAt); err == nil {
+		task.CreatedAt = added
+		task.Created = added
+	}
+	if task.Completed {
+		task.Status = "completed"
+		if completed, err := time.Parse(time.RFC3339, item.CompletedAt); err == nil {
+			task.CompletedDate = completed
+		}
+	}
+
+	var extra []string
+	if item.Due != nil {
+		if due, ok := parseTodoistDate(item.Due.Date); ok {
+			task.DueDate = due
+		}
+		// Keep wording like "every monday" that a plain date can't express
+		if item.Due.String != "" && item.Due.String != item.Due.Date {
+			extra = append(extra, "Todoist due: "+item.Due.String)
+		}
+	}
+	if item.SectionID != "" {
+		extra = append(extra, "Todoist section: "+string(item.SectionID))
+	}
+	if len(extra) > 0 {
+		if task.Notes != "" {
+			task.Notes += "\n\n"
+		}
+		task.Notes += strings.Join(extra, "\n")
+	}
+
+	return task
+}
+
+// todoistPriority maps API priorities, where 4 is the most urgent, to godo levels
+func todoistPriority(priority int) string {
+	switch priority {
+	case 4:
+		return PriorityHigh
+	case 3:
+		return PriorityMedium
+	case 2:
+		return PriorityLow
+	}
+	return ""
+}
+
+func parseTodoistDate(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// importTodoistCSV reads a project template CSV, where INDENT gives the nesting
+// and PRIORITY counts from 1 (most urgent)
+func importTodoistCSV(projectName, data string) ([]Task, error) {
+	records, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Todoist CSV: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("empty Todoist CSV")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToUpper(strings.TrimSpace(name))] = i
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	type indentedTask struct {
+		task   Task
+		indent int
+	}
+	var rows []indentedTask
+	for _, record := range records[1:] {
+		if !strings.EqualFold(field(record, "TYPE"), "task") {
+			continue
+		}
+
+		priority, _ := strconv.Atoi(field(record, "PRIORITY"))
+		item := todoistItem{
+			Content:     field(record, "CONTENT"),
+			Description: field(record, "DESCRIPTION"),
+			Priority:    5 - priority,
+		}
+		if date := field(record, "DATE"); date != "" {
+			item.Due = &todoistDue{String: date}
+			if _, ok := parseTodoistDate(date); ok {
+				item.Due.Date = date
+			}
+		}
+
+		indent, err := strconv.Atoi(field(record, "INDENT"))
+		if err != nil || indent < 1 {
+			indent = 1
+		}
+		rows = append(rows, indentedTask{task: todoistItemToTask(item), indent: indent})
+	}
+
+	// nest collects consecutive rows at the given indent, attaching deeper rows as children
+	var nest func(start, indent int) ([]Task, int)
+	nest = func(start, indent int) ([]Task, int) {
+		var tasks []Task
+		i := start
+		for i < len(rows) && rows[i].indent >= indent {
+			task := rows[i].task
+			task.Tasks, i = nest(i+1, indent+1)
+			tasks = append(tasks, task)
+		}
+		return tasks, i
+	}
+	roots, _ := nest(0, 1)
+
+	return []Task{newImportedList(projectName, roots)}, nil
+}
+
+// newImportedList wraps imported tasks in a top-level task, which local mode
+// shows as a list, pointing every task at its parent as creating them in
+// godo would
+func newImportedList(title string, tasks []Task) Task {
+	now := time.Now()
+	list := Task{
+		Id:        generateID(),
+		Title:     title,
+		Kind:      "tasks#task",
+		Status:    "needsAction",
+		CreatedAt: now,
+		Created:   now,
+		Updated:   now,
+		Tasks:     tasks,
+	}
+	setParents(list.Tasks, list.Id)
+	return list
+}
+
+// setParents points each task at parentID and their subtasks at them
+func setParents(tasks []Task, parentID string) {
+	for i := range tasks {
+		tasks[i].Parent = parentID
+		setParents(tasks[i].Tasks, tasks[i].Id)
+	}
+}
@@ -0,0 +1,41 @@
+package internal
+
+import "testing"
+
+func TestImportTodoistJSON(t *testing.T) {
+	export := `{
+		"projects": [{"id": "p1", "name": "Home"}],
+		"items": [
+			{"id": "a", "project_id": "p1", "content": "Parent", "child_order": 1},
+			{"id": "b", "project_id": "p1", "parent_id": "a", "content": "Child", "child_order": 1},
+			{"id": "c", "project_id": "p1", "parent_id": "gone", "content": "Lost parent", "child_order": 2},
+			{"id": "d", "project_id": "gone", "content": "Lost project", "child_order": 3}
+		]
+	}`
+
+	lists, orphaned, err := importTodoistJSON([]byte(export))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if orphaned != 2 {
+		t.Errorf("reported %d orphans, want 2", orphaned)
+	}
+	if len(lists) != 2 || lists[0].Title != "Home" || lists[1].Title != TodoistOrphanList {
+		t.Fatalf("got lists %v, want Home and %s", treeShape(lists), TodoistOrphanList)
+	}
+
+	home := lists[0]
+	if len(home.Tasks) != 2 || home.Tasks[0].Title != "Parent" || home.Tasks[1].Title != "Lost parent" {
+		t.Fatalf("Home holds %v, want Parent and Lost parent", treeShape(home.Tasks))
+	}
+	parent := home.Tasks[0]
+	if parent.Parent != home.Id {
+		t.Errorf("Parent points at %q, want its list %q", parent.Parent, home.Id)
+	}
+	if len(parent.Tasks) != 1 || parent.Tasks[0].Parent != parent.Id {
+		t.Errorf("Child isn't nested under Parent with Parent set: %+v", parent.Tasks)
+	}
+	if orphans := lists[1].Tasks; len(orphans) != 1 || orphans[0].Title != "Lost project" {
+		t.Errorf("%s holds %v, want Lost project", TodoistOrphanList, treeShape(orphans))
+	}
+}
@@ -0,0 +1,169 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	tombstoneMu sync.Mutex
+	tombstones  map[string]time.Time // Task ID -> when it was deleted locally
+)
+
+// loadTombstones reads the tombstone registry from the storage directory
+func loadTombstones() error {
+	tombstoneMu.Lock()
+	defer tombstoneMu.Unlock()
+
+	tombstones = make(map[string]time.Time)
+	path, err := storageFile("tombstones.json")
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("error reading tombstones: %v", err)
+	}
+	if err := json.Unmarshal(data, &tombstones); err != nil {
+		return fmt.Errorf("error parsing tombstones: %v", err)
+	}
+	return nil
+}
+
+// saveTombstonesLocked writes the registry; callers must hold tombstoneMu
+func saveTombstonesLocked() error {
+	path, err := storageFile("tombstones.json")
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(tombstones, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling tombstones: %v", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// recordTombstone remembers that a task was deleted so sync won't resurrect it
+func recordTombstone(id string, deletedAt time.Time) error {
+	tombstoneMu.Lock()
+	defer tombstoneMu.Unlock()
+
+	if tombstones == nil {
+		tombstones = make(map[string]time.Time)
+	}
+	tombstones[id] = deletedAt
+	return saveTombstonesLocked()
+}
+
+// tombstoneGracePeriod is how long tombstones are kept before being purged
+func tombstoneGracePeriod() time.Duration {
+	days := 30
+	if config := GetGlobalConfig(); config != nil && config.TombstoneDays > 0 {
+		days = config.TombstoneDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// applyTombstones merges local deletions into freshly fetched tasks. A
+// tombstone newer than the remote edit wins: the task is marked deleted and
+// the delete is re-sent. A remote edit after the deletion drops the tombstone.
+func applyTombstones(tasks []Task) []Task {
+	for _, del := range markTombstoned(tasks) {
+		if GoogleTasksClientVar != nil {
+			if err := GoogleTasksClientVar.DeleteTask(del.taskID, del.listID); err != nil {
+				logError("Error re-deleting task %s: %v", del.taskID, err)
+			}
+		}
+	}
+	return tasks
+}
+
+// pendingDelete is a task to delete again in Google
+type pendingDelete struct {
+	taskID, listID string
+}
+
+// markTombstoned marks the tombstoned tasks in tasks as deleted and returns
+// the deletes to re-send, which are made after unlocking so recording a new
+// tombstone never waits on the network
+func markTombstoned(tasks []Task) []pendingDelete {
+	tombstoneMu.Lock()
+	defer tombstoneMu.Unlock()
+
+	if len(tombstones) == 0 {
+		return nil
+	}
+
+	var deletes []pendingDelete
+	changed := false
+	var walk func(tasks []Task, listID string)
+	walk = func(tasks []Task, listID string) {
+		for i := range tasks {
+			task := &tasks[i]
+			if deletedAt, ok := tombstones[task.Id]; ok {
+				if deletedAt.After(task.Updated) {
+					task.Deleted = true
+					task.DeletedAt = deletedAt
+					deletes = append(deletes, pendingDelete{task.Id, listID})
+				} else {
+					delete(tombstones, task.Id)
+					changed = true
+				}
+			}
+			if task.Kind == "tasks#taskList" {
+				walk(task.Tasks, task.Id)
+			} else {
+				walk(task.Tasks, listID)
+			}
+		}
+	}
+	walk(tasks, "")
+
+	if changed {
+		if err := saveTombstonesLocked(); err != nil {
+			logError("Error saving tombstones: %v", err)
+		}
+	}
+	return deletes
+}
+
+// purgeTombstones forgets deletions older than the grace period
+func purgeTombstones() {
+	tombstoneMu.Lock()
+	defer tombstoneMu.Unlock()
+
+	cutoff := time.Now().Add(-tombstoneGracePeriod())
+	changed := false
+	for id, deletedAt := range tombstones {
+		if deletedAt.Before(cutoff) {
+			delete(tombstones, id)
+			changed = true
+		}
+	}
+	if changed {
+		if err := saveTombstonesLocked(); err != nil {
+			logError("Error saving tombstones: %v", err)
+		}
+	}
+}
+
+// pruneDeletedTasks drops tombstoned tasks older than the grace period from a tree
+func pruneDeletedTasks(tasks []Task) []Task {
+	cutoff := time.Now().Add(-tombstoneGracePeriod())
+	kept := make([]Task, 0, len(tasks))
+	for _, task := range tasks {
+		if task.Deleted && task.DeletedAt.Before(cutoff) {
+			continue
+		}
+		task.Tasks = pruneDeletedTasks(task.Tasks)
+		kept = append(kept, task)
+	}
+	return kept
+}
@@ -0,0 +1,301 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// trashedTask is a deleted task, with its subtasks, kept so it can be restored
+type trashedTask struct {
+	ListID    string    `json:"listId"`
+	ParentID  string    `json:"parentId"`
+	DeletedAt time.Time `json:"deletedAt"`
+	Task      Task      `json:"task"`
+}
+
+// trashMu serializes reads and writes of the trash file
+var trashMu sync.Mutex
+
+// trashRetention is how long deleted tasks stay in the trash
+func trashRetention() time.Duration {
+	days := 30
+	if config := GetGlobalConfig(); config != nil && config.TrashDays > 0 {
+		days = config.TrashDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// loadTrash reads the active profile's trash, newest first, purging items
+// older than the retention period
+func loadTrash(now time.Time) ([]trashedTask, error) {
+	trashMu.Lock()
+	defer trashMu.Unlock()
+
+	path, err := storageFile(profileFileName("trash"))
+	if err != nil {
+		return nil, err
+	}
+	var items []trashedTask
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, fmt.Errorf("error parsing trash: %v", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading trash: %v", err)
+	}
+
+	cutoff := now.Add(-trashRetention())
+	kept := items[:0]
+	for _, item := range items {
+		if item.DeletedAt.After(cutoff) {
+			kept = append(kept, item)
+		}
+	}
+	if len(kept) < len(items) {
+		if err := saveTrashLocked(kept); err != nil {
+			return nil, err
+		}
+	}
+	return kept, nil
+}
+
+// saveTrash replaces the active profile's trash
+func saveTrash(items []trashedTask) error {
+	trashMu.Lock()
+	defer trashMu.Unlock()
+	return saveTrashLocked(items)
+}
+
+// saveTrashLocked writes the trash; callers must hold trashMu
+func saveTrashLocked(items []trashedTask) error {
+	path, err := storageFile(profileFileName("trash"))
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling trash: %v", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// trashTask puts a copy of a task about to be deleted in the trash,
+// remembering its list and parent so it can go back where it was
+func (m *model) trashTask(id string, now time.Time) error {
+	path, found := findTaskByID(m.allTasks(), id)
+	if !found {
+		return nil
+	}
+	item := trashedTask{DeletedAt: now, Task: path[len(path)-1]}
+	if path[0].Kind == "tasks#taskList" && len(path) > 1 {
+		item.ListID = path[0].Id
+	}
+	if len(path) > 1 {
+		item.ParentID = path[len(path)-2].Id
+	}
+
+	items, err := loadTrash(now)
+	if err != nil {
+		return err
+	}
+	return saveTrash(append([]trashedTask{item}, items...))
+}
+
+// openTrash shows the deleted tasks
+func (m *model) openTrash() {
+	items, err := loadTrash(time.Now())
+	if err != nil {
+		m.statusMsg = "Couldn't open the trash: " + err.Error()
+		return
+	}
+	m.trash = items
+	m.trashCursor = 0
+	m.trashConfirm = false
+	m.showTrash = true
+}
+
+// trashKey handles keys while the trash is open
+func (m *model) trashKey(key string) {
+	confirm := m.trashConfirm
+	m.trashConfirm = false
+	switch key {
+	case "u", "esc":
+		m.showTrash = false
+	case "down", "j":
+		if m.trashCursor < len(m.trash)-1 {
+			m.trashCursor++
+		}
+	case "up", "k":
+		if m.trashCursor > 0 {
+			m.trashCursor--
+		}
+	case "enter", "r":
+		if m.trashCursor < len(m.trash) {
+			m.restoreTrashed(m.trashCursor)
+		}
+	case "x":
+		if m.trashCursor < len(m.trash) {
+			m.removeTrashed(m.trashCursor)
+		}
+	case "X":
+		if len(m.trash) == 0 {
+			return
+		}
+		if !confirm {
+			m.trashConfirm = true
+			return
+		}
+		m.trash = nil
+		m.trashCursor = 0
+		if err := saveTrash(m.trash); err != nil {
+			logError("Error emptying trash: %v", err)
+		}
+	}
+}
+
+// removeTrashed drops one item from the trash for good
+func (m *model) removeTrashed(i int) {
+	m.trash = append(m.trash[:i:i], m.trash[i+1:]...)
+	if m.trashCursor >= len(m.trash) {
+		m.trashCursor = max(len(m.trash)-1, 0)
+	}
+	if err := saveTrash(m.trash); err != nil {
+		logError("Error saving trash: %v", err)
+	}
+}
+
+// taskRestoredMsg reports how recreating a trashed task in Google, done
+// in the background, went
+type taskRestoredMsg struct {
+	item     trashedTask // The trash entry being restored
+	listID   string      // The list the copy was created in
+	parentID string      // Its parent task, empty at the top of the list
+	created  Task        // The copy, with its new IDs
+	err      error
+}
+
+// restoreTrashed puts a trashed task back under its old parent, or at the
+// top of its list if the parent is gone too. In Google mode it is created
+// again in the background, since Google has deleted the original, and
+// finishRestore places the copy.
+func (m *model) restoreTrashed(i int) {
+	item := m.trash[i]
+
+	parent := m.findTask(item.ParentID)
+	if parent != nil && parent.Deleted {
+		parent = nil
+	}
+	if parent == nil && item.ListID != "" {
+		if list := m.findTask(item.ListID); list != nil && !list.Deleted {
+			parent = list
+		}
+	}
+	parentID := ""
+	if parent != nil && parent.Kind != "tasks#taskList" {
+		parentID = parent.Id
+	}
+	// Taken out now so it can't be restored twice; a failure puts it back
+	m.removeTrashed(i)
+
+	if m.googleTasks == nil {
+		task := item.Task
+		task.Parent = parentID
+		m.placeRestored(parent, task)
+		return
+	}
+
+	client, listID := m.googleTasks, item.ListID
+	if parent == nil || m.findTask(listID) == nil {
+		// The first list is looked up in the background
+		listID, parentID = "", ""
+	}
+	task := cloneTasks([]Task{item.Task})[0]
+	m.statusMsg = "Restoring " + task.Title + "..."
+	goSync(func() {
+		msg := taskRestoredMsg{item: item, listID: listID, parentID: parentID}
+		if msg.listID == "" {
+			msg.listID, msg.err = client.firstListID()
+		}
+		if msg.err == nil {
+			msg.created, msg.err = recreateTree(client, msg.listID, parentID, task)
+		}
+		sendToUI(msg)
+	})
+}
+
+// finishRestore places a task once Google has the copy, or puts it back in
+// the trash if that failed
+func (m *model) finishRestore(msg taskRestoredMsg) {
+	if msg.err != nil {
+		m.trash = append(m.trash, msg.item)
+		sort.SliceStable(m.trash, func(i, j int) bool {
+			return m.trash[i].DeletedAt.After(m.trash[j].DeletedAt)
+		})
+		if err := saveTrash(m.trash); err != nil {
+			logError("Error saving trash: %v", err)
+		}
+		m.statusMsg = "Restore failed: " + msg.err.Error()
+		return
+	}
+
+	parentID := msg.parentID
+	if parentID == "" {
+		parentID = msg.listID
+	}
+	parent := m.findTask(parentID)
+	if parent != nil && parent.Deleted {
+		parent = nil
+	}
+	m.placeRestored(parent, msg.created)
+}
+
+// placeRestored adds a restored task under parent, or at the top level when
+// there is none, where a completed one belongs with the completed tasks
+func (m *model) placeRestored(parent *Task, task Task) {
+	switch {
+	case parent != nil:
+		parent.Tasks = append(parent.Tasks, task)
+	case task.Completed:
+		m.completedTasks = append(m.completedTasks, task)
+	default:
+		m.tasks = append(m.tasks, task)
+	}
+	m.saveTasks()
+	m.statusMsg = "Restored " + task.Title
+}
+
+// renderTrash lists the trashed tasks, newest first
+func (m *model) renderTrash() string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	var s strings.Builder
+	s.WriteString(lipgloss.NewStyle().Bold(true).Render("Trash") + "\n\n")
+	if len(m.trash) == 0 {
+		s.WriteString(dim.Render("  The trash is empty") + "\n")
+	}
+	for i, item := range m.trash {
+		cursor := " "
+		if i == m.trashCursor {
+			cursor = ">"
+		}
+		line := fmt.Sprintf("%s %s", cursor, item.Task.Title)
+		if _, total := subtaskProgress(item.Task); total > 0 {
+			line += fmt.Sprintf(" (+%d subtasks)", total)
+		}
+		s.WriteString(line + "  " + dim.Render("deleted "+formatDate(item.DeletedAt)) + "\n")
+	}
+
+	help := fmt.Sprintf("j/k: Select  Enter/r: Restore  x: Delete forever  X: Empty trash  u/esc: Close  (kept %d days)", int(trashRetention().Hours()/24))
+	if m.trashConfirm {
+		help = "Press X again to empty the trash for good"
+	}
+	s.WriteString("\n" + dim.Render(help))
+	return s.String()
+}
@@ -0,0 +1,165 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// treeRow is one visible line of the inline tree view
+type treeRow struct {
+	task  Task
+	depth int
+}
+
+// treeRows flattens the current level into the rows visible in the inline
+// tree view, descending only into tasks that have been expanded
+func (m *model) treeRows() []treeRow {
+	active, completed := m.getCurrentTasks()
+
+	var rows []treeRow
+	var walk func(tasks []Task, depth int)
+	walk = func(tasks []Task, depth int) {
+		for _, task := range tasks {
+			rows = append(rows, treeRow{task: task, depth: depth})
+			if m.expanded[task.Id] && len(task.Tasks) > 0 {
+				childActive, childCompleted := splitTasks(task.Tasks)
+				walk(childActive, depth+1)
+				walk(childCompleted, depth+1)
+			}
+		}
+	}
+	walk(active, 0)
+	walk(completed, 0)
+
+	return rows
+}
+
+// visibleCount returns how many rows the cursor can move across
+func (m *model) visibleCount() int {
+	if m.treeView {
+		return len(m.treeRows())
+	}
+	active, completed := m.getCurrentTasks()
+	return len(active) + len(completed)
+}
+
+// setExpanded expands or collapses the task under the cursor in tree view
+func (m *model) setExpanded(expand bool) {
+	rows := m.treeRows()
+	if m.cursor >= len(rows) {
+		return
+	}
+	task := rows[m.cursor].task
+	if len(task.Tasks) == 0 {
+		return
+	}
+	if expand {
+		m.expanded[task.Id] = true
+	} else {
+		delete(m.expanded, task.Id)
+	}
+}
+
+// setAllExpanded expands or collapses every task at the current level and
+// below. The cursor stays on the same task, or moves to its closest
+// ancestor that is still visible after a collapse.
+func (m *model) setAllExpanded(expand bool) {
+	rows := m.treeRows()
+	var keep []string
+	if m.cursor < len(rows) {
+		// The selected task first, then its ancestors from nearest to farthest
+		depth := rows[m.cursor].depth + 1
+		for i := m.cursor; i >= 0 && depth > 0; i-- {
+			if rows[i].depth < depth {
+				keep = append(keep, rows[i].task.Id)
+				depth = rows[i].depth
+			}
+		}
+	}
+
+	var walk func(tasks []Task)
+	walk = func(tasks []Task) {
+		for _, task := range tasks {
+			if len(task.Tasks) == 0 {
+				continue
+			}
+			if expand {
+				m.expanded[task.Id] = true
+			} else {
+				delete(m.expanded, task.Id)
+			}
+			walk(task.Tasks)
+		}
+	}
+	active, completed := m.getCurrentTasks()
+	walk(active)
+	walk(completed)
+
+	rows = m.treeRows()
+	for _, id := range keep {
+		for i, row := range rows {
+			if row.task.Id == id {
+				m.cursor = i
+				return
+			}
+		}
+	}
+	m.cursor = min(m.cursor, max(len(rows)-1, 0))
+}
+
+// renderTreeRow formats one tree row with indentation and an expand marker
+func (m *model) renderTreeRow(row treeRow, selected bool, now time.Time, wt workingTime) string {
+	cursor := " "
+	if selected {
+		cursor = ">"
+	}
+
+	marker := "  "
+	if len(row.task.Tasks) > 0 {
+		marker = "▶ "
+		if m.expanded[row.task.Id] {
+			marker = "▼ "
+		}
+	}
+
+	markers := ""
+	if row.depth == 0 && (row.task.Pinned || (len(m.currentPath) == 0 && isPinned(row.task.Id))) {
+		markers = "📌 " + markers
+	}
+	if row.task.Id == m.linkFrom {
+		markers = linkMarker + markers
+	}
+	style := lipgloss.NewStyle()
+	if row.task.Completed {
+		markers = "✓ " + markers
+		style = completedStyle
+	} else if m.isBlocked(row.task) {
+		markers = "🔒 " + markers
+		style = blockedStyle
+	} else if isOverdue(row.task, now, wt) {
+		style = overdueStyle
+	} else if row.task.InProgress {
+		markers = inProgressMarker + markers
+		style = inProgressStyle
+	} else if isStale(row.task, now) {
+		markers = staleMarker + markers
+		style = staleStyle
+	}
+	if selected {
+		style = style.Foreground(m.accentColor())
+	}
+
+	// The expand marker already shows there are subtasks, so only add the count
+	badge := ""
+	if row.depth == 0 && len(m.currentPath) == 0 {
+		badge = listBadge(row.task)
+	} else if done, total := subtaskProgress(row.task); total > 0 {
+		badge = fmt.Sprintf(" %d/%d", done, total)
+	}
+	prefix := fmt.Sprintf("%s %s%s%s%s", cursor, strings.Repeat("  ", row.depth), marker, colorFlag(row.task.Color), priorityMarker(row.task.Priority))
+	title := markers + fitTitle(row.task.Title, badge, m.titleWidth(prefix+markers))
+	return prefix + style.Render(title)
+}
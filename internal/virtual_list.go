@@ -0,0 +1,44 @@
+package internal
+
+import "strings"
+
+// virtualList renders a window of a long list, formatting only the lines
+// that fit and scrolling so the cursor stays centered
+type virtualList struct {
+	count  int // Total number of lines
+	cursor int // Line the cursor is on
+	height int // Lines available, including the scroll indicators
+}
+
+// window returns the half-open range of lines to show. A line is held back
+// for each scroll indicator that will be drawn.
+func (v virtualList) window() (start, end int) {
+	height := max(v.height, 1)
+	if v.count <= height {
+		return 0, v.count
+	}
+
+	// Both indicators may show once the list has to scroll
+	height = max(height-2, 1)
+	start = v.cursor - height/2
+	start = max(min(start, v.count-height), 0)
+	return start, start + height
+}
+
+// render formats the visible lines with line(i), adding scroll indicators
+// when lines are cut off above or below
+func (v virtualList) render(line func(i int) string) string {
+	start, end := v.window()
+
+	var s strings.Builder
+	if start > 0 {
+		s.WriteString("↑ More tasks above\n")
+	}
+	for i := start; i < end; i++ {
+		s.WriteString(line(i) + "\n")
+	}
+	if end < v.count {
+		s.WriteString("↓ More tasks below\n")
+	}
+	return s.String()
+}
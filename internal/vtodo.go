@@ -0,0 +1,306 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// iCalendar date formats used by VTODO properties
+const (
+	icalDateTime = "20060102T150405Z"
+	icalDate     = "20060102"
+)
+
+// todoFromICal reads the first VTODO of an iCalendar object into a task.
+// The parent comes from RELATED-TO, and godo-only fields from the metadata
+// line in DESCRIPTION, as with Google. Components inside the VTODO, such as
+// alarms, are skipped.
+func todoFromICal(data string) (Task, bool) {
+	task := Task{Kind: "tasks#task", Status: "needsAction", Tasks: []Task{}}
+	inTodo, found := false, false
+	nested := 0
+	for _, line := range unfoldICal(data) {
+		name, params, value := parseICalLine(line)
+		switch {
+		case name == "BEGIN" && value == "VTODO" && !found:
+			inTodo, found = true, true
+			continue
+		case name == "END" && value == "VTODO":
+			inTodo = false
+		case inTodo && name == "BEGIN":
+			nested++
+		case inTodo && name == "END":
+			nested--
+			continue
+		}
+		if !inTodo || nested > 0 {
+			continue
+		}
+
+		switch name {
+		case "UID":
+			task.Id = value
+		case "SUMMARY":
+			task.Title = unescapeICal(value)
+		case "DESCRIPTION":
+			task.Notes = unescapeICal(value)
+		case "DUE":
+			task.DueDate = parseICalTime(value)
+		case "STATUS":
+			switch value {
+			case "COMPLETED":
+				task.Completed = true
+				task.Status = "completed"
+			case "IN-PROCESS":
+				task.InProgress = true
+			}
+		case "COMPLETED":
+			task.CompletedDate = parseICalTime(value)
+		case "CREATED":
+			task.Created = parseICalTime(value)
+			task.CreatedAt = task.Created
+		case "LAST-MODIFIED":
+			task.Updated = parseICalTime(value)
+		case "PRIORITY":
+			task.Priority = priorityFromICal(value)
+		case "RELATED-TO":
+			if reltype, ok := params["RELTYPE"]; !ok || reltype == "PARENT" {
+				task.Parent = value
+			}
+		}
+	}
+	if !found || task.Id == "" {
+		return Task{}, false
+	}
+
+	// Native fields win over the copies kept in the metadata line
+	priority, inProgress := task.Priority, task.InProgress
+	decodeNotes(&task)
+	if priority != "" {
+		task.Priority = priority
+	}
+	task.InProgress = inProgress
+	return task, true
+}
+
+// todoToICal writes a task as an iCalendar object holding one VTODO
+func todoToICal(task Task, now time.Time) string {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//godo//godo//EN",
+		"BEGIN:VTODO",
+	}
+	lines = append(lines, todoProperties(task, now, false)...)
+	lines = append(lines, "END:VTODO", "END:VCALENDAR")
+	return foldICalLines(lines)
+}
+
+// todoProperties lists the VTODO properties godo writes for a task. With
+// allDay the due date is written as a DATE rather than a DATE-TIME.
+func todoProperties(task Task, now time.Time, allDay bool) []string {
+	lines := []string{
+		"UID:" + task.Id,
+		"DTSTAMP:" + now.UTC().Format(icalDateTime),
+		"SUMMARY:" + escapeICal(task.Title),
+	}
+	// PRIORITY and STATUS carry these, so the metadata line needn't
+	native := task
+	native.Priority = ""
+	native.InProgress = false
+	if notes := encodeNotes(native); notes != "" {
+		lines = append(lines, "DESCRIPTION:"+escapeICal(notes))
+	}
+	if allDay {
+		lines = append(lines, "DUE;VALUE=DATE:"+task.DueDate.In(time.Local).Format(icalDate))
+	} else if !task.DueDate.IsZero() {
+		lines = append(lines, "DUE:"+task.DueDate.UTC().Format(icalDateTime))
+	}
+	if !task.Created.IsZero() {
+		lines = append(lines, "CREATED:"+task.Created.UTC().Format(icalDateTime))
+	}
+	if !task.Updated.IsZero() {
+		lines = append(lines, "LAST-MODIFIED:"+task.Updated.UTC().Format(icalDateTime))
+	}
+	if task.Completed {
+		lines = append(lines, "STATUS:COMPLETED")
+		if !task.CompletedDate.IsZero() {
+			lines = append(lines, "COMPLETED:"+task.CompletedDate.UTC().Format(icalDateTime))
+		}
+	} else if task.InProgress {
+		lines = append(lines, "STATUS:IN-PROCESS")
+	} else {
+		lines = append(lines, "STATUS:NEEDS-ACTION")
+	}
+	if priority := priorityToICal(task.Priority); priority != "" {
+		lines = append(lines, "PRIORITY:"+priority)
+	}
+	if task.Parent != "" {
+		lines = append(lines, "RELATED-TO;RELTYPE=PARENT:"+task.Parent)
+	}
+	return lines
+}
+
+// ownedProperty reports whether a VTODO property is one todoProperties
+// writes, and so replaced when godo updates the task
+func ownedProperty(name string, params map[string]string) bool {
+	switch name {
+	case "UID", "DTSTAMP", "SUMMARY", "DESCRIPTION", "DUE", "CREATED", "LAST-MODIFIED", "STATUS", "COMPLETED", "PRIORITY":
+		return true
+	case "RELATED-TO":
+		reltype, ok := params["RELTYPE"]
+		return !ok || reltype == "PARENT"
+	}
+	return false
+}
+
+// mergeTodo updates the first VTODO of data, an iCalendar object as the
+// server has it, with the fields of the task. Only the properties godo owns
+// are replaced, so alarms, recurrence rules, categories, start dates and
+// the X- properties of other clients survive, and an all-day due date stays
+// a DATE while it has no time of day.
+func mergeTodo(data string, task Task, now time.Time) string {
+	var lines []string
+	inTodo, merged, allDay := false, false, false
+	nested := 0
+	for _, line := range unfoldICal(data) {
+		name, params, value := parseICalLine(line)
+		switch {
+		case merged:
+		case name == "BEGIN" && value == "VTODO":
+			inTodo = true
+		case !inTodo:
+		case name == "END" && value == "VTODO":
+			due := task.DueDate.In(time.Local)
+			allDay = allDay && !due.IsZero() && due.Hour() == 0 && due.Minute() == 0 && due.Second() == 0
+			lines = append(lines, todoProperties(task, now, allDay)...)
+			inTodo, merged = false, true
+		case name == "BEGIN":
+			nested++
+		case name == "END":
+			nested--
+		case nested == 0 && ownedProperty(name, params):
+			if name == "DUE" {
+				allDay = params["VALUE"] == "DATE"
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if !merged {
+		return todoToICal(task, now)
+	}
+	return foldICalLines(lines)
+}
+
+// foldICalLines joins content lines into iCalendar text
+func foldICalLines(lines []string) string {
+	var s strings.Builder
+	for _, line := range lines {
+		s.WriteString(foldICal(line))
+	}
+	return s.String()
+}
+
+// unfoldICal splits iCalendar text into logical lines, joining the
+// continuation lines that start with a space or tab
+func unfoldICal(data string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// foldICal ends a line with CRLF, wrapping it at 75 bytes as RFC 5545 asks
+// without splitting a UTF-8 character
+func foldICal(line string) string {
+	var s strings.Builder
+	for len(line) > 75 {
+		cut := 75
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		s.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+	}
+	s.WriteString(line + "\r\n")
+	return s.String()
+}
+
+// parseICalLine splits a content line into its name, parameters and value
+func parseICalLine(line string) (string, map[string]string, string) {
+	head, value, _ := strings.Cut(line, ":")
+	parts := strings.Split(head, ";")
+	params := make(map[string]string)
+	for _, param := range parts[1:] {
+		if key, val, ok := strings.Cut(param, "="); ok {
+			params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+// parseICalTime reads a DATE or DATE-TIME value. Floating times are taken
+// as local time.
+func parseICalTime(value string) time.Time {
+	if t, err := time.Parse(icalDateTime, value); err == nil {
+		return t
+	}
+	if t, err := time.ParseInLocation("20060102T150405", value, time.Local); err == nil {
+		return t
+	}
+	if t, err := time.ParseInLocation(icalDate, value, time.Local); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
+var (
+	icalEscaper   = strings.NewReplacer(`\`, `\\`, "\n", `\n`, ";", `\;`, ",", `\,`)
+	icalUnescaper = strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\N`, "\n", `\;`, ";", `\,`, ",")
+)
+
+func escapeICal(text string) string   { return icalEscaper.Replace(text) }
+func unescapeICal(text string) string { return icalUnescaper.Replace(text) }
+
+// priorityFromICal maps VTODO priorities, 1 highest to 9 lowest with 0 for
+// none, onto godo's three levels
+func priorityFromICal(value string) string {
+	priority, err := strconv.Atoi(value)
+	switch {
+	case err != nil || priority <= 0:
+		return ""
+	case priority < 5:
+		return PriorityHigh
+	case priority == 5:
+		return PriorityMedium
+	}
+	return PriorityLow
+}
+
+// priorityToICal maps godo's priorities onto the usual VTODO values
+func priorityToICal(priority string) string {
+	switch priority {
+	case PriorityHigh:
+		return "1"
+	case PriorityMedium:
+		return "5"
+	case PriorityLow:
+		return "9"
+	}
+	return ""
+}
+
+// icalFileName is the resource name a new task is stored under
+func icalFileName(task Task) string {
+	return fmt.Sprintf("%s.ics", task.Id)
+}
@@ -0,0 +1,134 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTodoRoundTrip(t *testing.T) {
+	task := Task{
+		Id:            "abc",
+		Title:         "Call Bob; about the lease, again",
+		Notes:         "First line\nSecond line",
+		Parent:        "parent",
+		Priority:      PriorityHigh,
+		DueDate:       time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC),
+		Created:       time.Date(2024, 2, 1, 8, 0, 0, 0, time.UTC),
+		Updated:       time.Date(2024, 2, 2, 8, 0, 0, 0, time.UTC),
+		Completed:     true,
+		CompletedDate: time.Date(2024, 2, 3, 8, 0, 0, 0, time.UTC),
+	}
+
+	got, ok := todoFromICal(todoToICal(task, time.Now()))
+	if !ok {
+		t.Fatal("the VTODO written for the task couldn't be read back")
+	}
+	if got.Id != task.Id || got.Title != task.Title || got.Notes != task.Notes || got.Parent != task.Parent || got.Priority != task.Priority {
+		t.Errorf("got %q %q %q %q %q, want %q %q %q %q %q",
+			got.Id, got.Title, got.Notes, got.Parent, got.Priority,
+			task.Id, task.Title, task.Notes, task.Parent, task.Priority)
+	}
+	for name, pair := range map[string][2]time.Time{
+		"due":       {got.DueDate, task.DueDate},
+		"created":   {got.Created, task.Created},
+		"updated":   {got.Updated, task.Updated},
+		"completed": {got.CompletedDate, task.CompletedDate},
+	} {
+		if !pair[0].Equal(pair[1]) {
+			t.Errorf("%s date is %v, want %v", name, pair[0], pair[1])
+		}
+	}
+	if !got.Completed || got.Status != "completed" {
+		t.Errorf("the task came back with Completed %v and Status %q", got.Completed, got.Status)
+	}
+}
+
+// foreignTodo is a VTODO as another client might write it, with properties
+// and an alarm godo doesn't manage
+const foreignTodo = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Other//Other//EN\r\n" +
+	"BEGIN:VTODO\r\n" +
+	"UID:water\r\n" +
+	"DTSTAMP:20240101T000000Z\r\n" +
+	"SUMMARY:Water the plants\r\n" +
+	"DESCRIPTION:The ones on the balcony\r\n" +
+	"DTSTART;VALUE=DATE:20240301\r\n" +
+	"DUE;VALUE=DATE:20240302\r\n" +
+	"RRULE:FREQ=WEEKLY\r\n" +
+	"CATEGORIES:home,garden\r\n" +
+	"X-OTHER-COLOR:green and a long value that has to be folded over more than one line\r\n" +
+	"  to fit\r\n" +
+	"RELATED-TO;RELTYPE=SIBLING:neighbour\r\n" +
+	"STATUS:NEEDS-ACTION\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:DISPLAY\r\n" +
+	"DESCRIPTION:Reminder\r\n" +
+	"TRIGGER:-PT15M\r\n" +
+	"END:VALARM\r\n" +
+	"END:VTODO\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestTodoFromICalSkipsAlarms(t *testing.T) {
+	task, ok := todoFromICal(foreignTodo)
+	if !ok {
+		t.Fatal("the VTODO couldn't be read")
+	}
+	if task.Notes != "The ones on the balcony" {
+		t.Errorf("notes are %q, want the task's own description", task.Notes)
+	}
+	if task.Parent != "" {
+		t.Errorf("a sibling relation was read as parent %q", task.Parent)
+	}
+}
+
+func TestMergeTodoKeepsForeignProperties(t *testing.T) {
+	task, ok := todoFromICal(foreignTodo)
+	if !ok {
+		t.Fatal("the VTODO couldn't be read")
+	}
+	task.Title = "Water the plants twice"
+	task.Completed, task.Status = true, "completed"
+
+	merged := mergeTodo(foreignTodo, task, time.Now())
+	unfolded := strings.Join(unfoldICal(merged), "\n")
+	for _, want := range []string{
+		"SUMMARY:Water the plants twice",
+		"STATUS:COMPLETED",
+		"DTSTART;VALUE=DATE:20240301",
+		"DUE;VALUE=DATE:20240302",
+		"RRULE:FREQ=WEEKLY",
+		"CATEGORIES:home,garden",
+		"X-OTHER-COLOR:green and a long value that has to be folded over more than one line to fit",
+		"RELATED-TO;RELTYPE=SIBLING:neighbour",
+		"BEGIN:VALARM\nACTION:DISPLAY\nDESCRIPTION:Reminder\nTRIGGER:-PT15M\nEND:VALARM",
+	} {
+		if !strings.Contains(unfolded, want) {
+			t.Errorf("merged VTODO lost %q:\n%s", want, unfolded)
+		}
+	}
+	for _, name := range []string{"SUMMARY:", "STATUS:", "DUE", "UID:"} {
+		if count := strings.Count(unfolded, "\n"+name); count != 1 {
+			t.Errorf("merged VTODO has %d %s lines, want 1", count, name)
+		}
+	}
+
+	again, ok := todoFromICal(merged)
+	if !ok {
+		t.Fatal("the merged VTODO couldn't be read back")
+	}
+	if again.Title != task.Title || !again.Completed || !again.DueDate.Equal(task.DueDate) {
+		t.Errorf("read back %q, completed %v, due %v", again.Title, again.Completed, again.DueDate)
+	}
+}
+
+func TestMergeTodoTimedDue(t *testing.T) {
+	task, _ := todoFromICal(foreignTodo)
+	task.DueDate = time.Date(2024, 3, 2, 15, 0, 0, 0, time.UTC)
+
+	merged := strings.Join(unfoldICal(mergeTodo(foreignTodo, task, time.Now())), "\n")
+	if !strings.Contains(merged, "\nDUE:20240302T150000Z") {
+		t.Errorf("a due date with a time of day wasn't written as a DATE-TIME:\n%s", merged)
+	}
+}
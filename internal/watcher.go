@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long the tasks file must stay quiet before reloading
+const watchDebounce = 300 * time.Millisecond
+
+var (
+	ownWriteMu   sync.Mutex
+	lastOwnWrite []byte // Contents of the tasks file as godo last wrote it
+)
+
+// recordOwnWrite remembers what we wrote so the watcher can ignore it
+func recordOwnWrite(data []byte) {
+	ownWriteMu.Lock()
+	defer ownWriteMu.Unlock()
+	lastOwnWrite = data
+}
+
+// isOwnWrite reports whether the file contents match our last write
+func isOwnWrite(data []byte) bool {
+	ownWriteMu.Lock()
+	defer ownWriteMu.Unlock()
+	return lastOwnWrite != nil && bytes.Equal(lastOwnWrite, data)
+}
+
+// localWatcherOnce keeps switching back to local mode from adding watchers
+var localWatcherOnce sync.Once
+
+// ensureLocalWatcher starts the tasks file watcher unless it already runs
+func ensureLocalWatcher() {
+	localWatcherOnce.Do(func() {
+		if err := startLocalWatcher(); err != nil {
+			logError("Error starting file watcher: %v", err)
+		}
+	})
+}
+
+// startLocalWatcher reloads tasks when the active profile's tasks file
+// changes on disk, the local mode counterpart of startBackgroundSync
+func startLocalWatcher() error {
+	tasksFile, err := tasksFilePath()
+	if err != nil {
+		return err
+	}
+	tasksDir := filepath.Dir(tasksFile)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("error creating file watcher: %v", err)
+	}
+	// Watch the directory so editors that save via rename are still seen
+	if err := watcher.Add(tasksDir); err != nil {
+		watcher.Close()
+		return fmt.Errorf("error watching %s: %v", tasksDir, err)
+	}
+
+	go func() {
+		defer watcher.Close()
+		var debounce *time.Timer
+
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != tasksFile || !event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+					continue
+				}
+				if debounce != nil {
+					debounce.Stop()
+				}
+				debounce = time.AfterFunc(watchDebounce, func() {
+					reloadLocalTasks(tasksFile)
+				})
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logError("Error watching tasks file: %v", err)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// reloadLocalTasks pushes the on-disk tasks to the UI unless we wrote them
+func reloadLocalTasks(tasksFile string) {
+	if UseGoogleTasks.Load() {
+		// Switched to Google Tasks since the watcher started
+		return
+	}
+	data, err := os.ReadFile(tasksFile)
+	if err != nil || isOwnWrite(data) {
+		return
+	}
+
+	tasks, err := ImportFromLocal()
+	if err != nil {
+		logError("Error reloading tasks: %v", err)
+		return
+	}
+	recordOwnWrite(data)
+	notifyUIOfChanges(tasks)
+}
@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"os"
+	"time"
+)
+
+// welcomedFile records that the welcome task was offered, so emptying the
+// task list later doesn't bring it back
+const welcomedFile = "welcomed"
+
+// FirstRun reports whether godo has never run with this storage directory
+// before, and records that it now has. Existing task files count as a
+// previous run, so upgrading never shows the welcome.
+func FirstRun() bool {
+	flagPath, err := storageFile(welcomedFile)
+	if err != nil {
+		return false
+	}
+	if _, err := os.Stat(flagPath); err == nil {
+		return false
+	}
+	tasksPath, err := tasksFilePath()
+	if err != nil {
+		return false
+	}
+	_, statErr := os.Stat(tasksPath)
+
+	if err := os.WriteFile(flagPath, nil, 0644); err != nil {
+		return false
+	}
+	return os.IsNotExist(statErr)
+}
+
+// WelcomeTasks returns the intro task for new users, or none when
+// ShowWelcome is turned off
+func WelcomeTasks(now time.Time) []Task {
+	config := GetGlobalConfig()
+	if config == nil || !config.ShowWelcome {
+		return nil
+	}
+	return []Task{
+		{
+			Id:        generateID(),
+			Title:     "Welcome to Godo!",
+			Notes:     "This is your first task. Press 'n' to create a new task, 'r' to rename this task, or 'd' to delete it. Run 'godo init' to choose where tasks are kept, the date format and the theme.",
+			Status:    "needsAction",
+			Kind:      "tasks#task",
+			CreatedAt: now,
+			Created:   now,
+			Updated:   now,
+		},
+	}
+}
@@ -0,0 +1,55 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// terminalTitleEnabled reports whether TerminalTitle is on
+func terminalTitleEnabled() bool {
+	config := GetGlobalConfig()
+	return config != nil && config.TerminalTitle
+}
+
+// windowTitle names godo along with what is due today or overdue, e.g.
+// "godo: 3 due today, 1 overdue"
+func windowTitle(tasks []Task, now time.Time) string {
+	summary := Summarize(tasks, now)
+	switch {
+	case summary.DueToday > 0 && summary.Overdue > 0:
+		return fmt.Sprintf("godo: %d due today, %d overdue", summary.DueToday, summary.Overdue)
+	case summary.DueToday > 0:
+		return fmt.Sprintf("godo: %d due today", summary.DueToday)
+	case summary.Overdue > 0:
+		return fmt.Sprintf("godo: %d overdue", summary.Overdue)
+	}
+	return "godo"
+}
+
+// updateWindowTitle sets the terminal title when the counts in it changed
+func (m *model) updateWindowTitle() tea.Cmd {
+	if !terminalTitleEnabled() {
+		return nil
+	}
+	title := windowTitle(m.allTasks(), time.Now())
+	if title == m.windowTitle {
+		return nil
+	}
+	m.windowTitle = title
+	return tea.SetWindowTitle(title)
+}
+
+// saveTerminalTitle pushes the current title onto the terminal's title
+// stack so restoreTerminalTitle can bring it back. Terminals without a
+// title stack ignore both.
+func saveTerminalTitle() {
+	fmt.Fprint(os.Stdout, "\x1b[22;0t")
+}
+
+// restoreTerminalTitle pops the title saved by saveTerminalTitle
+func restoreTerminalTitle() {
+	fmt.Fprint(os.Stdout, "\x1b[23;0t")
+}
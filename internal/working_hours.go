@@ -0,0 +1,155 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// workingTime holds the parsed WorkingDays and WorkingHours config. The zero
+// value lets every moment count as working time.
+type workingTime struct {
+	days       [7]bool // Indexed by time.Weekday
+	start, end int     // Minutes since midnight, end 0 when hours aren't set
+}
+
+var (
+	badWorkingTimeMu sync.Mutex
+	badWorkingTime   string // Last invalid setting logged, so it's logged once
+)
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// parseWeekday reads a day name, of which only the first three letters count
+func parseWeekday(name string) (time.Weekday, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if day, ok := weekdayNames[name[:min(3, len(name))]]; ok {
+		return day, nil
+	}
+	return 0, fmt.Errorf("invalid working day %q", name)
+}
+
+// parseWorkingDays reads days such as "Mon-Fri" or "Mon,Wed,Fri". Ranges
+// may wrap around the week, as in "Sun-Thu".
+func parseWorkingDays(value string) ([7]bool, error) {
+	var days [7]bool
+	for _, part := range strings.Split(value, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		first, err := parseWeekday(from)
+		if err != nil {
+			return days, err
+		}
+		last := first
+		if isRange {
+			if last, err = parseWeekday(to); err != nil {
+				return days, err
+			}
+		}
+		for day := first; ; day = (day + 1) % 7 {
+			days[day] = true
+			if day == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// parseWorkingHours reads hours such as "9-17" or "09:00-17:30" into minutes
+// since midnight
+func parseWorkingHours(value string) (start, end int, err error) {
+	from, to, ok := strings.Cut(value, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid working hours %q, use e.g. 9-17 or 09:00-17:30", value)
+	}
+	minutes := func(s string) (int, error) {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if _, err := strconv.Atoi(s); err == nil {
+			s += ":00"
+		}
+		t, err := parseTimeOfDay(s)
+		if err != nil {
+			return 0, err
+		}
+		return t.Hour()*60 + t.Minute(), nil
+	}
+	if start, err = minutes(from); err != nil {
+		return 0, 0, err
+	}
+	if end, err = minutes(to); err != nil {
+		return 0, 0, err
+	}
+	if end <= start {
+		return 0, 0, fmt.Errorf("working hours %q end before they start", value)
+	}
+	return start, end, nil
+}
+
+// currentWorkingTime parses the working time config, falling back to every
+// moment counting when it is unset or invalid
+func currentWorkingTime() workingTime {
+	var wt workingTime
+	config := GetGlobalConfig()
+	if config == nil {
+		return wt
+	}
+
+	days, hours := strings.TrimSpace(config.WorkingDays), strings.TrimSpace(config.WorkingHours)
+	var err error
+	if days != "" {
+		wt.days, err = parseWorkingDays(days)
+	}
+	if err == nil && hours != "" {
+		wt.start, wt.end, err = parseWorkingHours(hours)
+	}
+	if err != nil {
+		badWorkingTimeMu.Lock()
+		if badWorkingTime != days+" "+hours {
+			badWorkingTime = days + " " + hours
+			logError("Ignoring working time config: %v", err)
+		}
+		badWorkingTimeMu.Unlock()
+		return workingTime{}
+	}
+	return wt
+}
+
+// workingDay reports whether work happens on t's day
+func (wt workingTime) workingDay(t time.Time) bool {
+	return wt.days == [7]bool{} || wt.days[t.Weekday()]
+}
+
+// isWorkingTime reports whether t falls on a working day within working hours
+func (wt workingTime) isWorkingTime(t time.Time) bool {
+	if !wt.workingDay(t) {
+		return false
+	}
+	if wt.end == 0 {
+		return true
+	}
+	minute := t.Hour()*60 + t.Minute()
+	return minute >= wt.start && minute < wt.end
+}
+
+// effectiveDue moves a due date that falls on a day off to the end of the
+// next working day, so weekend tasks show up as due on Monday and only turn
+// overdue once Monday's work is over. Due dates on working days stay as
+// they are.
+func (wt workingTime) effectiveDue(due time.Time) time.Time {
+	if due.IsZero() || wt.workingDay(due) {
+		return due
+	}
+	day := due
+	for i := 0; i < 7 && !wt.workingDay(day); i++ {
+		day = day.AddDate(0, 0, 1)
+	}
+	if wt.end == 0 {
+		return day
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), wt.end/60, wt.end%60, 0, 0, day.Location())
+}
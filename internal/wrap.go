@@ -0,0 +1,31 @@
+package internal
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// wrap breaks text into lines at most width columns wide, measured the way
+// the terminal draws them so wide CJK characters and emoji count double.
+// Lines break at spaces where possible, and words longer than a line, like
+// URLs, are cut at the edge. Line breaks in text are kept. A width of zero
+// or less leaves text as it is.
+func wrap(text string, width int) []string {
+	if text == "" {
+		return nil
+	}
+	if width <= 0 {
+		return strings.Split(text, "\n")
+	}
+	return strings.Split(ansi.Wrap(text, width, ""), "\n")
+}
+
+// wrapWidth is the width the details panel wraps text at: the panel width,
+// or WrapWidth when that is set and narrower
+func wrapWidth(panelWidth int) int {
+	if config := GetGlobalConfig(); config != nil && config.WrapWidth > 0 {
+		return min(config.WrapWidth, panelWidth)
+	}
+	return panelWidth
+}
@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width int
+		want  []string
+	}{
+		{
+			name:  "breaks at spaces",
+			text:  "one two three",
+			width: 7,
+			want:  []string{"one two", "three"},
+		},
+		{
+			name:  "cuts a URL longer than a line",
+			text:  "see https://example.com/a/very/long/path/that/keeps/going",
+			width: 20,
+			want:  []string{"see", "https://example.com/", "a/very/long/path/tha", "t/keeps/going"},
+		},
+		{
+			name:  "counts CJK characters as two columns",
+			text:  "日本語のテキスト",
+			width: 6,
+			want:  []string{"日本語", "のテキ", "スト"},
+		},
+		{
+			name:  "counts emoji as two columns",
+			text:  "🎉🎉🎉 party",
+			width: 4,
+			want:  []string{"🎉🎉", "🎉", "part", "y"},
+		},
+		{
+			name:  "keeps line breaks",
+			text:  "first\nsecond line",
+			width: 6,
+			want:  []string{"first", "second", "line"},
+		},
+		{
+			name:  "zero width leaves text as it is",
+			text:  "hello world",
+			width: 0,
+			want:  []string{"hello world"},
+		},
+		{
+			name:  "negative width leaves text as it is",
+			text:  "hello world",
+			width: -3,
+			want:  []string{"hello world"},
+		},
+		{
+			name:  "empty text has no lines",
+			text:  "",
+			width: 10,
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrap(tt.text, tt.width)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("wrap(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+			}
+			for _, line := range got {
+				if tt.width > 0 && ansi.StringWidth(line) > tt.width {
+					t.Errorf("line %q is %d columns wide, more than %d", line, ansi.StringWidth(line), tt.width)
+				}
+			}
+		})
+	}
+}